@@ -0,0 +1,36 @@
+// Command recompute-prices recalculates the lowest price and lowest price
+// supplier of every product from the supplier offers.
+package main
+
+import (
+	"context"
+	"log"
+
+	"diploma/internal/config"
+	"diploma/modules/product/repository/product"
+	"diploma/pkg/client/db/pg"
+)
+
+func main() {
+	ctx := context.Background()
+
+	_ = config.Load(".env")
+
+	pgConfig, err := config.NewPGConfig()
+	if err != nil {
+		log.Fatalf("failed to get pg config: %v", err)
+	}
+
+	client, err := pg.New(ctx, pgConfig.DSN())
+	if err != nil {
+		log.Fatalf("failed to create db client: %v", err)
+	}
+	defer client.Close()
+
+	changed, err := product.NewRepository(client).RecomputeLowestPrices(ctx)
+	if err != nil {
+		log.Fatalf("failed to recompute lowest prices: %v", err)
+	}
+
+	log.Printf("lowest prices recomputed, %d products changed", changed)
+}
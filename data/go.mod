@@ -3,7 +3,7 @@ module migration
 go 1.24.0
 
 require (
-	github.com/jmoiron/sqlx v1.4.0 // indirect
-	github.com/lib/pq v1.10.9 // indirect
-	golang.org/x/crypto v0.36.0 // indirect
+	github.com/jmoiron/sqlx v1.4.0
+	github.com/lib/pq v1.10.9
+	golang.org/x/crypto v0.36.0
 )
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -141,6 +142,49 @@ func loadJSON(fileName string, data interface{}) error {
 	return nil
 }
 
+// normalizeGTIN приводит штрихкод (EAN-8, UPC-A, EAN-13, GTIN-14) к числу
+// GTIN-14 и проверяет контрольную цифру — так же, как model.NormalizeGTIN
+// в основном модуле. Разные формы одного товара дают одно число.
+func normalizeGTIN(raw string) (int64, bool) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(raw))
+	switch len(digits) {
+	case 8, 12, 13, 14:
+	default:
+		return 0, false
+	}
+
+	gtin, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || gtin <= 0 {
+		return 0, false
+	}
+
+	padded := fmt.Sprintf("%014d", gtin)
+	sum := 0
+	for i := 0; i < 13; i++ {
+		d := int(padded[i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return gtin, (10-sum%10)%10 == int(padded[13]-'0')
+}
+
+// gtinValue возвращает значение для products.gtin. Неверный штрихкод
+// сохраняется как есть (БД помечает его в gtin_invalid), нечисловой — NULL.
+func gtinValue(productID int, raw string) interface{} {
+	if gtin, ok := normalizeGTIN(raw); ok {
+		return gtin
+	}
+
+	fmt.Printf("⚠️  Продукт %d: неверный GTIN %q\n", productID, raw)
+	gtin, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return nil
+	}
+	return gtin
+}
+
 func fileExists(filename string) bool {
 	_, err := os.Stat(filename)
 	return !os.IsNotExist(err)
@@ -312,7 +356,7 @@ func loadBasicProducts(db *sqlx.DB) error {
 			VALUES ($1, $2, $3, $4)
 			ON CONFLICT (id) DO UPDATE SET 
 				name = $2, image_url = $3, gtin = $4`,
-			product.ID, product.Name, product.ImageURL, product.GTIN)
+			product.ID, product.Name, product.ImageURL, gtinValue(product.ID, strconv.FormatInt(product.GTIN, 10)))
 		if err != nil {
 			return fmt.Errorf("ошибка добавления продукта %d: %v", product.ID, err)
 		}
@@ -341,15 +385,22 @@ func loadEnhancedProducts(db *sqlx.DB) error {
 					category_id = $2, 
 					subcategory_id = $3,
 					created_at = $4,
-					updated_at = $5
+					updated_at = $5,
+					brand = $6,
+					weight = $7,
+					country_of_origin = $8,
+					shelf_life_days = $9,
+					storage_conditions = $10
 				WHERE id = $1`,
 				product.ID, product.CategoryID, product.SubcategoryID,
-				createdAt, updatedAt)
+				createdAt, updatedAt,
+				nullString(product.Brand), nullString(product.Weight), nullString(product.CountryOrigin),
+				nullInt(product.ShelfLifeDays), nullString(product.StorageConditions))
 			if err != nil {
 				fmt.Printf("⚠️  Предупреждение: не удалось обновить продукт %d: %v\n", product.ID, err)
 			}
 		}
-		fmt.Printf("✅ Обновлено %d продуктов категориями\n", len(enhancedProducts))
+		fmt.Printf("✅ Обновлено %d продуктов категориями и атрибутами\n", len(enhancedProducts))
 	}
 
 	// Загружаем extended_products.json для добавления новых
@@ -364,13 +415,18 @@ func loadEnhancedProducts(db *sqlx.DB) error {
 			updatedAt, _ := time.Parse("2006-01-02T15:04:05Z", product.UpdatedAt)
 
 			_, err := db.Exec(`
-				INSERT INTO products (id, name, image_url, gtin, category_id, subcategory_id, created_at, updated_at)
-				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				INSERT INTO products (id, name, image_url, gtin, category_id, subcategory_id, created_at, updated_at,
+					brand, weight, country_of_origin, shelf_life_days, storage_conditions)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 				ON CONFLICT (id) DO UPDATE SET 
 					name = $2, image_url = $3, gtin = $4, 
-					category_id = $5, subcategory_id = $6, updated_at = $8`,
-				product.ID, product.Name, product.ImageURL, product.GTIN,
-				product.CategoryID, product.SubcategoryID, createdAt, updatedAt)
+					category_id = $5, subcategory_id = $6, updated_at = $8,
+					brand = $9, weight = $10, country_of_origin = $11,
+					shelf_life_days = $12, storage_conditions = $13`,
+				product.ID, product.Name, product.ImageURL, gtinValue(product.ID, product.GTIN),
+				product.CategoryID, product.SubcategoryID, createdAt, updatedAt,
+				nullString(product.Brand), nullString(product.Weight), nullString(product.CountryOrigin),
+				nullInt(product.ShelfLifeDays), nullString(product.StorageConditions))
 			if err != nil {
 				return fmt.Errorf("ошибка добавления расширенного продукта %d: %v", product.ID, err)
 			}
@@ -381,6 +437,23 @@ func loadEnhancedProducts(db *sqlx.DB) error {
 	return nil
 }
 
+// nullString сохраняет пустые атрибуты как NULL
+func nullString(s string) interface{} {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// nullInt сохраняет неизвестный (нулевой) срок годности как NULL
+func nullInt(n int) interface{} {
+	if n <= 0 {
+		return nil
+	}
+	return n
+}
+
 func loadProductSuppliers(db *sqlx.DB) error {
 	fmt.Println("💰 Загружаем связи продукт-поставщик...")
 
@@ -475,41 +548,12 @@ func loadPriceHistory(db *sqlx.DB) error {
 func updateProductPrices(db *sqlx.DB) error {
 	fmt.Println("🔄 Обновляем минимальные цены продуктов...")
 
-	rows, err := db.Query(`
-		SELECT ps.product_id, ps.price AS lowest_price, ps.supplier_id
-		FROM products_supplier ps
-		WHERE ps.price = (
-			SELECT MIN(price)
-			FROM products_supplier
-			WHERE product_id = ps.product_id
-		)
-		GROUP BY ps.product_id, ps.supplier_id, ps.price;
-	`)
-	if err != nil {
-		return fmt.Errorf("ошибка запроса минимальных цен: %v", err)
-	}
-	defer rows.Close()
-
-	updateCount := 0
-	updateQuery := `UPDATE products SET lowest_price = $1, lowest_supplier_id = $2 WHERE id = $3`
-
-	for rows.Next() {
-		var productID, lowestPrice, lowestSupplierID int
-
-		if err := rows.Scan(&productID, &lowestPrice, &lowestSupplierID); err != nil {
-			return fmt.Errorf("ошибка сканирования строки: %v", err)
-		}
-
-		_, err := db.Exec(updateQuery, lowestPrice, lowestSupplierID, productID)
-		if err != nil {
-			fmt.Printf("⚠️  Предупреждение: не удалось обновить цену продукта %d: %v\n", productID, err)
-		} else {
-			updateCount++
-		}
-	}
-
-	if err := rows.Err(); err != nil {
-		return fmt.Errorf("ошибка итерации строк: %v", err)
+	// lowest_price и lowest_supplier_id поддерживаются триггером на
+	// products_supplier; пересчёт нужен только для данных, загруженных до
+	// миграции с триггером
+	var updateCount int
+	if err := db.Get(&updateCount, `SELECT recompute_lowest_prices()`); err != nil {
+		return fmt.Errorf("ошибка пересчёта минимальных цен: %v", err)
 	}
 
 	fmt.Printf("✅ Обновлено %d минимальных цен\n", updateCount)
@@ -2651,6 +2651,9 @@ const docTemplate = `{
         "diploma_modules_product_handler_model.SupplierAnalytics": {
             "type": "object",
             "properties": {
+                "active": {
+                    "type": "boolean"
+                },
                 "avg_price": {
                     "type": "number"
                 },
@@ -9,6 +9,8 @@ import (
 	"diploma/modules/contract"
 	"diploma/modules/order"
 	"diploma/modules/product"
+	"diploma/modules/standing"
+	"diploma/modules/supplier"
 	"diploma/modules/user"
 	"diploma/pkg/logger"
 	"diploma/pkg/metrics"
@@ -48,6 +50,12 @@ func (a *App) Run() error {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
+	go a.standingSchedulerRun()
+	go a.contractExpiryRun()
+	go a.slotHoldExpiryRun()
+	go a.productImportRun()
+	go a.marketAggregatesRun()
+
 	go func() {
 		defer wg.Done()
 		if err := a.httpServerRun(); err != nil {
@@ -143,6 +151,9 @@ func (a *App) initHTTPServer(ctx context.Context) error {
 	cartCallbackHander := a.serviceProvider.CartHandler(ctx)
 	cart.RegisterRoutesCallback(apiGroup, cartCallbackHander)
 
+	standingHandler := a.serviceProvider.StandingHandler(ctx)
+	standing.RegisterRoutesCallback(apiGroup, standingHandler)
+
 	secureGroup := router.Group("/api")
 	secureGroup.Use(authMiddleware.AuthMiddleware())
 
@@ -157,9 +168,14 @@ func (a *App) initHTTPServer(ctx context.Context) error {
 	contractHandler := a.serviceProvider.ContractHandler(ctx)
 	contract.RegisterRoutes(secureGroup, contractHandler)
 
+	supplierHandler := a.serviceProvider.SupplierHandler(ctx)
+	supplier.RegisterRoutes(secureGroup, supplierHandler)
+
 	userHandler := a.serviceProvider.UserHandler(ctx)
 	user.RegisterRoutes(secureGroup, userHandler)
 
+	standing.RegisterRoutes(secureGroup, standingHandler)
+
 	a.httpServer = router
 	return nil
 }
@@ -169,3 +185,44 @@ func (a *App) httpServerRun() error {
 	log.Printf("HTTP server is running on %s", address)
 	return a.httpServer.Run(address)
 }
+
+// standingSchedulerRun generates orders from standing orders in the
+// background for as long as the application runs.
+func (a *App) standingSchedulerRun() {
+	ctx := context.Background()
+	interval := a.serviceProvider.StandingConfig().Interval()
+	log.Printf("Standing order scheduler is running every %s", interval)
+	a.serviceProvider.StandingService(ctx).Start(ctx, interval)
+}
+
+// contractExpiryRun expires contracts left unsigned past their expiry.
+func (a *App) contractExpiryRun() {
+	ctx := context.Background()
+	interval := a.serviceProvider.ContractConfig().ExpiryInterval()
+	log.Printf("Contract expiry is running every %s", interval)
+	a.serviceProvider.ContractService(ctx).StartExpiry(ctx, interval)
+}
+
+// slotHoldExpiryRun gives back delivery slot places of abandoned checkouts.
+func (a *App) slotHoldExpiryRun() {
+	ctx := context.Background()
+	interval := a.serviceProvider.SlotHoldConfig().ExpiryInterval()
+	log.Printf("Slot hold expiry is running every %s", interval)
+	a.serviceProvider.SupplierService(ctx).StartHoldExpiry(ctx, interval)
+}
+
+// productImportRun processes uploaded product imports in the background.
+func (a *App) productImportRun() {
+	ctx := context.Background()
+	interval := a.serviceProvider.ProductImportConfig().Interval()
+	log.Printf("Product import is running every %s", interval)
+	a.serviceProvider.ProductService(ctx).StartImports(ctx, interval)
+}
+
+// marketAggregatesRun keeps the daily market analytics aggregates up to date.
+func (a *App) marketAggregatesRun() {
+	ctx := context.Background()
+	interval := a.serviceProvider.MarketAnalyticsConfig().AggregatesInterval()
+	log.Printf("Market aggregates are refreshed every %s", interval)
+	a.serviceProvider.ProductService(ctx).StartMarketAggregates(ctx, interval)
+}
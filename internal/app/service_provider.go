@@ -26,20 +26,26 @@ import (
 	categoryRepository "diploma/modules/category/repository"
 	categoryService "diploma/modules/category/service"
 
+	cartAgreementClient "diploma/modules/cart/client/agreement"
 	cartOrderClient "diploma/modules/cart/client/order"
 	cartPaymentClient "diploma/modules/cart/client/payment"
 	cartSupplierClient "diploma/modules/cart/client/supplier"
+	cartUserClient "diploma/modules/cart/client/user"
 	cartApi "diploma/modules/cart/handler"
 	cartRedisClient "diploma/modules/cart/redis"
 	cartRepository "diploma/modules/cart/repository"
 	cartService "diploma/modules/cart/service"
 
+	supplierApi "diploma/modules/supplier/handler"
 	supplierRepository "diploma/modules/supplier/repo"
 	supplierService "diploma/modules/supplier/service"
 
+	orderContractClient "diploma/modules/order/client/contract"
 	orderProductClient "diploma/modules/order/client/product"
 	orderSupplierCleint "diploma/modules/order/client/supplier"
+	orderUserClient "diploma/modules/order/client/user"
 	orderHander "diploma/modules/order/handler"
+	orderPDF "diploma/modules/order/pdf"
 	orderRepository "diploma/modules/order/repo"
 
 	// orderProductClient "diploma/modules/order/client/product"
@@ -49,26 +55,43 @@ import (
 	userRepository "diploma/modules/user/repository"
 	userService "diploma/modules/user/service"
 
+	contractOrderClient "diploma/modules/contract/client/order"
+	contractUserClient "diploma/modules/contract/client/user"
 	contractHandler "diploma/modules/contract/handler"
+	contractPDF "diploma/modules/contract/pdf"
 	contractRepo "diploma/modules/contract/repo"
 	contractService "diploma/modules/contract/service"
 
+	standingOrderClient "diploma/modules/standing/client/order"
+	standingPaymentClient "diploma/modules/standing/client/payment"
+	standingProductClient "diploma/modules/standing/client/product"
+	standingUserClient "diploma/modules/standing/client/user"
+	standingApi "diploma/modules/standing/handler"
+	standingRepository "diploma/modules/standing/repo"
+	standingService "diploma/modules/standing/service"
+
 	"github.com/go-redis/redis/v8"
 )
 
 type serviceProvider struct {
-	pgConfig      config.PGConfig
-	jwtConfig     config.JWTConfig
-	httpConfig    config.HTTPConfig
-	swaggerConfig config.SwaggerConfig
-	paymentConfig config.PaymentConfig
-	redisConfig   config.RedisConfig
-	nctConfig     config.NCTConfig
+	pgConfig       config.PGConfig
+	jwtConfig      config.JWTConfig
+	httpConfig     config.HTTPConfig
+	swaggerConfig  config.SwaggerConfig
+	paymentConfig  config.PaymentConfig
+	redisConfig    config.RedisConfig
+	nctConfig      config.NCTConfig
+	pdfConfig      config.PDFConfig
+	standingConfig config.StandingConfig
+	contractConfig config.ContractConfig
+	importConfig   config.ProductImportConfig
+	marketConfig   config.MarketAnalyticsConfig
+	slotConfig     config.SlotHoldConfig
 
 	dbClient    db.Client
 	txManager   db.TxManager
 	redisClient []*redis.Client
-	nctClient   *nct.NCTParser
+	nctClient   productService.INCTClient
 
 	// auth
 	authRepository authService.IAuthRepository
@@ -95,18 +118,24 @@ type serviceProvider struct {
 	cartService        cartApi.ICartService
 	cartPaymentClient  cartService.IPaymentClient
 	cartRedisClient    cartService.IRedis
+	cartUserClient     cartService.IUserClient
+	cartAgreement      cartService.IAgreementClient
 	cartHanlder        *cartApi.CartHandler
 
 	// supplier
 	supplierRepository supplierService.ISupplierRepository
 	supplierService    *supplierService.SupplierService
+	supplierHandler    *supplierApi.SupplierHandler
 
 	// order
 	// orderHandler  *orderHandler.OrderHandler
 	orderRepository     orderService.IOrderRepository
 	orderSupplierCleint orderService.ISupplierClient
 	orderProductClient  orderService.IProductClient
+	orderUserClient     orderService.IUserClient
+	orderContractClient orderService.IContractService
 	orderService        *orderService.OrderService
+	orderInvoicePDF     orderHander.IInvoiceRenderer
 	orderHandler        *orderHander.OrderHandler
 
 	// user
@@ -117,7 +146,19 @@ type serviceProvider struct {
 	// contract
 	contractRepo    contractService.Repository
 	contractService *contractService.Service
+	contractPDF     contractService.IDocumentRenderer
+	contractUser    contractService.IUserClient
+	contractOrder   contractService.IOrderEvents
 	contractHandler *contractHandler.Handler
+
+	// standing order
+	standingRepository    standingService.IStandingRepository
+	standingProductClient standingService.IProductClient
+	standingOrderClient   standingService.IOrderClient
+	standingPaymentClient standingService.IPaymentClient
+	standingUserClient    standingService.IUserClient
+	standingService       *standingService.StandingService
+	standingHandler       *standingApi.StandingHandler
 }
 
 func newServiceProvider() *serviceProvider {
@@ -215,6 +256,84 @@ func (s *serviceProvider) NCTConfig() config.NCTConfig {
 	return s.nctConfig
 }
 
+func (s *serviceProvider) PDFConfig() config.PDFConfig {
+	if s.pdfConfig == nil {
+		cfg, err := config.NewPDFConfig()
+		if err != nil {
+			log.Fatalf("failed to get pdf config: %s", err.Error())
+		}
+
+		s.pdfConfig = cfg
+	}
+
+	return s.pdfConfig
+}
+
+func (s *serviceProvider) StandingConfig() config.StandingConfig {
+	if s.standingConfig == nil {
+		cfg, err := config.NewStandingConfig()
+		if err != nil {
+			log.Fatalf("failed to get standing order config: %s", err.Error())
+		}
+
+		s.standingConfig = cfg
+	}
+
+	return s.standingConfig
+}
+
+func (s *serviceProvider) ContractConfig() config.ContractConfig {
+	if s.contractConfig == nil {
+		cfg, err := config.NewContractConfig()
+		if err != nil {
+			log.Fatalf("failed to get contract config: %s", err.Error())
+		}
+
+		s.contractConfig = cfg
+	}
+
+	return s.contractConfig
+}
+
+func (s *serviceProvider) ProductImportConfig() config.ProductImportConfig {
+	if s.importConfig == nil {
+		cfg, err := config.NewProductImportConfig()
+		if err != nil {
+			log.Fatalf("failed to get product import config: %s", err.Error())
+		}
+
+		s.importConfig = cfg
+	}
+
+	return s.importConfig
+}
+
+func (s *serviceProvider) SlotHoldConfig() config.SlotHoldConfig {
+	if s.slotConfig == nil {
+		cfg, err := config.NewSlotHoldConfig()
+		if err != nil {
+			log.Fatalf("failed to get slot hold config: %s", err.Error())
+		}
+
+		s.slotConfig = cfg
+	}
+
+	return s.slotConfig
+}
+
+func (s *serviceProvider) MarketAnalyticsConfig() config.MarketAnalyticsConfig {
+	if s.marketConfig == nil {
+		cfg, err := config.NewMarketAnalyticsConfig()
+		if err != nil {
+			log.Fatalf("failed to get market analytics config: %s", err.Error())
+		}
+
+		s.marketConfig = cfg
+	}
+
+	return s.marketConfig
+}
+
 func (s *serviceProvider) DBClient(ctx context.Context) db.Client {
 	if s.dbClient == nil {
 		cl, err := pg.New(ctx, s.PGConfig().DSN())
@@ -308,9 +427,13 @@ func (s *serviceProvider) ProductRepository(ctx context.Context) productService.
 	return s.productRepository
 }
 
-func (s *serviceProvider) NewNCTParser(ctx context.Context) *nct.NCTParser {
+func (s *serviceProvider) NCTClient(_ context.Context) productService.INCTClient {
 	if s.nctClient == nil {
-		s.nctClient = nct.NewNCTParser(s.NCTConfig().BaseURL())
+		cfg := s.NCTConfig()
+		client := nct.NewClient(cfg.BaseURL(), cfg.PoolSize(), cfg.Timeout())
+		closer.Add(client.Close)
+
+		s.nctClient = nct.NewCache(client, cfg.CacheTTL())
 	}
 
 	return s.nctClient
@@ -321,7 +444,7 @@ func (s *serviceProvider) ProductService(ctx context.Context) *productService.Pr
 		s.productService = productService.NewService(
 			s.ProductRepository(ctx),
 			s.TxManager(ctx),
-			s.NewNCTParser(ctx),
+			s.NCTClient(ctx),
 		)
 	}
 
@@ -382,12 +505,20 @@ func (s *serviceProvider) SupplierRepo(ctx context.Context) supplierService.ISup
 
 func (s *serviceProvider) SupplierService(ctx context.Context) *supplierService.SupplierService {
 	if s.supplierService == nil {
-		s.supplierService = supplierService.NewService(s.SupplierRepo(ctx), s.TxManager(ctx))
+		s.supplierService = supplierService.NewService(s.SupplierRepo(ctx), s.TxManager(ctx), s.SlotHoldConfig().TTL())
 	}
 
 	return s.supplierService
 }
 
+func (s *serviceProvider) SupplierHandler(ctx context.Context) *supplierApi.SupplierHandler {
+	if s.supplierHandler == nil {
+		s.supplierHandler = supplierApi.NewHandler(s.SupplierService(ctx))
+	}
+
+	return s.supplierHandler
+}
+
 // ========= cart =========
 
 func (s *serviceProvider) CartRepo(ctx context.Context) cartService.ICartRepository {
@@ -431,9 +562,25 @@ func (s *serviceProvider) CartRedisClient(ctx context.Context) cartService.IRedi
 	return s.cartRedisClient
 }
 
+func (s *serviceProvider) CartAgreementClient(ctx context.Context) cartService.IAgreementClient {
+	if s.cartAgreement == nil {
+		s.cartAgreement = cartAgreementClient.NewClient(s.ContractService(ctx))
+	}
+
+	return s.cartAgreement
+}
+
+func (s *serviceProvider) CartUserClient(ctx context.Context) cartService.IUserClient {
+	if s.cartUserClient == nil {
+		s.cartUserClient = cartUserClient.NewClient(s.UserService(ctx))
+	}
+
+	return s.cartUserClient
+}
+
 func (s *serviceProvider) CartService(ctx context.Context) cartApi.ICartService {
 	if s.cartService == nil {
-		s.cartService = cartService.NewService(s.CartRepo(ctx), s.ProductService(ctx), s.CartSupplierClient(ctx), s.CartOrderClient(ctx), s.CartPaymentClient(ctx), s.CartRedisClient(ctx), s.TxManager(ctx))
+		s.cartService = cartService.NewService(s.CartRepo(ctx), s.ProductService(ctx), s.CartSupplierClient(ctx), s.CartOrderClient(ctx), s.CartPaymentClient(ctx), s.CartRedisClient(ctx), s.CartUserClient(ctx), s.CartAgreementClient(ctx), s.TxManager(ctx))
 	}
 
 	return s.cartService
@@ -465,6 +612,14 @@ func (s *serviceProvider) OrderProductClient(ctx context.Context) orderService.I
 	return s.orderProductClient
 }
 
+func (s *serviceProvider) OrderUserClient(ctx context.Context) orderService.IUserClient {
+	if s.orderUserClient == nil {
+		s.orderUserClient = orderUserClient.NewClient(s.UserService(ctx))
+	}
+
+	return s.orderUserClient
+}
+
 func (s *serviceProvider) OrderRepo(ctx context.Context) orderService.IOrderRepository {
 	if s.orderRepository == nil {
 		s.orderRepository = orderRepository.NewRepository(s.DBClient(ctx))
@@ -480,6 +635,7 @@ func (s *serviceProvider) OrderService(ctx context.Context) *orderService.OrderS
 			s.OrderSupplierClient(ctx),
 			s.OrderProductClient(ctx),
 			s.OrderContractClient(ctx), // ← добавь этот вызов
+			s.OrderUserClient(ctx),
 			s.TxManager(ctx),
 		)
 
@@ -490,12 +646,20 @@ func (s *serviceProvider) OrderService(ctx context.Context) *orderService.OrderS
 
 func (s *serviceProvider) OrderHandler(ctx context.Context) *orderHander.OrderHandler {
 	if s.orderHandler == nil {
-		s.orderHandler = orderHander.NewHandler(s.OrderService(ctx))
+		s.orderHandler = orderHander.NewHandler(s.OrderService(ctx), s.OrderInvoiceRenderer(ctx))
 	}
 
 	return s.orderHandler
 }
 
+func (s *serviceProvider) OrderInvoiceRenderer(ctx context.Context) orderHander.IInvoiceRenderer {
+	if s.orderInvoicePDF == nil {
+		s.orderInvoicePDF = orderPDF.NewInvoiceRenderer(s.PDFConfig().FontDir())
+	}
+
+	return s.orderInvoicePDF
+}
+
 func (s *serviceProvider) UserRepo(ctx context.Context) userService.IUserRepository {
 	if s.userRepository == nil {
 		s.userRepository = userRepository.NewRepository(s.DBClient(ctx))
@@ -529,11 +693,43 @@ func (s *serviceProvider) ContractRepo(ctx context.Context) contractService.Repo
 
 func (s *serviceProvider) ContractService(ctx context.Context) *contractService.Service {
 	if s.contractService == nil {
-		s.contractService = contractService.NewService(s.ContractRepo(ctx))
+		s.contractService = contractService.NewService(
+			s.ContractRepo(ctx),
+			s.ContractPDF(ctx),
+			s.ContractUserClient(ctx),
+			s.ContractOrderEvents(ctx),
+			s.ContractConfig().TTL(),
+			s.TxManager(ctx),
+		)
 	}
 	return s.contractService
 }
 
+func (s *serviceProvider) ContractPDF(_ context.Context) contractService.IDocumentRenderer {
+	if s.contractPDF == nil {
+		s.contractPDF = contractPDF.NewContractRenderer(s.PDFConfig().FontDir())
+	}
+	return s.contractPDF
+}
+
+// ContractOrderEvents resolves the order service on first use: the order
+// service needs the contract service, so it cannot be created before it.
+func (s *serviceProvider) ContractOrderEvents(ctx context.Context) contractService.IOrderEvents {
+	if s.contractOrder == nil {
+		s.contractOrder = contractOrderClient.NewEvents(func() contractOrderClient.IOrderService {
+			return s.OrderService(ctx)
+		})
+	}
+	return s.contractOrder
+}
+
+func (s *serviceProvider) ContractUserClient(ctx context.Context) contractService.IUserClient {
+	if s.contractUser == nil {
+		s.contractUser = contractUserClient.NewClient(s.UserService(ctx))
+	}
+	return s.contractUser
+}
+
 func (s *serviceProvider) ContractHandler(ctx context.Context) *contractHandler.Handler {
 	if s.contractHandler == nil {
 		s.contractHandler = contractHandler.NewHandler(s.ContractService(ctx))
@@ -542,8 +738,74 @@ func (s *serviceProvider) ContractHandler(ctx context.Context) *contractHandler.
 }
 
 func (s *serviceProvider) OrderContractClient(ctx context.Context) orderService.IContractService {
-	if s.contractService == nil {
-		s.contractService = contractService.NewService(s.ContractRepo(ctx)) // или что-то подобное
+	if s.orderContractClient == nil {
+		s.orderContractClient = orderContractClient.NewClient(s.ContractService(ctx))
 	}
-	return s.contractService
+	return s.orderContractClient
+}
+
+// ========= standing order =========
+
+func (s *serviceProvider) StandingRepo(ctx context.Context) standingService.IStandingRepository {
+	if s.standingRepository == nil {
+		s.standingRepository = standingRepository.NewRepository(s.DBClient(ctx))
+	}
+
+	return s.standingRepository
+}
+
+func (s *serviceProvider) StandingProductClient(ctx context.Context) standingService.IProductClient {
+	if s.standingProductClient == nil {
+		s.standingProductClient = standingProductClient.NewClient(s.ProductService(ctx))
+	}
+
+	return s.standingProductClient
+}
+
+func (s *serviceProvider) StandingOrderClient(ctx context.Context) standingService.IOrderClient {
+	if s.standingOrderClient == nil {
+		s.standingOrderClient = standingOrderClient.NewClient(s.OrderService(ctx))
+	}
+
+	return s.standingOrderClient
+}
+
+func (s *serviceProvider) StandingPaymentClient(ctx context.Context) standingService.IPaymentClient {
+	if s.standingPaymentClient == nil {
+		cfg := s.PaymentConfig()
+		s.standingPaymentClient = standingPaymentClient.NewPaymentClient(cfg.CheckoutURL(), cfg.RecurringURL(), cfg.MerchantID(), cfg.MerchantPassword(), cfg.ConsentCallbackURL())
+	}
+
+	return s.standingPaymentClient
+}
+
+func (s *serviceProvider) StandingUserClient(ctx context.Context) standingService.IUserClient {
+	if s.standingUserClient == nil {
+		s.standingUserClient = standingUserClient.NewClient(s.UserService(ctx))
+	}
+
+	return s.standingUserClient
+}
+
+func (s *serviceProvider) StandingService(ctx context.Context) *standingService.StandingService {
+	if s.standingService == nil {
+		s.standingService = standingService.NewService(
+			s.StandingRepo(ctx),
+			s.StandingProductClient(ctx),
+			s.StandingOrderClient(ctx),
+			s.StandingPaymentClient(ctx),
+			s.StandingUserClient(ctx),
+			s.TxManager(ctx),
+		)
+	}
+
+	return s.standingService
+}
+
+func (s *serviceProvider) StandingHandler(ctx context.Context) *standingApi.StandingHandler {
+	if s.standingHandler == nil {
+		s.standingHandler = standingApi.NewHandler(s.StandingService(ctx))
+	}
+
+	return s.standingHandler
 }
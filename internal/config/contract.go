@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	contractTTLEnv            = "CONTRACT_TTL"
+	contractExpiryIntervalEnv = "CONTRACT_EXPIRY_INTERVAL"
+
+	defaultContractTTL            = "168h"
+	defaultContractExpiryInterval = "10m"
+)
+
+type ContractConfig interface {
+	// TTL is how long a contract may stay unsigned before it expires.
+	TTL() time.Duration
+	// ExpiryInterval is how often expired contracts are looked for.
+	ExpiryInterval() time.Duration
+}
+
+type contractConfig struct {
+	ttl            time.Duration
+	expiryInterval time.Duration
+}
+
+func NewContractConfig() (ContractConfig, error) {
+	ttl, err := time.ParseDuration(GetEnv(contractTTLEnv, defaultContractTTL))
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid %s", contractTTLEnv)
+	}
+
+	interval, err := time.ParseDuration(GetEnv(contractExpiryIntervalEnv, defaultContractExpiryInterval))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s", contractExpiryIntervalEnv)
+	}
+
+	return &contractConfig{
+		ttl:            ttl,
+		expiryInterval: interval,
+	}, nil
+}
+
+func (c *contractConfig) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *contractConfig) ExpiryInterval() time.Duration {
+	return c.expiryInterval
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	productImportIntervalEnv     = "PRODUCT_IMPORT_INTERVAL"
+	defaultProductImportInterval = "10s"
+)
+
+type ProductImportConfig interface {
+	// Interval is how often the worker processes uploaded product imports.
+	Interval() time.Duration
+}
+
+type productImportConfig struct {
+	interval time.Duration
+}
+
+func NewProductImportConfig() (ProductImportConfig, error) {
+	interval, err := time.ParseDuration(GetEnv(productImportIntervalEnv, defaultProductImportInterval))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s", productImportIntervalEnv)
+	}
+
+	return &productImportConfig{
+		interval: interval,
+	}, nil
+}
+
+func (c *productImportConfig) Interval() time.Duration {
+	return c.interval
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	marketAggregatesIntervalEnv     = "MARKET_AGGREGATES_INTERVAL"
+	defaultMarketAggregatesInterval = "15m"
+)
+
+type MarketAnalyticsConfig interface {
+	// AggregatesInterval is how often the daily market aggregates are refreshed.
+	AggregatesInterval() time.Duration
+}
+
+type marketAnalyticsConfig struct {
+	aggregatesInterval time.Duration
+}
+
+func NewMarketAnalyticsConfig() (MarketAnalyticsConfig, error) {
+	interval, err := time.ParseDuration(GetEnv(marketAggregatesIntervalEnv, defaultMarketAggregatesInterval))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s", marketAggregatesIntervalEnv)
+	}
+
+	return &marketAnalyticsConfig{
+		aggregatesInterval: interval,
+	}, nil
+}
+
+func (c *marketAnalyticsConfig) AggregatesInterval() time.Duration {
+	return c.aggregatesInterval
+}
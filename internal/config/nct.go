@@ -3,18 +3,36 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 )
 
 const (
-	nctBaseURLEnv = "NCT_BASE_URL"
+	nctBaseURLEnv  = "NCT_BASE_URL"
+	nctPoolSizeEnv = "NCT_POOL_SIZE"
+	nctTimeoutEnv  = "NCT_TIMEOUT"
+	nctCacheTTLEnv = "NCT_CACHE_TTL"
+
+	defaultNCTPoolSize = "2"
+	defaultNCTTimeout  = "30s"
+	defaultNCTCacheTTL = "24h"
 )
 
 type NCTConfig interface {
 	BaseURL() string
+	// PoolSize is the number of browser pages used for lookups at once.
+	PoolSize() int
+	// Timeout bounds each browser operation of a lookup.
+	Timeout() time.Duration
+	// CacheTTL is how long lookup results are kept per GTIN.
+	CacheTTL() time.Duration
 }
 
 type nctConfig struct {
-	baseURL string
+	baseURL  string
+	poolSize int
+	timeout  time.Duration
+	cacheTTL time.Duration
 }
 
 func NewNCTConfig() (NCTConfig, error) {
@@ -23,11 +41,41 @@ func NewNCTConfig() (NCTConfig, error) {
 		return nil, fmt.Errorf("NCT base URL is not set")
 	}
 
+	poolSize, err := strconv.Atoi(GetEnv(nctPoolSizeEnv, defaultNCTPoolSize))
+	if err != nil || poolSize <= 0 {
+		return nil, fmt.Errorf("invalid %s", nctPoolSizeEnv)
+	}
+
+	timeout, err := time.ParseDuration(GetEnv(nctTimeoutEnv, defaultNCTTimeout))
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("invalid %s", nctTimeoutEnv)
+	}
+
+	cacheTTL, err := time.ParseDuration(GetEnv(nctCacheTTLEnv, defaultNCTCacheTTL))
+	if err != nil || cacheTTL < 0 {
+		return nil, fmt.Errorf("invalid %s", nctCacheTTLEnv)
+	}
+
 	return &nctConfig{
-		baseURL: baseURL,
+		baseURL:  baseURL,
+		poolSize: poolSize,
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
 	}, nil
 }
 
 func (c *nctConfig) BaseURL() string {
 	return c.baseURL
 }
+
+func (c *nctConfig) PoolSize() int {
+	return c.poolSize
+}
+
+func (c *nctConfig) Timeout() time.Duration {
+	return c.timeout
+}
+
+func (c *nctConfig) CacheTTL() time.Duration {
+	return c.cacheTTL
+}
@@ -10,6 +10,10 @@ const (
 	fondyMerchantIDEnvName       = "FONDY_MERCHANT_ID"
 	fondyMerchantPasswordEnvName = "FONDY_MERCHANT_PASSWORD"
 	fondyCallbackURLEnvName      = "FONDY_CALLBACK_URL"
+
+	fondyRecurringURLEnvName       = "FONDY_RECURRING_URL"
+	fondyConsentCallbackURLEnvName = "FONDY_CONSENT_CALLBACK_URL"
+	defaultFondyRecurringURL       = "https://pay.fondy.eu/api/recurring"
 )
 
 type PaymentConfig interface {
@@ -17,6 +21,11 @@ type PaymentConfig interface {
 	MerchantID() string
 	MerchantPassword() string
 	CallbackURL() string
+	// RecurringURL is the endpoint charging a saved card token.
+	RecurringURL() string
+	// ConsentCallbackURL receives the card token of standing order
+	// payment consents. Consents cannot be requested when it is empty.
+	ConsentCallbackURL() string
 }
 
 type paymentConfig struct {
@@ -24,6 +33,9 @@ type paymentConfig struct {
 	merchantID       string
 	merchantPassword string
 	callbackURL      string
+
+	recurringURL       string
+	consentCallbackURL string
 }
 
 func NewPaymentConfig() (PaymentConfig, error) {
@@ -50,6 +62,9 @@ func NewPaymentConfig() (PaymentConfig, error) {
 		merchantID:       merchantID,
 		merchantPassword: merchantPassword,
 		callbackURL:      callbackURL,
+
+		recurringURL:       GetEnv(fondyRecurringURLEnvName, defaultFondyRecurringURL),
+		consentCallbackURL: os.Getenv(fondyConsentCallbackURLEnvName),
 	}, nil
 }
 
@@ -68,3 +83,11 @@ func (cfg *paymentConfig) MerchantPassword() string {
 func (cfg *paymentConfig) CallbackURL() string {
 	return cfg.callbackURL
 }
+
+func (cfg *paymentConfig) RecurringURL() string {
+	return cfg.recurringURL
+}
+
+func (cfg *paymentConfig) ConsentCallbackURL() string {
+	return cfg.consentCallbackURL
+}
@@ -0,0 +1,27 @@
+package config
+
+const (
+	pdfFontDirEnv     = "PDF_FONT_DIR"
+	defaultPDFFontDir = "/usr/share/fonts/truetype/dejavu"
+)
+
+type PDFConfig interface {
+	FontDir() string
+}
+
+type pdfConfig struct {
+	fontDir string
+}
+
+// NewPDFConfig returns the configuration used to render PDF documents.
+// The font directory must contain DejaVuSans.ttf and DejaVuSans-Bold.ttf,
+// which are needed to print Cyrillic text.
+func NewPDFConfig() (PDFConfig, error) {
+	return &pdfConfig{
+		fontDir: GetEnv(pdfFontDirEnv, defaultPDFFontDir),
+	}, nil
+}
+
+func (c *pdfConfig) FontDir() string {
+	return c.fontDir
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	slotHoldTTLEnv            = "SLOT_HOLD_TTL"
+	slotHoldExpiryIntervalEnv = "SLOT_HOLD_EXPIRY_INTERVAL"
+
+	defaultSlotHoldTTL            = "30m"
+	defaultSlotHoldExpiryInterval = "1m"
+)
+
+type SlotHoldConfig interface {
+	// TTL is how long a delivery slot place reserved at checkout waits for
+	// the payment.
+	TTL() time.Duration
+	// ExpiryInterval is how often expired holds are given back.
+	ExpiryInterval() time.Duration
+}
+
+type slotHoldConfig struct {
+	ttl            time.Duration
+	expiryInterval time.Duration
+}
+
+func NewSlotHoldConfig() (SlotHoldConfig, error) {
+	ttl, err := time.ParseDuration(GetEnv(slotHoldTTLEnv, defaultSlotHoldTTL))
+	if err != nil || ttl <= 0 {
+		return nil, fmt.Errorf("invalid %s", slotHoldTTLEnv)
+	}
+
+	interval, err := time.ParseDuration(GetEnv(slotHoldExpiryIntervalEnv, defaultSlotHoldExpiryInterval))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s", slotHoldExpiryIntervalEnv)
+	}
+
+	return &slotHoldConfig{
+		ttl:            ttl,
+		expiryInterval: interval,
+	}, nil
+}
+
+func (c *slotHoldConfig) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *slotHoldConfig) ExpiryInterval() time.Duration {
+	return c.expiryInterval
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	standingIntervalEnv     = "STANDING_ORDER_INTERVAL"
+	defaultStandingInterval = "1m"
+)
+
+type StandingConfig interface {
+	// Interval is how often the scheduler looks for due standing orders.
+	Interval() time.Duration
+}
+
+type standingConfig struct {
+	interval time.Duration
+}
+
+func NewStandingConfig() (StandingConfig, error) {
+	interval, err := time.ParseDuration(GetEnv(standingIntervalEnv, defaultStandingInterval))
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("invalid %s", standingIntervalEnv)
+	}
+
+	return &standingConfig{
+		interval: interval,
+	}, nil
+}
+
+func (c *standingConfig) Interval() time.Duration {
+	return c.interval
+}
@@ -0,0 +1,30 @@
+package testutils
+
+import (
+	"context"
+
+	"diploma/pkg/client/db"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type inTxKey struct{}
+
+// MockTxManager runs the handler in place of a transaction. The error set
+// on the ReadCommitted expectation is returned without running it.
+type MockTxManager struct {
+	mock.Mock
+}
+
+func (m *MockTxManager) ReadCommitted(ctx context.Context, h db.Handler) error {
+	args := m.Called(ctx, h)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return h(context.WithValue(ctx, inTxKey{}, true))
+}
+
+// InTx reports whether ctx belongs to a MockTxManager transaction.
+func InTx(ctx context.Context) bool {
+	return ctx.Value(inTxKey{}) != nil
+}
@@ -0,0 +1,27 @@
+package testutils
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// AssertSQL asserts that the builder generates the expected query and
+// arguments. Queries are compared the way NewMockDB matches them, ignoring
+// differences in whitespace.
+func AssertSQL(t *testing.T, builder sq.Sqlizer, expected string, expectedArgs ...interface{}) {
+	t.Helper()
+
+	query, args, err := builder.ToSql()
+	require.NoError(t, err)
+
+	assert.NoError(t, sqlmock.QueryMatcherEqual.Match(expected, query))
+	if len(expectedArgs) == 0 {
+		assert.Empty(t, args)
+		return
+	}
+	assert.Equal(t, expectedArgs, args)
+}
@@ -0,0 +1,28 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Last issued invoice number per supplier. Numbers are allocated by
+-- incrementing this row, so they are sequential and never reused.
+CREATE TABLE invoice_counters (
+    supplier_id INTEGER PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE invoices (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL UNIQUE,
+    supplier_id INTEGER NOT NULL,
+    number INTEGER NOT NULL,
+    issued_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(user_id) ON DELETE CASCADE,
+    UNIQUE (supplier_id, number)
+);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS invoice_counters;
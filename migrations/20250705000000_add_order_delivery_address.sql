@@ -0,0 +1,17 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- address_id is only a reference to the address book entry picked at
+-- checkout; the entry may later be edited or deleted, so there is no FK.
+-- delivery_address keeps the text as it was when the order was placed.
+ALTER TABLE orders
+    ADD COLUMN address_id INTEGER,
+    ADD COLUMN delivery_address TEXT NOT NULL DEFAULT '';
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS delivery_address,
+    DROP COLUMN IF EXISTS address_id;
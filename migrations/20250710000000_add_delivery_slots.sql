@@ -0,0 +1,39 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Weekly delivery windows of a supplier. Times are minutes from midnight,
+-- weekday follows Go/PostgreSQL DOW numbering (0 = Sunday).
+CREATE TABLE delivery_slots (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL,
+    weekday SMALLINT NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_minute INTEGER NOT NULL CHECK (start_minute BETWEEN 0 AND 1439),
+    end_minute INTEGER NOT NULL CHECK (end_minute BETWEEN 1 AND 1440),
+    cutoff_hours INTEGER NOT NULL DEFAULT 0 CHECK (cutoff_hours >= 0),
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(user_id) ON DELETE CASCADE,
+    CHECK (end_minute > start_minute)
+);
+
+CREATE INDEX idx_delivery_slots_supplier_id ON delivery_slots(supplier_id);
+
+-- Number of orders booked into a slot on a given date.
+CREATE TABLE delivery_slot_reservations (
+    slot_id INTEGER NOT NULL,
+    delivery_date DATE NOT NULL,
+    reserved INTEGER NOT NULL DEFAULT 0 CHECK (reserved >= 0),
+    PRIMARY KEY (slot_id, delivery_date),
+    FOREIGN KEY (slot_id) REFERENCES delivery_slots(id) ON DELETE CASCADE
+);
+
+ALTER TABLE orders ADD COLUMN delivery_slot_id INTEGER REFERENCES delivery_slots(id);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_slot_id;
+DROP TABLE IF EXISTS delivery_slot_reservations;
+DROP TABLE IF EXISTS delivery_slots;
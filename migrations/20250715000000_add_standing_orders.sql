@@ -0,0 +1,60 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Recurring order templates. Weekdays is a bit mask with bit 0 = Sunday,
+-- run_minute is minutes from local midnight. As on orders, the delivery
+-- address is a snapshot and address_id has no FK.
+CREATE TABLE standing_orders (
+    id SERIAL PRIMARY KEY,
+    customer_id INTEGER NOT NULL,
+    supplier_id INTEGER NOT NULL,
+    schedule_kind VARCHAR(16) NOT NULL CHECK (schedule_kind IN ('daily', 'weekdays', 'weekly')),
+    weekdays SMALLINT NOT NULL DEFAULT 0 CHECK (weekdays BETWEEN 0 AND 127),
+    run_minute INTEGER NOT NULL CHECK (run_minute BETWEEN 0 AND 1439),
+    payment_method VARCHAR(16) NOT NULL CHECK (payment_method IN ('invoice', 'saved_consent')),
+    payment_token TEXT NOT NULL DEFAULT '',
+    consent_at TIMESTAMP,
+    address_id INTEGER,
+    delivery_address TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused')),
+    skip_next BOOLEAN NOT NULL DEFAULT FALSE,
+    next_run_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (customer_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_standing_orders_customer_id ON standing_orders(customer_id);
+CREATE INDEX idx_standing_orders_due ON standing_orders(next_run_at) WHERE status = 'active';
+
+CREATE TABLE standing_order_items (
+    standing_order_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (standing_order_id, product_id),
+    FOREIGN KEY (standing_order_id) REFERENCES standing_orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- One row per scheduled run, whether it produced an order or not.
+CREATE TABLE standing_order_runs (
+    id SERIAL PRIMARY KEY,
+    standing_order_id INTEGER NOT NULL,
+    scheduled_for TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('created', 'skipped', 'failed')),
+    order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (standing_order_id) REFERENCES standing_orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_standing_order_runs_standing_order_id ON standing_order_runs(standing_order_id);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS standing_order_runs;
+DROP TABLE IF EXISTS standing_order_items;
+DROP TABLE IF EXISTS standing_orders;
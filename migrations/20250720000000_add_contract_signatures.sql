@@ -0,0 +1,38 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Public keys users sign contracts with. Private keys are never stored,
+-- including for keys issued by the server.
+CREATE TABLE signing_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    public_key TEXT NOT NULL,
+    algorithm VARCHAR(16) NOT NULL DEFAULT 'ed25519',
+    issued_by_server BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_signing_keys_active_user ON signing_keys(user_id) WHERE revoked_at IS NULL;
+
+-- content_hash is the hex SHA-256 of the canonical contract text; the
+-- signatures are base64 ed25519 signatures over that digest.
+ALTER TABLE contracts
+    ADD COLUMN content_hash TEXT NOT NULL DEFAULT '',
+    ADD COLUMN supplier_key_id INTEGER REFERENCES signing_keys(id),
+    ADD COLUMN customer_key_id INTEGER REFERENCES signing_keys(id),
+    ADD COLUMN supplier_signed_at TIMESTAMP,
+    ADD COLUMN customer_signed_at TIMESTAMP;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE contracts
+    DROP COLUMN IF EXISTS customer_signed_at,
+    DROP COLUMN IF EXISTS supplier_signed_at,
+    DROP COLUMN IF EXISTS customer_key_id,
+    DROP COLUMN IF EXISTS supplier_key_id,
+    DROP COLUMN IF EXISTS content_hash;
+DROP TABLE IF EXISTS signing_keys;
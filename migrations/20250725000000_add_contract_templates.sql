@@ -0,0 +1,43 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Contract templates managed by admins. supplier_id NULL marks a global
+-- template. At most one default exists per supplier and one global default.
+CREATE TABLE contract_templates (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER,
+    name VARCHAR(255) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(user_id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_contract_templates_default
+    ON contract_templates (COALESCE(supplier_id, 0)) WHERE is_default;
+
+-- Every edit of a template body is a new version; versions are immutable.
+CREATE TABLE contract_template_versions (
+    template_id INTEGER NOT NULL,
+    version INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    created_by INTEGER NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id, version),
+    FOREIGN KEY (template_id) REFERENCES contract_templates(id) ON DELETE CASCADE
+);
+
+-- The template version a contract was rendered from; NULL for the
+-- built-in template.
+ALTER TABLE contracts
+    ADD COLUMN template_id INTEGER REFERENCES contract_templates(id) ON DELETE SET NULL,
+    ADD COLUMN template_version INTEGER;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE contracts
+    DROP COLUMN IF EXISTS template_version,
+    DROP COLUMN IF EXISTS template_id;
+DROP TABLE IF EXISTS contract_template_versions;
+DROP TABLE IF EXISTS contract_templates;
@@ -0,0 +1,22 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- PDF of a completed contract, frozen when the last party signs.
+-- contracts.pdf_sha256 repeats the hash so it can be shown without
+-- loading the document.
+CREATE TABLE contract_documents (
+    contract_id INTEGER PRIMARY KEY,
+    pdf BYTEA NOT NULL,
+    sha256 TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (contract_id) REFERENCES contracts(id) ON DELETE CASCADE
+);
+
+ALTER TABLE contracts ADD COLUMN pdf_sha256 TEXT;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE contracts DROP COLUMN IF EXISTS pdf_sha256;
+DROP TABLE IF EXISTS contract_documents;
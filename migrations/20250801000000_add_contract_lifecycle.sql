@@ -0,0 +1,32 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Status values 4, 5 and 6 are rejected, amended and expired.
+-- An amendment is a new contract row for the same order pointing at the
+-- contract it replaces; the replaced contract keeps its signatures for
+-- the record but can no longer be signed.
+ALTER TABLE contracts
+    ADD COLUMN previous_id INTEGER REFERENCES contracts(id),
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN expires_at TIMESTAMP,
+    ADD COLUMN rejected_by INTEGER,
+    ADD COLUMN reject_reason TEXT,
+    ADD COLUMN rejected_at TIMESTAMP;
+
+-- contracts created before expiry existed keep expires_at NULL and
+-- never expire
+CREATE INDEX idx_contracts_expires_at ON contracts (expires_at)
+    WHERE expires_at IS NOT NULL;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_contracts_expires_at;
+ALTER TABLE contracts
+    DROP COLUMN IF EXISTS rejected_at,
+    DROP COLUMN IF EXISTS reject_reason,
+    DROP COLUMN IF EXISTS rejected_by,
+    DROP COLUMN IF EXISTS expires_at,
+    DROP COLUMN IF EXISTS version,
+    DROP COLUMN IF EXISTS previous_id;
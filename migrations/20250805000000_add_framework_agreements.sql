@@ -0,0 +1,44 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- A framework agreement is proposed by the supplier ('pending'), becomes
+-- 'active' when the customer accepts it and ends when either party
+-- terminates it or valid_to passes.
+CREATE TABLE framework_agreements (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES users(id),
+    customer_id INTEGER NOT NULL REFERENCES users(id),
+    valid_from DATE NOT NULL,
+    valid_to DATE NOT NULL,
+    -- credit terms: days to pay after delivery and the outstanding amount
+    -- allowed, in tenge; 0 means prepayment and no limit respectively
+    payment_days INTEGER NOT NULL DEFAULT 0,
+    credit_limit INTEGER NOT NULL DEFAULT 0,
+    status TEXT NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    accepted_at TIMESTAMP,
+    terminated_at TIMESTAMP,
+    CHECK (valid_from <= valid_to),
+    CHECK (payment_days >= 0 AND credit_limit >= 0),
+    CHECK (status IN ('pending', 'active', 'terminated'))
+);
+
+CREATE INDEX idx_framework_agreements_parties
+    ON framework_agreements (supplier_id, customer_id, status);
+
+CREATE TABLE framework_agreement_prices (
+    agreement_id INTEGER NOT NULL REFERENCES framework_agreements(id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    price INTEGER NOT NULL CHECK (price > 0),
+    PRIMARY KEY (agreement_id, product_id)
+);
+
+ALTER TABLE contracts ADD COLUMN agreement_id INTEGER REFERENCES framework_agreements(id);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE contracts DROP COLUMN IF EXISTS agreement_id;
+DROP TABLE IF EXISTS framework_agreement_prices;
+DROP TABLE IF EXISTS framework_agreements;
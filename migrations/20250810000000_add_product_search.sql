@@ -0,0 +1,36 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Product names mix Russian, Kazakh and Latin brand names. The russian
+-- snowball stemmer handles Russian and Latin (english_stem) words but
+-- mangles Kazakh ones, so search_vector also keeps every word unstemmed
+-- via the simple configuration. ё is folded to е on both sides.
+CREATE TEXT SEARCH CONFIGURATION product_search (COPY = russian);
+
+ALTER TABLE products
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('product_search'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
+
+-- trigram index for typo-tolerant matching (word_similarity, <% operator)
+CREATE INDEX idx_products_name_trgm
+    ON products USING GIN (translate(lower(name), 'ё', 'е') gin_trgm_ops);
+
+CREATE INDEX idx_products_gtin ON products (gtin);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_products_gtin;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+DROP TEXT SEARCH CONFIGURATION IF EXISTS product_search;
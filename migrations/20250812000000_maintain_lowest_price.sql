@@ -0,0 +1,90 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- products.lowest_price and products.lowest_supplier_id mirror the cheapest
+-- products_supplier row of the product (ties go to the lower supplier id)
+-- and are NULL when nobody offers it. They are kept in sync by a trigger so
+-- that every writer - the API, imports and the seeding script - agrees.
+
+-- cheapest offer of a product
+CREATE INDEX IF NOT EXISTS idx_products_supplier_product_price
+    ON products_supplier (product_id, price, supplier_id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_lowest_price(p_product_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    v_price INTEGER;
+    v_supplier_id INTEGER;
+BEGIN
+    SELECT price, supplier_id INTO v_price, v_supplier_id
+    FROM products_supplier
+    WHERE product_id = p_product_id
+    ORDER BY price, supplier_id
+    LIMIT 1;
+
+    UPDATE products
+    SET lowest_price = v_price, lowest_supplier_id = v_supplier_id
+    WHERE id = p_product_id
+      AND (lowest_price, lowest_supplier_id) IS DISTINCT FROM (v_price, v_supplier_id);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION products_supplier_lowest_price() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM refresh_product_lowest_price(NEW.product_id);
+    END IF;
+    IF TG_OP = 'DELETE' OR (TG_OP = 'UPDATE' AND OLD.product_id <> NEW.product_id) THEN
+        PERFORM refresh_product_lowest_price(OLD.product_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trg_products_supplier_lowest_price
+    AFTER INSERT OR UPDATE OR DELETE ON products_supplier
+    FOR EACH ROW EXECUTE FUNCTION products_supplier_lowest_price();
+
+-- Recomputes every product at once; returns the number of products changed.
+-- Used by cmd/recompute-prices and the seeding script.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION recompute_lowest_prices() RETURNS INTEGER AS $$
+DECLARE
+    v_changed INTEGER;
+BEGIN
+    UPDATE products p
+    SET lowest_price = l.price, lowest_supplier_id = l.supplier_id
+    FROM (
+        SELECT p2.id, o.price, o.supplier_id
+        FROM products p2
+        LEFT JOIN LATERAL (
+            SELECT price, supplier_id
+            FROM products_supplier
+            WHERE product_id = p2.id
+            ORDER BY price, supplier_id
+            LIMIT 1
+        ) o ON TRUE
+    ) l
+    WHERE p.id = l.id
+      AND (p.lowest_price, p.lowest_supplier_id) IS DISTINCT FROM (l.price, l.supplier_id);
+
+    GET DIAGNOSTICS v_changed = ROW_COUNT;
+    RETURN v_changed;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+SELECT recompute_lowest_prices();
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_products_supplier_product_price;
+DROP TRIGGER IF EXISTS trg_products_supplier_lowest_price ON products_supplier;
+DROP FUNCTION IF EXISTS recompute_lowest_prices();
+DROP FUNCTION IF EXISTS products_supplier_lowest_price();
+DROP FUNCTION IF EXISTS refresh_product_lowest_price(INTEGER);
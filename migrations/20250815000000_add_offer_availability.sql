@@ -0,0 +1,114 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Suppliers deactivate an offer instead of deleting it so that its price
+-- history and past orders keep pointing at it.
+ALTER TABLE products_supplier
+    ADD COLUMN is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- only available offers count towards the lowest price
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_lowest_price(p_product_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    v_price INTEGER;
+    v_supplier_id INTEGER;
+BEGIN
+    SELECT price, supplier_id INTO v_price, v_supplier_id
+    FROM products_supplier
+    WHERE product_id = p_product_id AND is_available
+    ORDER BY price, supplier_id
+    LIMIT 1;
+
+    UPDATE products
+    SET lowest_price = v_price, lowest_supplier_id = v_supplier_id
+    WHERE id = p_product_id
+      AND (lowest_price, lowest_supplier_id) IS DISTINCT FROM (v_price, v_supplier_id);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION recompute_lowest_prices() RETURNS INTEGER AS $$
+DECLARE
+    v_changed INTEGER;
+BEGIN
+    UPDATE products p
+    SET lowest_price = l.price, lowest_supplier_id = l.supplier_id
+    FROM (
+        SELECT p2.id, o.price, o.supplier_id
+        FROM products p2
+        LEFT JOIN LATERAL (
+            SELECT price, supplier_id
+            FROM products_supplier
+            WHERE product_id = p2.id AND is_available
+            ORDER BY price, supplier_id
+            LIMIT 1
+        ) o ON TRUE
+    ) l
+    WHERE p.id = l.id
+      AND (p.lowest_price, p.lowest_supplier_id) IS DISTINCT FROM (l.price, l.supplier_id);
+
+    GET DIAGNOSTICS v_changed = ROW_COUNT;
+    RETURN v_changed;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_lowest_price(p_product_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    v_price INTEGER;
+    v_supplier_id INTEGER;
+BEGIN
+    SELECT price, supplier_id INTO v_price, v_supplier_id
+    FROM products_supplier
+    WHERE product_id = p_product_id
+    ORDER BY price, supplier_id
+    LIMIT 1;
+
+    UPDATE products
+    SET lowest_price = v_price, lowest_supplier_id = v_supplier_id
+    WHERE id = p_product_id
+      AND (lowest_price, lowest_supplier_id) IS DISTINCT FROM (v_price, v_supplier_id);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION recompute_lowest_prices() RETURNS INTEGER AS $$
+DECLARE
+    v_changed INTEGER;
+BEGIN
+    UPDATE products p
+    SET lowest_price = l.price, lowest_supplier_id = l.supplier_id
+    FROM (
+        SELECT p2.id, o.price, o.supplier_id
+        FROM products p2
+        LEFT JOIN LATERAL (
+            SELECT price, supplier_id
+            FROM products_supplier
+            WHERE product_id = p2.id
+            ORDER BY price, supplier_id
+            LIMIT 1
+        ) o ON TRUE
+    ) l
+    WHERE p.id = l.id
+      AND (p.lowest_price, p.lowest_supplier_id) IS DISTINCT FROM (l.price, l.supplier_id);
+
+    GET DIAGNOSTICS v_changed = ROW_COUNT;
+    RETURN v_changed;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+ALTER TABLE products_supplier
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS is_available;
+
+SELECT recompute_lowest_prices();
@@ -0,0 +1,52 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- stock is optional: NULL means the supplier does not report it
+ALTER TABLE products_supplier ADD COLUMN stock INTEGER CHECK (stock >= 0);
+
+-- A bulk offer import uploaded by a supplier. Rows are processed in the
+-- background; the import is finished once none is pending or waiting for
+-- an NCT lookup.
+CREATE TABLE product_imports (
+    id SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES users(id),
+    file_name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX idx_product_imports_supplier ON product_imports (supplier_id, created_at DESC);
+
+-- status: 'pending' - not processed yet, 'lookup' - GTIN unknown locally,
+-- queued for NCT lookup, 'imported', 'failed' (see error)
+CREATE TABLE product_import_rows (
+    import_id INTEGER NOT NULL REFERENCES product_imports(id) ON DELETE CASCADE,
+    row_no INTEGER NOT NULL,
+    raw_gtin TEXT NOT NULL DEFAULT '',
+    gtin BIGINT,
+    price INTEGER,
+    sell_amount INTEGER,
+    stock INTEGER,
+    category_id INTEGER,
+    status TEXT NOT NULL DEFAULT 'pending',
+    error TEXT,
+    product_id INTEGER REFERENCES products(id),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (import_id, row_no),
+    CHECK (status IN ('pending', 'lookup', 'imported', 'failed'))
+);
+
+-- the worker picks unprocessed rows in upload order
+CREATE INDEX idx_product_import_rows_status
+    ON product_import_rows (status, import_id, row_no);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_product_import_rows_status;
+DROP TABLE IF EXISTS product_import_rows;
+DROP INDEX IF EXISTS idx_product_imports_supplier;
+DROP TABLE IF EXISTS product_imports;
+
+ALTER TABLE products_supplier DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,38 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- GTINs are stored as GTIN-14 numbers: EAN-8, UPC-A and EAN-13 differ from
+-- their GTIN-14 form only by leading zeros, which BIGINT drops, so every
+-- form of an item already maps to the same value. What remains is to flag
+-- values whose check digit is wrong, and the 0 stored for products created
+-- from NCT without a barcode.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION gtin_valid(gtin BIGINT) RETURNS BOOLEAN
+LANGUAGE sql IMMUTABLE STRICT AS $$
+    SELECT CASE
+        WHEN gtin <= 0 OR gtin >= 100000000000000 THEN FALSE
+        ELSE (10 - (
+            SELECT sum(substr(lpad(gtin::text, 14, '0'), i, 1)::int * CASE WHEN i % 2 = 1 THEN 3 ELSE 1 END)
+            FROM generate_series(1, 13) AS i
+        ) % 10) % 10 = gtin % 10
+    END
+$$;
+-- +goose StatementEnd
+
+ALTER TABLE products
+    ADD COLUMN gtin_invalid BOOLEAN GENERATED ALWAYS AS (
+        gtin IS NOT NULL AND NOT gtin_valid(gtin)
+    ) STORED;
+
+CREATE INDEX idx_products_gtin_invalid ON products (id) WHERE gtin_invalid;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_products_gtin_invalid;
+
+ALTER TABLE products DROP COLUMN IF EXISTS gtin_invalid;
+
+DROP FUNCTION IF EXISTS gtin_valid(BIGINT);
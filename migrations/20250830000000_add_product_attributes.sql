@@ -0,0 +1,37 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+ALTER TABLE products
+    ADD COLUMN description        TEXT,
+    ADD COLUMN brand              TEXT,
+    ADD COLUMN manufacturer       TEXT,
+    ADD COLUMN weight             TEXT,    -- as labelled, e.g. "900 мл"
+    ADD COLUMN country_of_origin  TEXT,
+    ADD COLUMN unit               TEXT,    -- unit of sale, e.g. "шт", "кг"
+    ADD COLUMN shelf_life_days    INTEGER CHECK (shelf_life_days > 0),
+    ADD COLUMN storage_conditions TEXT;
+
+-- Gallery in display order; products.image_url keeps the main image.
+CREATE TABLE product_images (
+    product_id INTEGER   NOT NULL REFERENCES products (id) ON DELETE CASCADE,
+    position   INTEGER   NOT NULL CHECK (position >= 0),
+    url        TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (product_id, position)
+);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS product_images;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS storage_conditions,
+    DROP COLUMN IF EXISTS shelf_life_days,
+    DROP COLUMN IF EXISTS unit,
+    DROP COLUMN IF EXISTS country_of_origin,
+    DROP COLUMN IF EXISTS weight,
+    DROP COLUMN IF EXISTS manufacturer,
+    DROP COLUMN IF EXISTS brand,
+    DROP COLUMN IF EXISTS description;
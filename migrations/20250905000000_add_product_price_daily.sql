@@ -0,0 +1,61 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Daily price and sales aggregates per product for market analytics. A
+-- row holds the mean of the suppliers' prices in effect at the end of the
+-- day and the quantity ordered that day, cancelled orders excluded. The
+-- application refreshes recent days in the background.
+CREATE TABLE product_price_daily (
+    day        DATE           NOT NULL,
+    product_id INTEGER        NOT NULL,
+    avg_price  NUMERIC(12, 2) NOT NULL,
+    volume     INTEGER        NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_order_products_product_id ON order_products(product_id);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_price_daily(from_day DATE) RETURNS VOID
+LANGUAGE sql AS $$
+    INSERT INTO product_price_daily (day, product_id, avg_price, volume)
+    SELECT d.day::date, p.id, prices.avg_price, COALESCE(sold.quantity, 0)
+    FROM generate_series(from_day, CURRENT_DATE, INTERVAL '1 day') AS d(day)
+    CROSS JOIN products p
+    CROSS JOIN LATERAL (
+        SELECT AVG(last.price) AS avg_price
+        FROM (
+            SELECT DISTINCT ON (ph.supplier_id) ph.price
+            FROM price_history ph
+            WHERE ph.product_id = p.id
+              AND ph.date < d.day + INTERVAL '1 day'
+            ORDER BY ph.supplier_id, ph.date DESC, ph.id DESC
+        ) AS last
+    ) AS prices
+    LEFT JOIN LATERAL (
+        SELECT SUM(op.quantity) AS quantity
+        FROM order_products op
+        JOIN orders o ON o.id = op.order_id
+        JOIN order_status os ON os.id = o.status_id
+        WHERE op.product_id = p.id
+          AND os.name <> 'Cancelled'
+          AND o.order_date >= d.day
+          AND o.order_date < d.day + INTERVAL '1 day'
+    ) AS sold ON TRUE
+    WHERE prices.avg_price IS NOT NULL
+    ON CONFLICT (day, product_id) DO UPDATE
+        SET avg_price = EXCLUDED.avg_price,
+            volume    = EXCLUDED.volume;
+$$;
+-- +goose StatementEnd
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP FUNCTION IF EXISTS refresh_product_price_daily(DATE);
+
+DROP INDEX IF EXISTS idx_order_products_product_id;
+
+DROP TABLE IF EXISTS product_price_daily;
@@ -0,0 +1,26 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- A place reserved at checkout is held until the payment is approved. A
+-- hold neither confirmed nor released by expires_at gives its place back,
+-- so abandoned checkouts do not keep slot capacity.
+CREATE TABLE delivery_slot_holds (
+    id SERIAL PRIMARY KEY,
+    checkout_id VARCHAR(128) NOT NULL,
+    slot_id INTEGER NOT NULL,
+    delivery_date DATE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    confirmed_at TIMESTAMP,
+    released BOOLEAN NOT NULL DEFAULT FALSE,
+    FOREIGN KEY (slot_id) REFERENCES delivery_slots(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_delivery_slot_holds_checkout_id ON delivery_slot_holds(checkout_id);
+CREATE INDEX idx_delivery_slot_holds_open ON delivery_slot_holds(expires_at)
+    WHERE confirmed_at IS NULL AND NOT released;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP TABLE IF EXISTS delivery_slot_holds;
@@ -0,0 +1,97 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- A supplier's price counts towards a day's mean only while its offer was
+-- available. Availability has no history of its own: a deactivated offer
+-- counts until the day it was last updated, which is when it was
+-- deactivated.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_price_daily(from_day DATE) RETURNS VOID
+LANGUAGE sql AS $$
+    INSERT INTO product_price_daily (day, product_id, avg_price, volume)
+    SELECT d.day::date, p.id, prices.avg_price, COALESCE(sold.quantity, 0)
+    FROM generate_series(from_day, CURRENT_DATE, INTERVAL '1 day') AS d(day)
+    CROSS JOIN products p
+    CROSS JOIN LATERAL (
+        SELECT AVG(last.price) AS avg_price
+        FROM (
+            SELECT DISTINCT ON (ph.supplier_id) ph.price
+            FROM price_history ph
+            JOIN products_supplier ps
+              ON ps.product_id = ph.product_id
+             AND ps.supplier_id = ph.supplier_id
+            WHERE ph.product_id = p.id
+              AND ph.date < d.day + INTERVAL '1 day'
+              AND (ps.is_available OR ps.updated_at >= d.day + INTERVAL '1 day')
+            ORDER BY ph.supplier_id, ph.date DESC, ph.id DESC
+        ) AS last
+    ) AS prices
+    LEFT JOIN LATERAL (
+        SELECT SUM(op.quantity) AS quantity
+        FROM order_products op
+        JOIN orders o ON o.id = op.order_id
+        JOIN order_status os ON os.id = o.status_id
+        WHERE op.product_id = p.id
+          AND os.name <> 'Cancelled'
+          AND o.order_date >= d.day
+          AND o.order_date < d.day + INTERVAL '1 day'
+    ) AS sold ON TRUE
+    WHERE prices.avg_price IS NOT NULL
+    ON CONFLICT (day, product_id) DO UPDATE
+        SET avg_price = EXCLUDED.avg_price,
+            volume    = EXCLUDED.volume;
+
+    -- days on which no offer was available any more
+    DELETE FROM product_price_daily pd
+    WHERE pd.day >= from_day
+      AND NOT EXISTS (
+        SELECT 1
+        FROM price_history ph
+        JOIN products_supplier ps
+          ON ps.product_id = ph.product_id
+         AND ps.supplier_id = ph.supplier_id
+        WHERE ph.product_id = pd.product_id
+          AND ph.date < pd.day + INTERVAL '1 day'
+          AND (ps.is_available OR ps.updated_at >= pd.day + INTERVAL '1 day')
+      );
+$$;
+-- +goose StatementEnd
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_price_daily(from_day DATE) RETURNS VOID
+LANGUAGE sql AS $$
+    INSERT INTO product_price_daily (day, product_id, avg_price, volume)
+    SELECT d.day::date, p.id, prices.avg_price, COALESCE(sold.quantity, 0)
+    FROM generate_series(from_day, CURRENT_DATE, INTERVAL '1 day') AS d(day)
+    CROSS JOIN products p
+    CROSS JOIN LATERAL (
+        SELECT AVG(last.price) AS avg_price
+        FROM (
+            SELECT DISTINCT ON (ph.supplier_id) ph.price
+            FROM price_history ph
+            WHERE ph.product_id = p.id
+              AND ph.date < d.day + INTERVAL '1 day'
+            ORDER BY ph.supplier_id, ph.date DESC, ph.id DESC
+        ) AS last
+    ) AS prices
+    LEFT JOIN LATERAL (
+        SELECT SUM(op.quantity) AS quantity
+        FROM order_products op
+        JOIN orders o ON o.id = op.order_id
+        JOIN order_status os ON os.id = o.status_id
+        WHERE op.product_id = p.id
+          AND os.name <> 'Cancelled'
+          AND o.order_date >= d.day
+          AND o.order_date < d.day + INTERVAL '1 day'
+    ) AS sold ON TRUE
+    WHERE prices.avg_price IS NOT NULL
+    ON CONFLICT (day, product_id) DO UPDATE
+        SET avg_price = EXCLUDED.avg_price,
+            volume    = EXCLUDED.volume;
+$$;
+-- +goose StatementEnd
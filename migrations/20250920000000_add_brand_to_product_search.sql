@@ -0,0 +1,40 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Brands are searched like names. They are proper names, so they are
+-- indexed unstemmed only. A generated column cannot change its
+-- expression, so search_vector is rebuilt.
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE products
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('product_search'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'B') ||
+        setweight(to_tsvector('simple'::regconfig, translate(lower(coalesce(brand, '')), 'ё', 'е')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
+
+DROP INDEX IF EXISTS idx_products_name_trgm;
+CREATE INDEX idx_products_name_brand_trgm
+    ON products USING GIN (translate(lower(name || coalesce(' ' || brand, '')), 'ё', 'е') gin_trgm_ops);
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+DROP INDEX IF EXISTS idx_products_name_brand_trgm;
+CREATE INDEX idx_products_name_trgm
+    ON products USING GIN (translate(lower(name), 'ё', 'е') gin_trgm_ops);
+
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE products
+    ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('product_search'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, translate(lower(coalesce(name, '')), 'ё', 'е')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_products_search_vector ON products USING GIN (search_vector);
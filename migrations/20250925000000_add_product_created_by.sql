@@ -0,0 +1,15 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- The supplier who added the product to the catalog. Only that supplier
+-- and admins edit its attributes; products added before it was recorded
+-- are edited by admins only.
+ALTER TABLE products
+    ADD COLUMN created_by INTEGER REFERENCES users (id) ON DELETE SET NULL;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS created_by;
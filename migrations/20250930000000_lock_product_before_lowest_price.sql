@@ -0,0 +1,55 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- Lock the product before reading its offers. Two transactions changing
+-- offers of the same product otherwise both compute the minimum from
+-- their own snapshot, and the one committing last may store a lowest
+-- price that misses the other's change. The lock makes the second wait
+-- until the first has committed; under READ COMMITTED its next statement
+-- then sees that change.
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_lowest_price(p_product_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    v_price INTEGER;
+    v_supplier_id INTEGER;
+BEGIN
+    PERFORM 1 FROM products WHERE id = p_product_id FOR UPDATE;
+
+    SELECT price, supplier_id INTO v_price, v_supplier_id
+    FROM products_supplier
+    WHERE product_id = p_product_id AND is_available
+    ORDER BY price, supplier_id
+    LIMIT 1;
+
+    UPDATE products
+    SET lowest_price = v_price, lowest_supplier_id = v_supplier_id
+    WHERE id = p_product_id
+      AND (lowest_price, lowest_supplier_id) IS DISTINCT FROM (v_price, v_supplier_id);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION refresh_product_lowest_price(p_product_id INTEGER) RETURNS VOID AS $$
+DECLARE
+    v_price INTEGER;
+    v_supplier_id INTEGER;
+BEGIN
+    SELECT price, supplier_id INTO v_price, v_supplier_id
+    FROM products_supplier
+    WHERE product_id = p_product_id AND is_available
+    ORDER BY price, supplier_id
+    LIMIT 1;
+
+    UPDATE products
+    SET lowest_price = v_price, lowest_supplier_id = v_supplier_id
+    WHERE id = p_product_id
+      AND (lowest_price, lowest_supplier_id) IS DISTINCT FROM (v_price, v_supplier_id);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
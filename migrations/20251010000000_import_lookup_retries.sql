@@ -0,0 +1,16 @@
+-- +goose Up
+------------------------------------------------------------------------
+
+-- A failed NCT lookup is retried with a growing delay before the rows
+-- waiting for it are failed.
+ALTER TABLE product_import_rows
+    ADD COLUMN lookup_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_lookup_at  TIMESTAMP;
+
+------------------------------------------------------------------------
+-- +goose Down
+------------------------------------------------------------------------
+
+ALTER TABLE product_import_rows
+    DROP COLUMN IF EXISTS next_lookup_at,
+    DROP COLUMN IF EXISTS lookup_attempts;
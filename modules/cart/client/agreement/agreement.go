@@ -0,0 +1,21 @@
+package agreement
+
+import (
+	"context"
+)
+
+type AgreementClient struct {
+	contractService IContractService
+}
+
+func NewClient(contractService IContractService) *AgreementClient {
+	return &AgreementClient{contractService: contractService}
+}
+
+type IContractService interface {
+	NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64) (int, bool, error)
+}
+
+func (c *AgreementClient) NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64) (int, bool, error) {
+	return c.contractService.NegotiatedPrice(ctx, supplierID, customerID, productID)
+}
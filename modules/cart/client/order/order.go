@@ -29,9 +29,15 @@ func cartToOrder(cart *model.Cart) []*orderModel.Order {
 	for _, supplier := range cart.Suppliers {
 
 		order := orderModel.Order{
-			CustomerID:  cart.CustomerID,
-			SupplierID:  supplier.ID,
-			ProductList: cartProductListToOrderProductList(supplier.ProductList),
+			CustomerID:      cart.CustomerID,
+			SupplierID:      supplier.ID,
+			AddressID:       cart.DeliveryAddress.ID,
+			DeliveryAddress: cart.DeliveryAddress.Text,
+			ProductList:     cartProductListToOrderProductList(supplier.ProductList),
+		}
+		if supplier.DeliverySlot != nil {
+			order.DeliverySlotID = supplier.DeliverySlot.ID
+			order.OrderDate = supplier.DeliverySlot.Start
 		}
 		res = append(res, &order)
 	}
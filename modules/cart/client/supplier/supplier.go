@@ -4,6 +4,8 @@ import (
 	"context"
 	"diploma/modules/cart/model"
 	supplierModel "diploma/modules/supplier/model"
+	"errors"
+	"time"
 )
 
 type SupplierClient struct {
@@ -16,6 +18,10 @@ func NewClient(supplierService ISupplierService) *SupplierClient {
 
 type ISupplierService interface {
 	SupplierListByIDList(ctx context.Context, idList []int64) ([]supplierModel.Supplier, error)
+	AvailableSlots(ctx context.Context, supplierID int64) ([]supplierModel.SlotOccurrence, bool, error)
+	ReserveSlot(ctx context.Context, supplierID, slotID int64, date time.Time, checkoutID string) (supplierModel.SlotOccurrence, error)
+	ConfirmSlots(ctx context.Context, checkoutID string) error
+	ReleaseSlots(ctx context.Context, checkoutID string) error
 }
 
 func (a *SupplierClient) SupplierListByIDList(ctx context.Context, IDList []int64) ([]model.Supplier, error) {
@@ -36,3 +42,47 @@ func (a *SupplierClient) SupplierListByIDList(ctx context.Context, IDList []int6
 	}
 	return res, nil
 }
+
+func (a *SupplierClient) AvailableSlots(ctx context.Context, supplierID int64) ([]model.DeliverySlot, bool, error) {
+	occurrences, uses, err := a.supplierService.AvailableSlots(ctx, supplierID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res := make([]model.DeliverySlot, 0, len(occurrences))
+	for _, o := range occurrences {
+		res = append(res, toCartSlot(o))
+	}
+	return res, uses, nil
+}
+
+func (a *SupplierClient) ReserveSlot(ctx context.Context, supplierID int64, choice model.SlotChoice, checkoutID string) (model.DeliverySlot, error) {
+	occurrence, err := a.supplierService.ReserveSlot(ctx, supplierID, choice.SlotID, choice.Date, checkoutID)
+	if errors.Is(err, supplierModel.ErrSlotFull) ||
+		errors.Is(err, supplierModel.ErrSlotUnavailable) ||
+		errors.Is(err, supplierModel.ErrNoRows) {
+		return model.DeliverySlot{}, model.ErrSlotUnavailable
+	}
+	if err != nil {
+		return model.DeliverySlot{}, err
+	}
+	return toCartSlot(occurrence), nil
+}
+
+func (a *SupplierClient) ConfirmSlots(ctx context.Context, checkoutID string) error {
+	return a.supplierService.ConfirmSlots(ctx, checkoutID)
+}
+
+func (a *SupplierClient) ReleaseSlots(ctx context.Context, checkoutID string) error {
+	return a.supplierService.ReleaseSlots(ctx, checkoutID)
+}
+
+func toCartSlot(o supplierModel.SlotOccurrence) model.DeliverySlot {
+	return model.DeliverySlot{
+		ID:        o.SlotID,
+		Date:      o.Date,
+		Start:     o.Start,
+		End:       o.End,
+		Remaining: o.Remaining,
+	}
+}
@@ -0,0 +1,35 @@
+package user
+
+import (
+	"context"
+	"diploma/modules/cart/model"
+	userModel "diploma/modules/user/model"
+	"errors"
+)
+
+type UserClient struct {
+	userService IUserService
+}
+
+func NewClient(userService IUserService) *UserClient {
+	return &UserClient{userService: userService}
+}
+
+type IUserService interface {
+	AddressByID(ctx context.Context, userID, addressID int64) (userModel.Address, error)
+}
+
+func (c *UserClient) DeliveryAddress(ctx context.Context, userID, addressID int64) (model.DeliveryAddress, error) {
+	address, err := c.userService.AddressByID(ctx, userID, addressID)
+	if errors.Is(err, userModel.ErrAddressNotFound) {
+		return model.DeliveryAddress{}, model.ErrInvalidAddress
+	}
+	if err != nil {
+		return model.DeliveryAddress{}, err
+	}
+
+	return model.DeliveryAddress{
+		ID:   address.ID,
+		Text: address.FullText(),
+	}, nil
+}
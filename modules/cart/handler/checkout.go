@@ -19,8 +19,11 @@ import (
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
+// @Param input body modelApi.CheckoutInput true "Delivery address and a delivery slot per supplier"
 // @Success 200 {object} modelApi.CheckoutResponse "Checkout status"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid cart, address or missing slot"
 // @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 409 {object} modelApi.ErrorResponse "Delivery slot is full or no longer available"
 // @Failure 500 {object} modelApi.ErrorResponse "Internal Server Error"
 // @Router /api/cart/checkout [post]
 func (h *CartHandler) Checkout(c *gin.Context) {
@@ -31,12 +34,30 @@ func (h *CartHandler) Checkout(c *gin.Context) {
 		return
 	}
 
-	checkout, err := h.service.Checkout(c.Request.Context(), claims.UserID)
+	var input modelApi.CheckoutInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	query, err := converter.ToServiceCheckoutQueryFromAPI(claims.UserID, input)
 	if err != nil {
-		if errors.Is(err, model.ErrInvalidCart) {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	checkout, err := h.service.Checkout(c.Request.Context(), query)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidCart) ||
+			errors.Is(err, model.ErrInvalidAddress) ||
+			errors.Is(err, model.ErrSlotRequired) {
 			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
 			return
 		}
+		if errors.Is(err, model.ErrSlotUnavailable) {
+			c.JSON(http.StatusConflict, modelApi.ErrorResponse{Err: err.Error()})
+			return
+		}
 
 		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
 		return
@@ -3,6 +3,7 @@ package converter
 import (
 	modelApi "diploma/modules/cart/handler/model"
 	"diploma/modules/cart/model"
+	"time"
 )
 
 func ToServiceCardInputFromAPI(input *modelApi.AddProductToCartInput) *model.PutCartQuery {
@@ -33,11 +34,27 @@ func ToAPISuppliersFromService(suppliers []model.Supplier) []modelApi.Supplier {
 			FreeDeliveryAmount: supplier.FreeDeliveryAmount,
 			DeliveryFee:        supplier.DeliveryFee,
 			ProductList:        ToAPIProductsFromService(supplier.ProductList),
+			UsesDeliverySlots:  supplier.UsesDeliverySlots,
+			DeliverySlots:      ToAPIDeliverySlotsFromService(supplier.DeliverySlots),
 		}
 	}
 	return apiSuppliers
 }
 
+func ToAPIDeliverySlotsFromService(slots []model.DeliverySlot) []modelApi.DeliverySlot {
+	apiSlots := make([]modelApi.DeliverySlot, len(slots))
+	for i, slot := range slots {
+		apiSlots[i] = modelApi.DeliverySlot{
+			ID:        slot.ID,
+			Date:      slot.Date.Format(time.DateOnly),
+			Start:     slot.Start.Format(time.RFC3339),
+			End:       slot.End.Format(time.RFC3339),
+			Remaining: slot.Remaining,
+		}
+	}
+	return apiSlots
+}
+
 func ToAPIProductsFromService(products []model.Product) []modelApi.Product {
 	apiProducts := make([]modelApi.Product, len(products))
 	for i, product := range products {
@@ -46,7 +63,7 @@ func ToAPIProductsFromService(products []model.Product) []modelApi.Product {
 			Name:     product.Name,
 			Price:    product.Price,
 			Quantity: product.Quantity,
-			ImageUrl:    product.ImageUrl,
+			ImageUrl: product.ImageUrl,
 		}
 	}
 	return apiProducts
@@ -4,6 +4,8 @@ import (
 	modelApi "diploma/modules/cart/handler/model"
 	"diploma/modules/cart/model"
 	"errors"
+	"fmt"
+	"time"
 )
 
 func ToAPICheckoutFromService(checkoutResponse model.CheckoutResponse) modelApi.CheckoutResponse {
@@ -12,6 +14,25 @@ func ToAPICheckoutFromService(checkoutResponse model.CheckoutResponse) modelApi.
 	}
 }
 
+func ToServiceCheckoutQueryFromAPI(customerID int64, input modelApi.CheckoutInput) (model.CheckoutQuery, error) {
+	query := model.CheckoutQuery{
+		CustomerID: customerID,
+		AddressID:  input.AddressID,
+		Slots:      make(map[int64]model.SlotChoice, len(input.Slots)),
+	}
+	for _, slot := range input.Slots {
+		date, err := time.Parse(time.DateOnly, slot.Date)
+		if err != nil {
+			return model.CheckoutQuery{}, fmt.Errorf("invalid slot date %q, expected YYYY-MM-DD", slot.Date)
+		}
+		query.Slots[slot.SupplierID] = model.SlotChoice{
+			SlotID: slot.SlotID,
+			Date:   date,
+		}
+	}
+	return query, nil
+}
+
 func ToServiceCheckoutFromApi(data map[string]interface{}) (model.CommitCheckout, error) {
 	orderID, ok := data["order_id"].(string)
 	if !ok {
@@ -1,5 +1,18 @@
 package model
 
+type CheckoutInput struct {
+	AddressID int64 `json:"address_id" binding:"required"`
+	// Slots holds the delivery slot chosen for each supplier that delivers
+	// by slots.
+	Slots []SlotChoice `json:"slots"`
+}
+
+type SlotChoice struct {
+	SupplierID int64  `json:"supplier_id" binding:"required"`
+	SlotID     int64  `json:"slot_id" binding:"required"`
+	Date       string `json:"date" binding:"required" example:"2025-07-15"`
+}
+
 type CheckoutResponse struct {
 	CheckoutURL string `json:"checkout_url"`
 }
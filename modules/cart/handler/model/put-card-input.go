@@ -28,13 +28,24 @@ type GetCartResponse struct {
 }
 
 type Supplier struct {
-	OrderAmount        int       `json:"order_amount`
+	OrderAmount        int       `json:"order_amount"`
 	TotalAmount        int       `json:"total_amount"`
 	FreeDeliveryAmount int       `json:"free_delivery_amount"`
 	DeliveryFee        int       `json:"delivery_fee"`
 	ID                 int64     `json:"id"`
 	Name               string    `json:"name"`
 	ProductList        []Product `json:"product_list"`
+
+	UsesDeliverySlots bool           `json:"uses_delivery_slots"`
+	DeliverySlots     []DeliverySlot `json:"delivery_slots"`
+}
+
+type DeliverySlot struct {
+	ID        int64  `json:"id"`
+	Date      string `json:"date"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Remaining int    `json:"remaining"`
 }
 
 type Product struct {
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type PutCartQuery struct {
 	Quantity   int
 	Price      int
@@ -10,10 +12,17 @@ type PutCartQuery struct {
 }
 
 type Cart struct {
-	ID         int64
-	Total      int
-	CustomerID int64
-	Suppliers  []Supplier
+	ID              int64
+	Total           int
+	CustomerID      int64
+	Suppliers       []Supplier
+	DeliveryAddress DeliveryAddress
+}
+
+// DeliveryAddress is a copy of the customer's address taken at checkout.
+type DeliveryAddress struct {
+	ID   int64
+	Text string
 }
 
 type Supplier struct {
@@ -24,6 +33,22 @@ type Supplier struct {
 	ID                 int64
 	Name               string
 	ProductList        []Product
+
+	// UsesDeliverySlots is set when the supplier delivers by slots; one of
+	// DeliverySlots must then be picked at checkout.
+	UsesDeliverySlots bool
+	DeliverySlots     []DeliverySlot
+	// DeliverySlot is the slot reserved at checkout.
+	DeliverySlot *DeliverySlot
+}
+
+// DeliverySlot is a delivery window of a supplier on a concrete date.
+type DeliverySlot struct {
+	ID        int64
+	Date      time.Time
+	Start     time.Time
+	End       time.Time
+	Remaining int
 }
 
 type Product struct {
@@ -40,5 +65,3 @@ type DeleteProductQuery struct {
 	Quantity   int
 	SupplierID int64
 }
-
-
@@ -1,8 +1,10 @@
 package model
 
+import "time"
+
 const (
 	PaymentStatusApproved = "approved"
-	PaymentStatusFailed  = "failed"
+	PaymentStatusFailed   = "failed"
 )
 
 type CheckoutResponse struct {
@@ -17,6 +19,18 @@ type PaymentOrder struct {
 	CheckoutResponse
 }
 
+type CheckoutQuery struct {
+	CustomerID int64
+	AddressID  int64
+	// Slots maps supplier ID to the delivery slot picked for that supplier.
+	Slots map[int64]SlotChoice
+}
+
+type SlotChoice struct {
+	SlotID int64
+	Date   time.Time
+}
+
 type CommitCheckout struct {
 	OrderID       string
 	PaymentStatus string
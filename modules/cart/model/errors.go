@@ -10,4 +10,10 @@ var (
 	ErrNoRows = errors.New("models: no rows")
 
 	ErrInvalidCart = errors.New("models: invalid cart")
+
+	ErrInvalidAddress = errors.New("models: address does not belong to the user")
+
+	ErrSlotRequired = errors.New("models: a delivery slot must be chosen for every supplier")
+
+	ErrSlotUnavailable = errors.New("models: delivery slot is not available")
 )
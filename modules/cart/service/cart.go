@@ -47,6 +47,24 @@ func (s *cartServ) AddProductToCard(ctx context.Context, query *model.PutCartQue
 			return errTx
 		}
 
+		// an active framework agreement overrides the supplier's list price
+		negotiated, ok, errTx := s.agreementClient.NegotiatedPrice(ctx, query.SupplierID, query.CustomerID, query.ProductID)
+		if errTx != nil {
+			s.LogError(ctx, "Failed to get negotiated price", errTx,
+				zap.Int64("product_id", query.ProductID),
+				zap.Int64("supplier_id", query.SupplierID),
+			)
+			return errTx
+		}
+		if ok {
+			s.LogInfo(ctx, "Using framework agreement price",
+				zap.Int64("product_id", query.ProductID),
+				zap.Int("list_price", query.Price),
+				zap.Int("price", negotiated),
+			)
+			query.Price = negotiated
+		}
+
 		itemQuantity, errTx := s.cartRepo.ItemQuantity(ctx, query.CartID, query.ProductID, query.SupplierID)
 		if errTx != nil {
 			if errors.Is(errTx, model.ErrNoRows) {
@@ -149,6 +167,16 @@ func (s *cartServ) Cart(ctx context.Context, userID int64) (*model.Cart, error)
 			cart.Suppliers[i].TotalAmount = getTotalSupplier(ctx, cart.Suppliers[i].ProductList, cart.Suppliers[i])
 		}
 
+		for i := range cart.Suppliers {
+			cart.Suppliers[i].DeliverySlots, cart.Suppliers[i].UsesDeliverySlots, errTx = s.supplierService.AvailableSlots(ctx, cart.Suppliers[i].ID)
+			if errTx != nil {
+				s.LogError(ctx, "Failed to get delivery slots", errTx,
+					zap.Int64("supplier_id", cart.Suppliers[i].ID),
+				)
+				return errTx
+			}
+		}
+
 		total := 0
 		for _, supplier := range cart.Suppliers {
 			total += supplier.TotalAmount
@@ -5,26 +5,41 @@ import (
 	"diploma/modules/cart/model"
 	"fmt"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"strconv"
 )
 
-func (s *cartServ) Checkout(ctx context.Context, userID int64) (model.CheckoutResponse, error) {
+func (s *cartServ) Checkout(ctx context.Context, query model.CheckoutQuery) (model.CheckoutResponse, error) {
 	var checkout model.CheckoutResponse
 	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
 		// get cart
-		cart, errTx := s.Cart(ctx, userID)
+		cart, errTx := s.Cart(ctx, query.CustomerID)
 		if errTx != nil {
 			return errTx
 		}
-		cart.CustomerID = userID
+		cart.CustomerID = query.CustomerID
 		if !checkCartForCheckout(cart) {
 			return model.ErrInvalidCart
 		}
 
+		// the address text is copied now, so later edits in the address
+		// book do not change the order
+		cart.DeliveryAddress, errTx = s.userClient.DeliveryAddress(ctx, query.CustomerID, query.AddressID)
+		if errTx != nil {
+			return errTx
+		}
+
+		// reserve delivery slots; the reservations are rolled back with the
+		// transaction if the payment request fails, and given back when the
+		// payment does not arrive before the hold expires
+		paymentOrderID := generateOrderID(cart.CustomerID, cart.ID)
+		if errTx = s.reserveSlots(ctx, cart, query.Slots, paymentOrderID); errTx != nil {
+			return errTx
+		}
+
 		// process payment
 		amount := getCartAmount(cart)
 
-		paymentOrderID := generateOrderID(cart.CustomerID, cart.ID)
 		checkout, errTx = s.PaymentClient.PaymentRequest(paymentOrderID, strconv.Itoa(amount)+"00", "USD", "Payment for order")
 		if errTx != nil {
 			return errTx
@@ -73,8 +88,41 @@ func (s *cartServ) CommitCheckout(ctx context.Context, commitCheckout model.Comm
 		if err != nil {
 			return err
 		}
+
+		// the order is paid and kept even if a slot whose hold expired was
+		// taken meanwhile; the supplier reschedules that delivery
+		if err = s.supplierService.ConfirmSlots(ctx, paymentOrder.ID); err != nil {
+			s.LogError(ctx, "Failed to confirm delivery slots", err,
+				zap.String("payment_order_id", paymentOrder.ID),
+			)
+		}
 	case model.PaymentStatusFailed:
+		return s.supplierService.ReleaseSlots(ctx, commitCheckout.OrderID)
+	}
+	return nil
+}
+
+func (s *cartServ) reserveSlots(ctx context.Context, cart *model.Cart, choices map[int64]model.SlotChoice, checkoutID string) error {
+	for i := range cart.Suppliers {
+		supplier := &cart.Suppliers[i]
+		if !supplier.UsesDeliverySlots {
+			continue
+		}
 
+		choice, ok := choices[supplier.ID]
+		if !ok {
+			return model.ErrSlotRequired
+		}
+
+		slot, err := s.supplierService.ReserveSlot(ctx, supplier.ID, choice, checkoutID)
+		if err != nil {
+			s.LogError(ctx, "Failed to reserve delivery slot", err,
+				zap.Int64("supplier_id", supplier.ID),
+				zap.Int64("slot_id", choice.SlotID),
+			)
+			return err
+		}
+		supplier.DeliverySlot = &slot
 	}
 	return nil
 }
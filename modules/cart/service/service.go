@@ -15,6 +15,8 @@ type cartServ struct {
 	orderService    IOrderClient
 	PaymentClient   IPaymentClient
 	redis           IRedis
+	userClient      IUserClient
+	agreementClient IAgreementClient
 	txManager       db.TxManager
 }
 
@@ -25,6 +27,8 @@ func NewService(
 	OrderClient IOrderClient,
 	PaymentClient IPaymentClient,
 	redis IRedis,
+	userClient IUserClient,
+	agreementClient IAgreementClient,
 	txManager db.TxManager,
 ) *cartServ {
 	return &cartServ{
@@ -34,6 +38,8 @@ func NewService(
 		orderService:    OrderClient,
 		PaymentClient:   PaymentClient,
 		redis:           redis,
+		userClient:      userClient,
+		agreementClient: agreementClient,
 		txManager:       txManager,
 		productService:  productService,
 	}
@@ -49,12 +55,27 @@ type IProductService interface {
 
 type ISupplierClient interface {
 	SupplierListByIDList(ctx context.Context, IDList []int64) ([]model.Supplier, error)
+	AvailableSlots(ctx context.Context, supplierID int64) ([]model.DeliverySlot, bool, error)
+	// ReserveSlot holds a place for the checkout until ConfirmSlots,
+	// ReleaseSlots or the hold expires.
+	ReserveSlot(ctx context.Context, supplierID int64, choice model.SlotChoice, checkoutID string) (model.DeliverySlot, error)
+	ConfirmSlots(ctx context.Context, checkoutID string) error
+	ReleaseSlots(ctx context.Context, checkoutID string) error
 }
 
 type IOrderClient interface {
 	CreateOrder(ctx context.Context, cart *model.Cart) error
 }
 
+type IUserClient interface {
+	DeliveryAddress(ctx context.Context, userID, addressID int64) (model.DeliveryAddress, error)
+}
+
+// IAgreementClient looks up prices negotiated in framework agreements.
+type IAgreementClient interface {
+	NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64) (int, bool, error)
+}
+
 type IRedis interface {
 	SavePaymentOrder(ctx context.Context, paymentOrder model.PaymentOrder) error
 	PaymentOrder(ctx context.Context, orderID string) (model.PaymentOrder, error)
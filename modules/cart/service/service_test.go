@@ -4,7 +4,6 @@ import (
 	"context"
 	"diploma/internal/testutils"
 	"diploma/modules/cart/model"
-	"diploma/pkg/client/db"
 	"testing"
 
 	"github.com/stretchr/testify/mock"
@@ -92,6 +91,29 @@ func (m *mockSupplierClient) SupplierListByIDList(ctx context.Context, IDList []
 	return args.Get(0).([]model.Supplier), args.Error(1)
 }
 
+func (m *mockSupplierClient) AvailableSlots(ctx context.Context, supplierID int64) ([]model.DeliverySlot, bool, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).([]model.DeliverySlot), args.Bool(1), args.Error(2)
+}
+
+func (m *mockSupplierClient) ReserveSlot(ctx context.Context, supplierID int64, choice model.SlotChoice, checkoutID string) (model.DeliverySlot, error) {
+	args := m.Called(ctx, supplierID, choice, checkoutID)
+	return args.Get(0).(model.DeliverySlot), args.Error(1)
+}
+
+func (m *mockSupplierClient) ConfirmSlots(ctx context.Context, checkoutID string) error {
+	args := m.Called(ctx, checkoutID)
+	return args.Error(0)
+}
+
+func (m *mockSupplierClient) ReleaseSlots(ctx context.Context, checkoutID string) error {
+	args := m.Called(ctx, checkoutID)
+	return args.Error(0)
+}
+
 type mockOrderClient struct {
 	mock.Mock
 }
@@ -110,6 +132,24 @@ func (m *mockPaymentClient) PaymentRequest(orderID, amount, currency, descriptio
 	return args.Get(0).(model.CheckoutResponse), args.Error(1)
 }
 
+type mockUserClient struct {
+	mock.Mock
+}
+
+func (m *mockUserClient) DeliveryAddress(ctx context.Context, userID, addressID int64) (model.DeliveryAddress, error) {
+	args := m.Called(ctx, userID, addressID)
+	return args.Get(0).(model.DeliveryAddress), args.Error(1)
+}
+
+type mockAgreementClient struct {
+	mock.Mock
+}
+
+func (m *mockAgreementClient) NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64) (int, bool, error) {
+	args := m.Called(ctx, supplierID, customerID, productID)
+	return args.Int(0), args.Bool(1), args.Error(2)
+}
+
 type mockRedis struct {
 	mock.Mock
 }
@@ -127,15 +167,6 @@ func (m *mockRedis) PaymentOrder(ctx context.Context, orderID string) (model.Pay
 	return args.Get(0).(model.PaymentOrder), args.Error(1)
 }
 
-type mockTxManager struct {
-	mock.Mock
-}
-
-func (m *mockTxManager) ReadCommitted(ctx context.Context, h db.Handler) error {
-	args := m.Called(ctx, h)
-	return args.Error(0)
-}
-
 type CartServiceTestSuite struct {
 	suite.Suite
 	service         *cartServ
@@ -145,7 +176,9 @@ type CartServiceTestSuite struct {
 	orderService    *mockOrderClient
 	paymentClient   *mockPaymentClient
 	redis           *mockRedis
-	txManager       *mockTxManager
+	userClient      *mockUserClient
+	agreementClient *mockAgreementClient
+	txManager       *testutils.MockTxManager
 	helper          *testutils.AssertTestHelper
 }
 
@@ -160,9 +193,13 @@ func (s *CartServiceTestSuite) SetupTest() {
 	s.orderService = new(mockOrderClient)
 	s.paymentClient = new(mockPaymentClient)
 	s.redis = new(mockRedis)
-	s.txManager = new(mockTxManager)
+	s.userClient = new(mockUserClient)
+	s.agreementClient = new(mockAgreementClient)
+	s.txManager = new(testutils.MockTxManager)
 	s.helper = testutils.NewAssertTestHelper(s.T())
 
+	s.txManager.On("ReadCommitted", mock.Anything, mock.Anything).Return(nil)
+
 	s.service = NewService(
 		s.cartRepo,
 		s.productService,
@@ -170,6 +207,8 @@ func (s *CartServiceTestSuite) SetupTest() {
 		s.orderService,
 		s.paymentClient,
 		s.redis,
+		s.userClient,
+		s.agreementClient,
 		s.txManager,
 	)
 }
@@ -251,3 +290,53 @@ func (s *CartServiceTestSuite) TestDeleteCart_Success() {
 
 	s.helper.AssertNoError(err)
 }
+
+func (s *CartServiceTestSuite) TestCommitCheckout_FailedPaymentReleasesSlots() {
+	s.supplierService.On("ReleaseSlots", mock.Anything, "1-2-abc").Return(nil)
+
+	err := s.service.CommitCheckout(context.Background(), model.CommitCheckout{
+		OrderID:       "1-2-abc",
+		PaymentStatus: model.PaymentStatusFailed,
+	})
+
+	s.helper.AssertNoError(err)
+	s.supplierService.AssertExpectations(s.T())
+}
+
+func (s *CartServiceTestSuite) expectAddProduct(price int) {
+	s.cartRepo.On("Cart", mock.Anything, int64(1)).Return(&model.Cart{ID: 7, Total: 50}, nil)
+	s.productService.On("ProductPriceBySupplier", mock.Anything, int64(3), int64(2)).Return(price, nil)
+	s.cartRepo.On("ItemQuantity", mock.Anything, int64(7), int64(3), int64(2)).Return(0, model.ErrNoRows)
+}
+
+func (s *CartServiceTestSuite) TestAddProductToCard_AgreementPrice() {
+	query := &model.PutCartQuery{CustomerID: 1, SupplierID: 2, ProductID: 3, Quantity: 2}
+	s.expectAddProduct(100)
+	s.agreementClient.On("NegotiatedPrice", mock.Anything, int64(2), int64(1), int64(3)).Return(80, true, nil)
+	s.cartRepo.On("AddItem", mock.Anything, mock.MatchedBy(func(q *model.PutCartQuery) bool {
+		return q.Price == 80
+	})).Return(nil)
+	s.cartRepo.On("UpdateCartTotal", mock.Anything, int64(7), 50+2*80).Return(nil)
+
+	err := s.service.AddProductToCard(context.Background(), query)
+
+	s.helper.AssertNoError(err)
+	s.cartRepo.AssertExpectations(s.T())
+	s.agreementClient.AssertExpectations(s.T())
+}
+
+func (s *CartServiceTestSuite) TestAddProductToCard_NoAgreement() {
+	query := &model.PutCartQuery{CustomerID: 1, SupplierID: 2, ProductID: 3, Quantity: 2}
+	s.expectAddProduct(100)
+	s.agreementClient.On("NegotiatedPrice", mock.Anything, int64(2), int64(1), int64(3)).Return(0, false, nil)
+	s.cartRepo.On("AddItem", mock.Anything, mock.MatchedBy(func(q *model.PutCartQuery) bool {
+		return q.Price == 100
+	})).Return(nil)
+	s.cartRepo.On("UpdateCartTotal", mock.Anything, int64(7), 50+2*100).Return(nil)
+
+	err := s.service.AddProductToCard(context.Background(), query)
+
+	s.helper.AssertNoError(err)
+	s.cartRepo.AssertExpectations(s.T())
+	s.agreementClient.AssertExpectations(s.T())
+}
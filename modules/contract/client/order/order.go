@@ -0,0 +1,34 @@
+package order
+
+import (
+	"context"
+)
+
+// OrderEvents applies contract outcomes to the order.
+type OrderEvents struct {
+	orderService func() IOrderService
+}
+
+// NewEvents takes a getter instead of the service itself because the order
+// service depends on the contract service and is created after it.
+func NewEvents(orderService func() IOrderService) *OrderEvents {
+	return &OrderEvents{orderService: orderService}
+}
+
+type IOrderService interface {
+	CompleteOrderByContract(ctx context.Context, orderID int64) error
+	CancelOrderByContract(ctx context.Context, orderID int64) error
+	CancelOrderByExpiredContract(ctx context.Context, orderID int64) error
+}
+
+func (e *OrderEvents) ContractCompleted(ctx context.Context, orderID int64) error {
+	return e.orderService().CompleteOrderByContract(ctx, orderID)
+}
+
+func (e *OrderEvents) ContractClosed(ctx context.Context, orderID int64) error {
+	return e.orderService().CancelOrderByContract(ctx, orderID)
+}
+
+func (e *OrderEvents) ContractExpired(ctx context.Context, orderID int64) error {
+	return e.orderService().CancelOrderByExpiredContract(ctx, orderID)
+}
@@ -0,0 +1,32 @@
+package user
+
+import (
+	"context"
+	"diploma/modules/contract/model"
+	userModel "diploma/modules/user/model"
+)
+
+type UserClient struct {
+	userService IUserService
+}
+
+func NewClient(userService IUserService) *UserClient {
+	return &UserClient{userService: userService}
+}
+
+type IUserService interface {
+	User(ctx context.Context, userID int64) (userModel.User, error)
+}
+
+func (c *UserClient) Party(ctx context.Context, userID int64) (*model.Party, error) {
+	user, err := c.userService.User(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Party{
+		ID:    user.ID,
+		Name:  user.Name,
+		Phone: user.PhoneNumber,
+	}, nil
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	contractConverter "diploma/modules/contract/handler/converter"
+	apiModel "diploma/modules/contract/handler/model"
+	serviceModel "diploma/modules/contract/model"
+	contextkeys "diploma/pkg/context-keys"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAgreement godoc
+// @Summary Propose framework agreement
+// @Description Supplier only. Proposes a framework agreement to a customer with a validity period, credit terms and negotiated prices. It takes effect once the customer accepts it.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body apiModel.AgreementRequest true "Agreement"
+// @Success 200 {object} apiModel.CreateAgreementResponse
+// @Failure 400 {object} map[string]string "Invalid agreement"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements [post]
+func (h *Handler) CreateAgreement(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if claims.Role != serviceModel.SupplierRole {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only suppliers can propose agreements"})
+		return
+	}
+
+	var req apiModel.AgreementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agreement := contractConverter.ToServiceAgreement(&req)
+	agreement.SupplierID = claims.UserID
+	id, err := h.service.CreateAgreement(c.Request.Context(), agreement)
+	if err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiModel.CreateAgreementResponse{ID: id})
+}
+
+// UpdateAgreement godoc
+// @Summary Update framework agreement
+// @Description Supplier only. Replaces the period, credit terms and prices of a pending agreement.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Param input body apiModel.AgreementRequest true "Agreement"
+// @Success 200 {object} map[string]string "agreement updated"
+// @Failure 400 {object} map[string]string "Invalid agreement"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Agreement not found"
+// @Failure 409 {object} map[string]string "Agreement is no longer pending"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements/{id} [put]
+func (h *Handler) UpdateAgreement(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	id, ok := agreementID(c)
+	if !ok {
+		return
+	}
+
+	var req apiModel.AgreementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	agreement := contractConverter.ToServiceAgreement(&req)
+	agreement.ID = id
+	agreement.SupplierID = claims.UserID
+	if err := h.service.UpdateAgreement(c.Request.Context(), agreement); err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "agreement updated"})
+}
+
+// AcceptAgreement godoc
+// @Summary Accept framework agreement
+// @Description Customer only. Activates a pending agreement; its prices apply to the customer's carts from then on.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 200 {object} map[string]string "agreement accepted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Agreement not found"
+// @Failure 409 {object} map[string]string "Not pending or overlapping another active agreement"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements/{id}/accept [post]
+func (h *Handler) AcceptAgreement(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	id, ok := agreementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.AcceptAgreement(c.Request.Context(), id, claims.UserID); err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "agreement accepted"})
+}
+
+// TerminateAgreement godoc
+// @Summary Terminate framework agreement
+// @Description Either party can terminate a pending or active agreement. Later orders use list prices again.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 200 {object} map[string]string "agreement terminated"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Agreement not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements/{id}/terminate [post]
+func (h *Handler) TerminateAgreement(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	id, ok := agreementID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.TerminateAgreement(c.Request.Context(), id, claims.UserID); err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "agreement terminated"})
+}
+
+// GetAgreement godoc
+// @Summary Get framework agreement
+// @Description Returns the agreement with its negotiated prices to its parties or an admin.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Agreement ID"
+// @Success 200 {object} apiModel.AgreementResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Agreement not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements/{id} [get]
+func (h *Handler) GetAgreement(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	id, ok := agreementID(c)
+	if !ok {
+		return
+	}
+
+	agreement, err := h.service.Agreement(c.Request.Context(), id, claims.UserID, claims.Role)
+	if err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contractConverter.ToAPIAgreement(agreement))
+}
+
+// GetAgreements godoc
+// @Summary List framework agreements
+// @Description Returns the caller's agreements, newest first, without prices.
+// @Tags contract-agreements
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} apiModel.AgreementResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/agreements [get]
+func (h *Handler) GetAgreements(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	agreements, err := h.service.Agreements(c.Request.Context(), claims.UserID)
+	if err != nil {
+		writeAgreementError(c, err)
+		return
+	}
+
+	res := make([]apiModel.AgreementResponse, 0, len(agreements))
+	for _, a := range agreements {
+		res = append(res, contractConverter.ToAPIAgreement(a))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func writeAgreementError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceModel.ErrInvalidAgreement):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, serviceModel.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "you are not a party of this agreement"})
+	case errors.Is(err, serviceModel.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "agreement not found"})
+	case errors.Is(err, serviceModel.ErrAgreementNotPending), errors.Is(err, serviceModel.ErrAgreementOverlap):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func agreementID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid agreement ID"})
+		return 0, false
+	}
+	return id, true
+}
@@ -4,22 +4,155 @@ import (
 	"database/sql"
 	apiModel "diploma/modules/contract/handler/model"
 	serviceModel "diploma/modules/contract/model"
+	"encoding/base64"
+	"time"
 )
 
 func ToAPI(c *serviceModel.Contract) *apiModel.ContractResponse {
 	return &apiModel.ContractResponse{
-		ID:          c.ID,
-		Content:     c.Content,
-		Status:      c.Status,
-		SupplierSig: nullStringToString(c.SupplierSig),
-		CustomerSig: nullStringToString(c.CustomerSig),
+		ID:           c.ID,
+		OrderID:      c.OrderID,
+		Content:      c.Content,
+		Status:       c.Status,
+		ContentHash:  c.CanonicalHashHex(),
+		SupplierSig:  nullStringToString(c.SupplierSig),
+		CustomerSig:  nullStringToString(c.CustomerSig),
+		Version:      c.Version,
+		PreviousID:   c.PreviousID.Int64,
+		ExpiresAt:    nullTimeToString(c.ExpiresAt),
+		RejectedBy:   c.RejectedBy.Int64,
+		RejectReason: nullStringToString(c.RejectReason),
+		RejectedAt:   nullTimeToString(c.RejectedAt),
+		AgreementID:  c.AgreementID.Int64,
 	}
 
 }
 
+// statusNames are the status keys of ContractListResponse.StatusCounts.
+var statusNames = map[int]string{
+	serviceModel.StatusCreated:          "created",
+	serviceModel.StatusSignedBySupplier: "signed_by_supplier",
+	serviceModel.StatusSignedByCustomer: "signed_by_customer",
+	serviceModel.StatusCompleted:        "completed",
+	serviceModel.StatusRejected:         "rejected",
+	serviceModel.StatusAmended:          "amended",
+	serviceModel.StatusExpired:          "expired",
+}
+
+func ToAPIList(list *serviceModel.ContractList) apiModel.ContractListResponse {
+	res := apiModel.ContractListResponse{
+		Contracts:         make([]apiModel.ContractResponse, 0, len(list.Contracts)),
+		Total:             list.Total,
+		StatusCounts:      make(map[string]int, len(statusNames)),
+		AwaitingSignature: list.AwaitingSignature,
+	}
+	for _, c := range list.Contracts {
+		res.Contracts = append(res.Contracts, *ToAPI(c))
+	}
+	for status, name := range statusNames {
+		res.StatusCounts[name] = list.StatusCounts[status]
+	}
+	return res
+}
+
 func nullStringToString(ns sql.NullString) string {
 	if ns.Valid {
 		return ns.String
 	}
 	return ""
 }
+
+func nullTimeToString(nt sql.NullTime) string {
+	if nt.Valid {
+		return nt.Time.Format(time.RFC3339)
+	}
+	return ""
+}
+
+func ToAPIKey(k *serviceModel.SigningKey) apiModel.SigningKeyResponse {
+	res := apiModel.SigningKeyResponse{
+		ID:             k.ID,
+		PublicKey:      base64.StdEncoding.EncodeToString(k.PublicKey),
+		Algorithm:      k.Algorithm,
+		IssuedByServer: k.IssuedByServer,
+		CreatedAt:      k.CreatedAt.Format(time.RFC3339),
+	}
+	if k.RevokedAt != nil {
+		res.RevokedAt = k.RevokedAt.Format(time.RFC3339)
+	}
+	return res
+}
+
+func ToAPIVerification(v *serviceModel.Verification) *apiModel.VerificationResponse {
+	return &apiModel.VerificationResponse{
+		ContractID:    v.ContractID,
+		Valid:         v.Valid(),
+		ContentHash:   v.ContentHash,
+		RecordedHash:  v.RecordedHash,
+		ContentIntact: v.ContentIntact,
+		ContentReason: v.ContentReason,
+		Supplier:      apiModel.PartyVerification(v.Supplier),
+		Customer:      apiModel.PartyVerification(v.Customer),
+	}
+}
+
+func ToAPITemplate(t *serviceModel.Template) apiModel.TemplateResponse {
+	return apiModel.TemplateResponse{
+		ID:         t.ID,
+		SupplierID: t.SupplierID,
+		Name:       t.Name,
+		IsDefault:  t.IsDefault,
+		Version:    t.Version,
+		Body:       t.Body,
+		CreatedBy:  t.CreatedBy,
+		CreatedAt:  t.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToAPITemplateVersion(v serviceModel.TemplateVersion) apiModel.TemplateVersionResponse {
+	return apiModel.TemplateVersionResponse{
+		Version:   v.Version,
+		Body:      v.Body,
+		CreatedBy: v.CreatedBy,
+		CreatedAt: v.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+const dateLayout = "2006-01-02"
+
+func ToAPIAgreement(a *serviceModel.Agreement) apiModel.AgreementResponse {
+	res := apiModel.AgreementResponse{
+		ID:           a.ID,
+		SupplierID:   a.SupplierID,
+		CustomerID:   a.CustomerID,
+		ValidFrom:    a.ValidFrom.Format(dateLayout),
+		ValidTo:      a.ValidTo.Format(dateLayout),
+		PaymentDays:  a.PaymentDays,
+		CreditLimit:  a.CreditLimit,
+		Status:       a.Status,
+		CreatedAt:    a.CreatedAt.Format(time.RFC3339),
+		AcceptedAt:   nullTimeToString(a.AcceptedAt),
+		TerminatedAt: nullTimeToString(a.TerminatedAt),
+	}
+	for _, p := range a.Prices {
+		res.Prices = append(res.Prices, apiModel.AgreementPrice{ProductID: p.ProductID, Price: p.Price})
+	}
+	return res
+}
+
+// ToServiceAgreement converts the request; dates that do not parse are left
+// zero and rejected by validation.
+func ToServiceAgreement(req *apiModel.AgreementRequest) *serviceModel.Agreement {
+	a := &serviceModel.Agreement{
+		CustomerID:  req.CustomerID,
+		PaymentDays: req.PaymentDays,
+		CreditLimit: req.CreditLimit,
+		Prices:      make([]serviceModel.AgreementPrice, 0, len(req.Prices)),
+	}
+	a.ValidFrom, _ = time.Parse(dateLayout, req.ValidFrom)
+	a.ValidTo, _ = time.Parse(dateLayout, req.ValidTo)
+	for _, p := range req.Prices {
+		a.Prices = append(a.Prices, serviceModel.AgreementPrice{ProductID: p.ProductID, Price: p.Price})
+	}
+	return a
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	contextkeys "diploma/pkg/context-keys"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPDF godoc
+// @Summary Download contract PDF
+// @Description Returns the contract as PDF with both parties, the signature status and signing times. Once both parties have signed, the PDF is archived and every download returns the same bytes; their SHA-256 is sent in the X-Content-SHA256 header.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Produce application/pdf
+// @Param id path int true "Contract ID"
+// @Success 200 {file} file "Contract PDF"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not a party of the contract"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/{id}/pdf [get]
+func (h *Handler) GetPDF(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	contractID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contract ID"})
+		return
+	}
+
+	body, hash, err := h.service.ContractPDF(c.Request.Context(), contractID, claims.UserID, claims.Role)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if hash != "" {
+		c.Header("X-Content-SHA256", hash)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="contract-%d.pdf"`, contractID))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"diploma/modules/auth/jwt"
 	contractConverter "diploma/modules/contract/handler/converter"
@@ -14,9 +17,28 @@ import (
 )
 
 type Service interface {
-	SignContract(ctx context.Context, contractID int64, role int, signature string) error
-	GetContract(ctx context.Context, id int64) (*serviceModel.Contract, error)
-	GetContractsByUser(ctx context.Context, userID int64) ([]*serviceModel.Contract, error) // 🔹 Добавьте эту строку
+	SignContract(ctx context.Context, contractID, userID int64, signature string) error
+	GetContract(ctx context.Context, id, userID int64, role int) (*serviceModel.Contract, error)
+	GetContractsByUser(ctx context.Context, userID int64, filter serviceModel.ContractFilter) (*serviceModel.ContractList, error)
+	RegisterKey(ctx context.Context, userID int64, publicKey string) (*serviceModel.SigningKey, error)
+	IssueKey(ctx context.Context, userID int64) (*serviceModel.IssuedKey, error)
+	Keys(ctx context.Context, userID int64) ([]*serviceModel.SigningKey, error)
+	VerifyContract(ctx context.Context, contractID, userID int64, role int) (*serviceModel.Verification, error)
+	CreateTemplate(ctx context.Context, t *serviceModel.Template) (int64, error)
+	UpdateTemplate(ctx context.Context, id int64, name, body string, adminID int64) (int, error)
+	SetDefaultTemplate(ctx context.Context, id int64) error
+	Template(ctx context.Context, id int64, version int) (*serviceModel.Template, error)
+	Templates(ctx context.Context, supplierID int64) ([]*serviceModel.Template, error)
+	TemplateVersions(ctx context.Context, id int64) ([]serviceModel.TemplateVersion, error)
+	ContractPDF(ctx context.Context, contractID, userID int64, role int) ([]byte, string, error)
+	RejectContract(ctx context.Context, contractID, userID int64, reason string) error
+	AmendContract(ctx context.Context, contractID, supplierID int64, content string) (int64, error)
+	CreateAgreement(ctx context.Context, a *serviceModel.Agreement) (int64, error)
+	UpdateAgreement(ctx context.Context, a *serviceModel.Agreement) error
+	AcceptAgreement(ctx context.Context, id, customerID int64) error
+	TerminateAgreement(ctx context.Context, id, userID int64) error
+	Agreement(ctx context.Context, id, userID int64, role int) (*serviceModel.Agreement, error)
+	Agreements(ctx context.Context, userID int64) ([]*serviceModel.Agreement, error)
 }
 
 type Handler struct {
@@ -29,15 +51,18 @@ func NewHandler(service Service) *Handler {
 
 // Sign godoc
 // @Summary Sign the contract
-// @Description Signing the contract (by client or supplier)
+// @Description Signing the contract by its supplier or customer, once per party and in any order. The signature is the base64 ed25519 signature of the contract's content_hash bytes made with the caller's active signing key.
 // @Tags contracts
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param input body apiModel.SignRequest true "Contract ID and Signature"
 // @Success 200 {object} map[string]string "Signature saved"
-// @Failure 400 {object} map[string]string "Validation error"
+// @Failure 400 {object} map[string]string "Validation error or invalid signature"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not a party of the contract"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Failure 409 {object} map[string]string "Already signed, no longer open or contract content was altered"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /api/contract/sign [post]
 func (h *Handler) Sign(c *gin.Context) {
@@ -49,9 +74,9 @@ func (h *Handler) Sign(c *gin.Context) {
 		return
 	}
 
-	err := h.service.SignContract(c.Request.Context(), req.ContractID, claims.Role, req.Signature)
+	err := h.service.SignContract(c.Request.Context(), req.ContractID, claims.UserID, req.Signature)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -60,7 +85,7 @@ func (h *Handler) Sign(c *gin.Context) {
 
 // Get godoc
 // @Summary Get contract
-// @Description Returns the contract by ID
+// @Description Returns the contract by ID to its supplier, its customer or an admin
 // @Tags contracts
 // @Security ApiKeyAuth
 // @Accept json
@@ -69,9 +94,13 @@ func (h *Handler) Sign(c *gin.Context) {
 // @Success 200 {object} apiModel.ContractResponse "Contract"
 // @Failure 400 {object} map[string]string "Invalid ID"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not a party of the contract"
+// @Failure 404 {object} map[string]string "Contract not found"
 // @Failure 500 {object} map[string]string "Server error"
 // @Router /api/contract/{id} [get]
 func (h *Handler) Get(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
 	id := c.Param("id")
 	contractID, err := strconv.ParseInt(id, 10, 64)
 	if err != nil {
@@ -79,9 +108,9 @@ func (h *Handler) Get(c *gin.Context) {
 		return
 	}
 
-	contract, err := h.service.GetContract(c.Request.Context(), contractID)
+	contract, err := h.service.GetContract(c.Request.Context(), contractID, claims.UserID, claims.Role)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeError(c, err)
 		return
 	}
 
@@ -90,23 +119,125 @@ func (h *Handler) Get(c *gin.Context) {
 
 // GetContractList godoc
 // @Summary List of contracts for the current user
+// @Description Returns one page of the caller's contracts with the total and per-status counts. The counts ignore the status filter, so they can be shown as tabs.
 // @Tags contracts
 // @Security ApiKeyAuth
-// @Success 200 {array} apiModel.ContractResponse
+// @Produce json
+// @Param status query []int false "Status, may be repeated" collectionFormat(multi)
+// @Param counterparty_id query int false "The other party: supplier for a customer, customer for a supplier"
+// @Param order_id query int false "Order ID"
+// @Param date_from query string false "Contracts created on or after this day (YYYY-MM-DD)"
+// @Param date_to query string false "Contracts created on or before this day (YYYY-MM-DD)"
+// @Param sort query string false "created_desc (default), created_asc, expires_asc or expires_desc"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} apiModel.ContractListResponse
+// @Failure 400 {object} map[string]string "Invalid filter"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
 // @Router /api/contract [get]
 func (h *Handler) GetList(c *gin.Context) {
 	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
 
-	contracts, err := h.service.GetContractsByUser(c.Request.Context(), claims.UserID)
+	filter, err := parseContractFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.service.GetContractsByUser(c.Request.Context(), claims.UserID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	var res []apiModel.ContractResponse
-	for _, c := range contracts {
-		res = append(res, *contractConverter.ToAPI(c))
+	c.JSON(http.StatusOK, contractConverter.ToAPIList(list))
+}
+
+// parseContractFilter reads the contract filter from the query string.
+func parseContractFilter(c *gin.Context) (serviceModel.ContractFilter, error) {
+	var filter serviceModel.ContractFilter
+
+	seen := make(map[int]bool)
+	for _, status := range c.QueryArray("status") {
+		statusID, err := strconv.Atoi(status)
+		if err != nil || statusID < serviceModel.StatusCreated || statusID > serviceModel.StatusExpired {
+			return filter, fmt.Errorf("invalid status")
+		}
+		if !seen[statusID] {
+			seen[statusID] = true
+			filter.Statuses = append(filter.Statuses, statusID)
+		}
+	}
+
+	var err error
+	if filter.CounterpartyID, err = queryInt64(c, "counterparty_id"); err != nil {
+		return filter, err
+	}
+	if filter.OrderID, err = queryInt64(c, "order_id"); err != nil {
+		return filter, err
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := time.Parse(time.DateOnly, dateFrom)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_from, expected YYYY-MM-DD")
+		}
+		filter.DateFrom = t
+	}
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := time.Parse(time.DateOnly, dateTo)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_to, expected YYYY-MM-DD")
+		}
+		filter.DateTo = t.AddDate(0, 0, 1)
+	}
+
+	switch sort := c.Query("sort"); sort {
+	case "", serviceModel.SortCreatedDesc, serviceModel.SortCreatedAsc, serviceModel.SortExpiresAsc, serviceModel.SortExpiresDesc:
+		filter.Sort = sort
+	default:
+		return filter, fmt.Errorf("invalid sort")
+	}
+
+	limit, err := queryInt64(c, "limit")
+	if err != nil {
+		return filter, err
+	}
+	offset, err := queryInt64(c, "offset")
+	if err != nil {
+		return filter, err
+	}
+	filter.Limit, filter.Offset = int(limit), int(offset)
+
+	return filter, nil
+}
+
+func queryInt64(c *gin.Context, name string) (int64, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s", name)
 	}
+	return n, nil
+}
 
-	c.JSON(http.StatusOK, res)
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceModel.ErrInvalidSignature), errors.Is(err, serviceModel.ErrNoSigningKey),
+		errors.Is(err, serviceModel.ErrReasonRequired), errors.Is(err, serviceModel.ErrEmptyContent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, serviceModel.ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"error": "you are not a party of this contract"})
+	case errors.Is(err, serviceModel.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "contract not found"})
+	case errors.Is(err, serviceModel.ErrContentTampered), errors.Is(err, serviceModel.ErrAlreadySigned),
+		errors.Is(err, serviceModel.ErrContractClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
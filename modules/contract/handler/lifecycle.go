@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	apiModel "diploma/modules/contract/handler/model"
+	contextkeys "diploma/pkg/context-keys"
+	"github.com/gin-gonic/gin"
+)
+
+// Reject godoc
+// @Summary Reject contract
+// @Description Either party can reject an open contract with a reason. The order the contract was made for is cancelled.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param input body apiModel.RejectRequest true "Reason"
+// @Success 200 {object} map[string]string "contract rejected"
+// @Failure 400 {object} map[string]string "Invalid ID or missing reason"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not a party of the contract"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Failure 409 {object} map[string]string "Contract is no longer open"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/{id}/reject [post]
+func (h *Handler) Reject(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	contractID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contract ID"})
+		return
+	}
+
+	var req apiModel.RejectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.RejectContract(c.Request.Context(), contractID, claims.UserID, req.Reason); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "contract rejected"})
+}
+
+// Amend godoc
+// @Summary Amend contract
+// @Description Supplier only. Replaces an open contract with a new unsigned version carrying the given content; signatures on the earlier version no longer count.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Param input body apiModel.AmendRequest true "New content"
+// @Success 200 {object} apiModel.AmendResponse
+// @Failure 400 {object} map[string]string "Invalid ID or empty content"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not the contract's supplier"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Failure 409 {object} map[string]string "Contract is no longer open"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/{id}/amend [post]
+func (h *Handler) Amend(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	contractID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contract ID"})
+		return
+	}
+
+	var req apiModel.AmendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := h.service.AmendContract(c.Request.Context(), contractID, claims.UserID, req.Content)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiModel.AmendResponse{ID: id})
+}
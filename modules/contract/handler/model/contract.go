@@ -6,9 +6,153 @@ type SignRequest struct {
 }
 
 type ContractResponse struct {
-	ID          int64  `json:"id"`
-	Content     string `json:"content"`
-	Status      int    `json:"status"`
-	SupplierSig string `json:"supplier_signature,omitempty"`
-	CustomerSig string `json:"customer_signature,omitempty"`
+	ID           int64  `json:"id"`
+	OrderID      int64  `json:"order_id"`
+	Content      string `json:"content"`
+	Status       int    `json:"status"`
+	ContentHash  string `json:"content_hash"`
+	SupplierSig  string `json:"supplier_signature,omitempty"`
+	CustomerSig  string `json:"customer_signature,omitempty"`
+	Version      int    `json:"version"`
+	PreviousID   int64  `json:"previous_id,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	RejectedBy   int64  `json:"rejected_by,omitempty"`
+	RejectReason string `json:"reject_reason,omitempty"`
+	RejectedAt   string `json:"rejected_at,omitempty"`
+	AgreementID  int64  `json:"agreement_id,omitempty"`
+}
+
+type ContractListResponse struct {
+	Contracts []ContractResponse `json:"contracts"`
+	// Total is the number of contracts matching the filter across all pages.
+	Total int `json:"total"`
+	// StatusCounts counts contracts per status name, ignoring the status filter.
+	StatusCounts      map[string]int `json:"status_counts"`
+	AwaitingSignature int            `json:"awaiting_signature"`
+}
+
+type RejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AmendRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+type AmendResponse struct {
+	// ID is the new version of the contract.
+	ID int64 `json:"id"`
+}
+
+type RegisterKeyRequest struct {
+	// PublicKey is a base64 ed25519 public key.
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+type SigningKeyResponse struct {
+	ID             int64  `json:"id"`
+	PublicKey      string `json:"public_key"`
+	Algorithm      string `json:"algorithm"`
+	IssuedByServer bool   `json:"issued_by_server"`
+	CreatedAt      string `json:"created_at"`
+	RevokedAt      string `json:"revoked_at,omitempty"`
+}
+
+type IssuedKeyResponse struct {
+	Key SigningKeyResponse `json:"key"`
+	// PrivateKey is the base64 ed25519 private key. It is not stored and
+	// is shown only in this response.
+	PrivateKey string `json:"private_key"`
+}
+
+type PartyVerification struct {
+	Signed bool   `json:"signed"`
+	KeyID  int64  `json:"key_id,omitempty"`
+	Valid  bool   `json:"valid"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type VerificationResponse struct {
+	ContractID    int64             `json:"contract_id"`
+	Valid         bool              `json:"valid"`
+	ContentHash   string            `json:"content_hash"`
+	RecordedHash  string            `json:"recorded_hash"`
+	ContentIntact bool              `json:"content_intact"`
+	ContentReason string            `json:"content_reason,omitempty"`
+	Supplier      PartyVerification `json:"supplier"`
+	Customer      PartyVerification `json:"customer"`
+}
+
+type CreateTemplateRequest struct {
+	Name string `json:"name" binding:"required"`
+	// SupplierID binds the template to a supplier; omit for a global template.
+	SupplierID int64  `json:"supplier_id,omitempty"`
+	Body       string `json:"body" binding:"required"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+type UpdateTemplateRequest struct {
+	Name string `json:"name"`
+	Body string `json:"body" binding:"required"`
+}
+
+type CreateTemplateResponse struct {
+	ID int64 `json:"id"`
+}
+
+type UpdateTemplateResponse struct {
+	Version int `json:"version"`
+}
+
+type TemplateResponse struct {
+	ID         int64  `json:"id"`
+	SupplierID int64  `json:"supplier_id,omitempty"`
+	Name       string `json:"name"`
+	IsDefault  bool   `json:"is_default"`
+	Version    int    `json:"version"`
+	Body       string `json:"body"`
+	CreatedBy  int64  `json:"created_by"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type TemplateVersionResponse struct {
+	Version   int    `json:"version"`
+	Body      string `json:"body"`
+	CreatedBy int64  `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+type AgreementPrice struct {
+	ProductID int64 `json:"product_id" binding:"required"`
+	Price     int   `json:"price" binding:"required"`
+}
+
+type AgreementRequest struct {
+	// CustomerID is required when creating an agreement and ignored on update.
+	CustomerID int64 `json:"customer_id"`
+	// ValidFrom and ValidTo are dates in YYYY-MM-DD format; both days are included.
+	ValidFrom   string           `json:"valid_from" binding:"required"`
+	ValidTo     string           `json:"valid_to" binding:"required"`
+	PaymentDays int              `json:"payment_days"`
+	CreditLimit int              `json:"credit_limit"`
+	Prices      []AgreementPrice `json:"prices"`
+}
+
+type CreateAgreementResponse struct {
+	ID int64 `json:"id"`
+}
+
+type AgreementResponse struct {
+	ID           int64            `json:"id"`
+	SupplierID   int64            `json:"supplier_id"`
+	CustomerID   int64            `json:"customer_id"`
+	ValidFrom    string           `json:"valid_from"`
+	ValidTo      string           `json:"valid_to"`
+	PaymentDays  int              `json:"payment_days"`
+	CreditLimit  int              `json:"credit_limit"`
+	Status       string           `json:"status"`
+	CreatedAt    string           `json:"created_at"`
+	AcceptedAt   string           `json:"accepted_at,omitempty"`
+	TerminatedAt string           `json:"terminated_at,omitempty"`
+	Prices       []AgreementPrice `json:"prices,omitempty"`
 }
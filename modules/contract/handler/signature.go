@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	contractConverter "diploma/modules/contract/handler/converter"
+	apiModel "diploma/modules/contract/handler/model"
+	serviceModel "diploma/modules/contract/model"
+	contextkeys "diploma/pkg/context-keys"
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterKey godoc
+// @Summary Register signing key
+// @Description Makes the given ed25519 public key the caller's active signing key. The previous key is revoked but still verifies earlier signatures.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body apiModel.RegisterKeyRequest true "Public key"
+// @Success 200 {object} apiModel.SigningKeyResponse
+// @Failure 400 {object} map[string]string "Invalid public key"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/keys [post]
+func (h *Handler) RegisterKey(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	var req apiModel.RegisterKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	key, err := h.service.RegisterKey(c.Request.Context(), claims.UserID, req.PublicKey)
+	if err != nil {
+		if errors.Is(err, serviceModel.ErrInvalidPublicKey) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, contractConverter.ToAPIKey(key))
+}
+
+// IssueKey godoc
+// @Summary Issue signing key
+// @Description Generates an ed25519 key pair and makes it the caller's active signing key. The private key is returned only in this response and is not stored.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} apiModel.IssuedKeyResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/keys/issue [post]
+func (h *Handler) IssueKey(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	issued, err := h.service.IssueKey(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, apiModel.IssuedKeyResponse{
+		Key:        contractConverter.ToAPIKey(issued.Key),
+		PrivateKey: base64.StdEncoding.EncodeToString(issued.PrivateKey),
+	})
+}
+
+// GetKeys godoc
+// @Summary List signing keys
+// @Description Returns the caller's signing keys, newest first, including revoked ones.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} apiModel.SigningKeyResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/keys [get]
+func (h *Handler) GetKeys(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	keys, err := h.service.Keys(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	res := make([]apiModel.SigningKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		res = append(res, contractConverter.ToAPIKey(key))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// Verify godoc
+// @Summary Verify contract signatures
+// @Description Recomputes the contract hash and re-checks both signatures. valid is false when the content was altered after creation, no hash was recorded for it or a signature does not verify.
+// @Tags contracts
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Contract ID"
+// @Success 200 {object} apiModel.VerificationResponse
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Caller is not a party of the contract"
+// @Failure 404 {object} map[string]string "Contract not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/{id}/verify [get]
+func (h *Handler) Verify(c *gin.Context) {
+	claims := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+
+	contractID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid contract ID"})
+		return
+	}
+
+	verification, err := h.service.VerifyContract(c.Request.Context(), contractID, claims.UserID, claims.Role)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contractConverter.ToAPIVerification(verification))
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	contractConverter "diploma/modules/contract/handler/converter"
+	apiModel "diploma/modules/contract/handler/model"
+	serviceModel "diploma/modules/contract/model"
+	contextkeys "diploma/pkg/context-keys"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTemplate godoc
+// @Summary Create contract template
+// @Description Admin only. The body uses Go template syntax with the placeholders .OrderID, .Date, .Supplier/.Customer (.Name, .Phone), .Lines (.No, .Name, .Quantity, .Price, .Amount), .Subtotal, .DeliveryFee, .Total, .DeliveryAddress, .DeliveryDate, .MinOrderAmount, .FreeDeliveryAmount and the functions money and date.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body apiModel.CreateTemplateRequest true "Template"
+// @Success 200 {object} apiModel.CreateTemplateResponse
+// @Failure 400 {object} map[string]string "Invalid template"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates [post]
+func (h *Handler) CreateTemplate(c *gin.Context) {
+	claims, ok := adminClaims(c)
+	if !ok {
+		return
+	}
+
+	var req apiModel.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := h.service.CreateTemplate(c.Request.Context(), &serviceModel.Template{
+		SupplierID: req.SupplierID,
+		Name:       req.Name,
+		Body:       req.Body,
+		IsDefault:  req.IsDefault,
+		CreatedBy:  claims.UserID,
+	})
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiModel.CreateTemplateResponse{ID: id})
+}
+
+// UpdateTemplate godoc
+// @Summary Update contract template
+// @Description Admin only. Saves the body as a new version; contracts already created keep their version.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param input body apiModel.UpdateTemplateRequest true "Template"
+// @Success 200 {object} apiModel.UpdateTemplateResponse
+// @Failure 400 {object} map[string]string "Invalid template"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates/{id} [put]
+func (h *Handler) UpdateTemplate(c *gin.Context) {
+	claims, ok := adminClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	var req apiModel.UpdateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := h.service.UpdateTemplate(c.Request.Context(), id, req.Name, req.Body, claims.UserID)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiModel.UpdateTemplateResponse{Version: version})
+}
+
+// SetDefaultTemplate godoc
+// @Summary Make contract template the default
+// @Description Admin only. The template becomes the default of its supplier, or the global default for a global template.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {object} map[string]string "default template set"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates/{id}/default [post]
+func (h *Handler) SetDefaultTemplate(c *gin.Context) {
+	if _, ok := adminClaims(c); !ok {
+		return
+	}
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SetDefaultTemplate(c.Request.Context(), id); err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "default template set"})
+}
+
+// GetTemplates godoc
+// @Summary List contract templates
+// @Description Admin only. Returns templates with their latest version.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Produce json
+// @Param supplier_id query int false "Only templates of this supplier and global ones"
+// @Success 200 {array} apiModel.TemplateResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates [get]
+func (h *Handler) GetTemplates(c *gin.Context) {
+	if _, ok := adminClaims(c); !ok {
+		return
+	}
+
+	supplierID, _ := strconv.ParseInt(c.Query("supplier_id"), 10, 64)
+	templates, err := h.service.Templates(c.Request.Context(), supplierID)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	res := make([]apiModel.TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		res = append(res, contractConverter.ToAPITemplate(t))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// GetTemplate godoc
+// @Summary Get contract template
+// @Description Admin only. Returns the latest version unless a version is given.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Template ID"
+// @Param version query int false "Version"
+// @Success 200 {object} apiModel.TemplateResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Template not found"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates/{id} [get]
+func (h *Handler) GetTemplate(c *gin.Context) {
+	if _, ok := adminClaims(c); !ok {
+		return
+	}
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	version, _ := strconv.Atoi(c.Query("version"))
+	t, err := h.service.Template(c.Request.Context(), id, version)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, contractConverter.ToAPITemplate(t))
+}
+
+// GetTemplateVersions godoc
+// @Summary List contract template versions
+// @Description Admin only. Newest version first.
+// @Tags contract-templates
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Template ID"
+// @Success 200 {array} apiModel.TemplateVersionResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Server error"
+// @Router /api/contract/templates/{id}/versions [get]
+func (h *Handler) GetTemplateVersions(c *gin.Context) {
+	if _, ok := adminClaims(c); !ok {
+		return
+	}
+	id, ok := templateID(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.service.TemplateVersions(c.Request.Context(), id)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	res := make([]apiModel.TemplateVersionResponse, 0, len(versions))
+	for _, v := range versions {
+		res = append(res, contractConverter.ToAPITemplateVersion(v))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func writeTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, serviceModel.ErrInvalidTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, serviceModel.ErrNoRows):
+		c.JSON(http.StatusNotFound, gin.H{"error": "template not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func adminClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return nil, false
+	}
+	if claims.Role != serviceModel.AdminRole {
+		c.JSON(http.StatusForbidden, gin.H{"error": "only admins can manage contract templates"})
+		return nil, false
+	}
+	return claims, true
+}
+
+func templateID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidAgreement = errors.New("models: invalid framework agreement")
+
+	ErrAgreementOverlap = errors.New("models: the parties already have an active agreement for this period")
+
+	ErrAgreementNotPending = errors.New("models: agreement is no longer pending")
+)
+
+const (
+	AgreementPending    = "pending"
+	AgreementActive     = "active"
+	AgreementTerminated = "terminated"
+)
+
+// Agreement is a framework contract between a supplier and a customer.
+// While active, its prices replace the supplier's list prices for the
+// customer and per-order contracts are issued as specifications to it.
+type Agreement struct {
+	ID         int64
+	SupplierID int64
+	CustomerID int64
+	// ValidFrom and ValidTo are dates; both days are included.
+	ValidFrom time.Time
+	ValidTo   time.Time
+	// PaymentDays is the deferred payment term; 0 means prepayment.
+	PaymentDays int
+	// CreditLimit is the outstanding amount allowed; 0 means no limit.
+	CreditLimit  int
+	Status       string
+	CreatedAt    time.Time
+	AcceptedAt   sql.NullTime
+	TerminatedAt sql.NullTime
+
+	Prices []AgreementPrice
+}
+
+type AgreementPrice struct {
+	ProductID int64
+	Price     int
+}
+
+func (a *Agreement) Validate() error {
+	if a.SupplierID == 0 || a.CustomerID == 0 || a.SupplierID == a.CustomerID {
+		return ErrInvalidAgreement
+	}
+	if a.ValidFrom.IsZero() || a.ValidTo.Before(a.ValidFrom) {
+		return ErrInvalidAgreement
+	}
+	if a.PaymentDays < 0 || a.CreditLimit < 0 {
+		return ErrInvalidAgreement
+	}
+	return ValidatePrices(a.Prices)
+}
+
+// ValidatePrices rejects non-positive prices and repeated products.
+func ValidatePrices(prices []AgreementPrice) error {
+	seen := make(map[int64]bool, len(prices))
+	for _, p := range prices {
+		if p.ProductID == 0 || p.Price <= 0 || seen[p.ProductID] {
+			return ErrInvalidAgreement
+		}
+		seen[p.ProductID] = true
+	}
+	return nil
+}
+
+// ActiveAt reports whether the agreement applies on the day of t.
+func (a *Agreement) ActiveAt(t time.Time) bool {
+	if a.Status != AgreementActive {
+		return false
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	from := time.Date(a.ValidFrom.Year(), a.ValidFrom.Month(), a.ValidFrom.Day(), 0, 0, 0, 0, time.UTC)
+	to := time.Date(a.ValidTo.Year(), a.ValidTo.Month(), a.ValidTo.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(from) && !day.After(to)
+}
+
+// Party returns the role the user holds in the agreement.
+func (a *Agreement) Party(userID int64) (role int, ok bool) {
+	switch userID {
+	case a.SupplierID:
+		return SupplierRole, true
+	case a.CustomerID:
+		return CustomerRole, true
+	}
+	return 0, false
+}
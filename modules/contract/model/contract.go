@@ -2,14 +2,28 @@ package model
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 )
 
+var (
+	ErrAlreadySigned = errors.New("models: contract is already signed by this party")
+
+	ErrContractClosed = errors.New("models: contract is no longer open")
+
+	ErrReasonRequired = errors.New("models: a reason is required")
+
+	ErrEmptyContent = errors.New("models: contract content is empty")
+)
+
 const (
 	StatusCreated = iota
 	StatusSignedBySupplier
 	StatusSignedByCustomer
 	StatusCompleted
+	StatusRejected
+	StatusAmended
+	StatusExpired
 )
 
 type Contract struct {
@@ -23,6 +37,76 @@ type Contract struct {
 	Status      int
 	CreatedAt   time.Time
 	SignedAt    sql.NullTime
+
+	// ContentHash is the hex canonical hash recorded at creation.
+	ContentHash      string
+	SupplierKeyID    sql.NullInt64
+	CustomerKeyID    sql.NullInt64
+	SupplierSignedAt sql.NullTime
+	CustomerSignedAt sql.NullTime
+
+	// TemplateID and TemplateVersion identify the template the content was
+	// rendered from; both are NULL for the built-in template.
+	TemplateID      sql.NullInt64
+	TemplateVersion sql.NullInt64
+
+	// PDFHash is the hex SHA-256 of the archived PDF, set once the
+	// contract is completed.
+	PDFHash sql.NullString
+
+	// PreviousID links an amendment to the contract it replaces; Version
+	// counts amendments starting at 1.
+	PreviousID sql.NullInt64
+	Version    int
+	// ExpiresAt is when an unsigned contract expires. NULL never expires.
+	ExpiresAt sql.NullTime
+
+	RejectedBy   sql.NullInt64
+	RejectReason sql.NullString
+	RejectedAt   sql.NullTime
+
+	// AgreementID is the framework agreement the contract is a
+	// specification to.
+	AgreementID sql.NullInt64
+}
+
+// Open reports whether the contract can still be signed, rejected or
+// amended at t.
+func (c *Contract) Open(t time.Time) bool {
+	if c.Status >= StatusCompleted {
+		return false
+	}
+	return !c.ExpiresAt.Valid || t.Before(c.ExpiresAt.Time)
+}
+
+// Party returns the role the user holds in the contract: SupplierRole or
+// CustomerRole. ok is false for users who are not a party.
+func (c *Contract) Party(userID int64) (role int, ok bool) {
+	switch userID {
+	case c.SupplierID:
+		return SupplierRole, true
+	case c.CustomerID:
+		return CustomerRole, true
+	}
+	return 0, false
+}
+
+// CanRead reports whether the user may see the contract. Only its parties
+// and admins can.
+func (c *Contract) CanRead(userID int64, role int) bool {
+	if role == AdminRole {
+		return true
+	}
+	_, ok := c.Party(userID)
+	return ok
+}
+
+// SignedBy reports whether the party holding role has signed.
+func (c *Contract) SignedBy(role int) bool {
+	if role == SupplierRole {
+		return c.SupplierSig.Valid
+	}
+	return c.CustomerSig.Valid
 }
 
 type SignatureRequest struct {
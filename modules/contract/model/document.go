@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Document is everything printed in the contract PDF.
+type Document struct {
+	Contract *Contract
+	Supplier *Party
+	Customer *Party
+}
+
+// Archive is the PDF of a completed contract as it was when the last
+// party signed. Later downloads return these exact bytes.
+type Archive struct {
+	ContractID int64
+	PDF        []byte
+	SHA256     string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	SortCreatedDesc = "created_desc"
+	SortCreatedAsc  = "created_asc"
+	SortExpiresAsc  = "expires_asc"
+	SortExpiresDesc = "expires_desc"
+)
+
+// ContractFilter narrows the contracts of one user. Zero fields do not
+// filter.
+type ContractFilter struct {
+	Statuses []int
+	// CounterpartyID is the other side: the supplier for a customer and
+	// the customer for a supplier.
+	CounterpartyID int64
+	OrderID        int64
+	// DateFrom and DateTo bound the creation time; DateTo is exclusive.
+	DateFrom time.Time
+	DateTo   time.Time
+
+	Sort   string
+	Limit  int
+	Offset int
+}
+
+type ContractList struct {
+	Contracts []*Contract
+	// Total is the number of contracts matching the filter.
+	Total int
+	// StatusCounts counts the contracts per status, applying every filter
+	// except the status one.
+	StatusCounts map[int]int
+	// AwaitingSignature counts open contracts the user has not signed yet.
+	AwaitingSignature int
+}
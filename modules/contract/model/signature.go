@@ -0,0 +1,101 @@
+package model
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNoRows = errors.New("models: no rows")
+
+	ErrInvalidPublicKey = errors.New("models: invalid ed25519 public key")
+
+	ErrNoSigningKey = errors.New("models: no active signing key, register or issue one first")
+
+	ErrInvalidSignature = errors.New("models: signature does not match the contract hash")
+
+	ErrContentTampered = errors.New("models: contract content does not match its recorded hash")
+)
+
+const (
+	KeyAlgorithmEd25519 = "ed25519"
+
+	// canonicalVersion is part of the hashed text so the format can change
+	// without old signatures being misread.
+	canonicalVersion = "diploma-contract-v1"
+)
+
+// SigningKey is a public key a user signs contracts with. Only one key per
+// user is active; revoked keys are kept to verify earlier signatures.
+type SigningKey struct {
+	ID             int64
+	UserID         int64
+	PublicKey      ed25519.PublicKey
+	Algorithm      string
+	IssuedByServer bool
+	CreatedAt      time.Time
+	RevokedAt      *time.Time
+}
+
+// IssuedKey is a key pair generated by the server. The private key is
+// returned to the user once and never stored.
+type IssuedKey struct {
+	Key        *SigningKey
+	PrivateKey ed25519.PrivateKey
+}
+
+// CanonicalHash is the SHA-256 digest both parties sign. It covers the
+// contract identity, the parties and the content; line endings are
+// normalized so the same text hashes the same on every platform.
+func (c *Contract) CanonicalHash() []byte {
+	content := strings.ReplaceAll(c.Content, "\r\n", "\n")
+
+	var b strings.Builder
+	b.WriteString(canonicalVersion + "\n")
+	fmt.Fprintf(&b, "id:%d\n", c.ID)
+	fmt.Fprintf(&b, "order_id:%d\n", c.OrderID)
+	fmt.Fprintf(&b, "supplier_id:%d\n", c.SupplierID)
+	fmt.Fprintf(&b, "customer_id:%d\n", c.CustomerID)
+	fmt.Fprintf(&b, "content:%d:%s", len(content), content)
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return sum[:]
+}
+
+func (c *Contract) CanonicalHashHex() string {
+	return hex.EncodeToString(c.CanonicalHash())
+}
+
+// PartyVerification is the result of re-checking one party's signature.
+type PartyVerification struct {
+	Signed bool
+	KeyID  int64
+	Valid  bool
+	Reason string
+}
+
+// Verification is the result of re-checking a contract.
+type Verification struct {
+	ContractID   int64
+	ContentHash  string
+	RecordedHash string
+	// ContentIntact is true only when the content matches the hash
+	// recorded when the contract was created; ContentReason says why not.
+	ContentIntact bool
+	ContentReason string
+	Supplier      PartyVerification
+	Customer      PartyVerification
+}
+
+// Valid reports whether the content is intact and every present
+// signature verifies.
+func (v *Verification) Valid() bool {
+	return v.ContentIntact &&
+		(!v.Supplier.Signed || v.Supplier.Valid) &&
+		(!v.Customer.Signed || v.Customer.Valid)
+}
@@ -0,0 +1,177 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+var (
+	ErrInvalidTemplate = errors.New("models: invalid contract template")
+
+	ErrForbidden = errors.New("models: access denied")
+)
+
+const (
+	CustomerRole = iota
+	SupplierRole
+	AdminRole
+)
+
+// Template is an admin-managed contract text. Editing a template adds a new
+// version; contracts keep the version they were rendered from. A template
+// without SupplierID is global and used for suppliers without a default of
+// their own.
+type Template struct {
+	ID         int64
+	SupplierID int64
+	Name       string
+	IsDefault  bool
+	Version    int
+	Body       string
+	CreatedBy  int64
+	CreatedAt  time.Time
+}
+
+// TemplateVersion is one saved revision of a template body.
+type TemplateVersion struct {
+	TemplateID int64
+	Version    int
+	Body       string
+	CreatedBy  int64
+	CreatedAt  time.Time
+}
+
+// Party is a contract side as printed in the contract.
+type Party struct {
+	ID    int64
+	Name  string
+	Phone string
+}
+
+type OrderLine struct {
+	No       int
+	Name     string
+	Quantity int
+	Price    int
+	Amount   int
+}
+
+// OrderDetails holds everything a template can refer to. Templates use Go
+// text/template syntax, e.g. {{.Supplier.Name}}, {{range .Lines}}...{{end}},
+// {{money .Total}} or {{date .DeliveryDate}}.
+type OrderDetails struct {
+	OrderID    int64
+	SupplierID int64
+	CustomerID int64
+
+	Supplier Party
+	Customer Party
+
+	Lines       []OrderLine
+	Subtotal    int
+	DeliveryFee int
+	Total       int
+
+	// delivery terms
+	DeliveryAddress    string
+	DeliveryDate       time.Time
+	MinOrderAmount     int
+	FreeDeliveryAmount int
+
+	// Date is the contract date.
+	Date time.Time
+
+	// Agreement is the framework agreement the order falls under, if any.
+	Agreement *Agreement
+}
+
+var templateFuncs = template.FuncMap{
+	"money": func(amount int) string {
+		return fmt.Sprintf("%d ₸", amount)
+	},
+	"date": func(t time.Time) string {
+		if t.IsZero() {
+			return "—"
+		}
+		return t.Format("02.01.2006")
+	},
+}
+
+// DefaultTemplateBody is used when neither the supplier nor the platform
+// has a default template.
+const DefaultTemplateBody = `ДОГОВОР ПОСТАВКИ № {{.OrderID}}
+Дата: {{date .Date}}
+
+Поставщик: {{.Supplier.Name}}, тел. {{.Supplier.Phone}}
+Покупатель: {{.Customer.Name}}, тел. {{.Customer.Phone}}
+
+1. Предмет договора
+Поставщик обязуется поставить, а Покупатель принять и оплатить товары:
+{{range .Lines}}{{.No}}. {{.Name}} — {{.Quantity}} шт. × {{money .Price}} = {{money .Amount}}
+{{end}}
+2. Стоимость
+Стоимость товаров: {{money .Subtotal}}
+Доставка: {{money .DeliveryFee}}
+Итого к оплате: {{money .Total}}
+
+3. Условия поставки
+Адрес доставки: {{.DeliveryAddress}}
+Дата доставки: {{date .DeliveryDate}}
+Минимальная сумма заказа: {{money .MinOrderAmount}}
+Бесплатная доставка от {{money .FreeDeliveryAmount}}
+`
+
+// SpecificationTemplateBody is used for orders placed under an active
+// framework agreement: the order contract only lists the goods and refers
+// to the agreement for everything else.
+const SpecificationTemplateBody = `СПЕЦИФИКАЦИЯ К ЗАКАЗУ № {{.OrderID}}
+к рамочному договору № {{.Agreement.ID}} от {{date .Agreement.ValidFrom}}
+Дата: {{date .Date}}
+
+Поставщик: {{.Supplier.Name}}, тел. {{.Supplier.Phone}}
+Покупатель: {{.Customer.Name}}, тел. {{.Customer.Phone}}
+
+1. Товары
+{{range .Lines}}{{.No}}. {{.Name}} — {{.Quantity}} шт. × {{money .Price}} = {{money .Amount}}
+{{end}}
+2. Стоимость
+Стоимость товаров: {{money .Subtotal}}
+Доставка: {{money .DeliveryFee}}
+Итого: {{money .Total}}
+
+3. Условия
+Адрес доставки: {{.DeliveryAddress}}
+Дата доставки: {{date .DeliveryDate}}
+Оплата: {{if .Agreement.PaymentDays}}отсрочка {{.Agreement.PaymentDays}} дн. с даты поставки{{else}}предоплата{{end}}
+Остальные условия определяются рамочным договором № {{.Agreement.ID}}, действующим до {{date .Agreement.ValidTo}}.
+`
+
+// Render fills the template body with the order details.
+func Render(body string, details *OrderDetails) (string, error) {
+	tmpl, err := template.New("contract").Funcs(templateFuncs).Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, details); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	return buf.String(), nil
+}
+
+// ValidateBody checks that the body parses and only refers to known
+// placeholders by rendering it with sample data.
+func ValidateBody(body string) error {
+	if body == "" {
+		return ErrInvalidTemplate
+	}
+	_, err := Render(body, &OrderDetails{
+		Lines: []OrderLine{{No: 1, Name: "sample", Quantity: 1, Price: 1, Amount: 1}},
+		Date:  time.Now(),
+	})
+	return err
+}
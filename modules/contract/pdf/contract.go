@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"diploma/modules/contract/model"
+	"diploma/pkg/pdf"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+const timestampLayout = "02.01.2006 15:04:05 MST"
+
+type ContractRenderer struct {
+	fontDir string
+}
+
+func NewContractRenderer(fontDir string) *ContractRenderer {
+	return &ContractRenderer{fontDir: fontDir}
+}
+
+// Render builds the contract document. The output only depends on the
+// contract and the parties, so the PDF of a completed contract can be
+// rendered again and compared byte for byte with the archived one.
+func (r *ContractRenderer) Render(document *model.Document) ([]byte, error) {
+	contract := document.Contract
+
+	doc, err := pdf.New(r.fontDir)
+	if err != nil {
+		return nil, err
+	}
+	date := contract.CreatedAt
+	if contract.SignedAt.Valid {
+		date = contract.SignedAt.Time
+	}
+	doc.SetFixedDates(date)
+	title := fmt.Sprintf("Договор №%d", contract.ID)
+	doc.SetTitle(title, true)
+	doc.AddPage()
+
+	doc.SetFont(pdf.FontFamily, "B", 16)
+	doc.CellFormat(0, 10, title, "", 1, "L", false, 0, "")
+	doc.SetFont(pdf.FontFamily, "", 10)
+	doc.CellFormat(0, 6, "Дата: "+contract.CreatedAt.Format("02.01.2006"), "", 1, "L", false, 0, "")
+	doc.CellFormat(0, 6, fmt.Sprintf("К заказу №%d", contract.OrderID), "", 1, "L", false, 0, "")
+	if contract.PreviousID.Valid {
+		doc.CellFormat(0, 6, fmt.Sprintf("Редакция %d, заменяет договор №%d", contract.Version, contract.PreviousID.Int64), "", 1, "L", false, 0, "")
+	}
+	doc.Ln(4)
+
+	party(doc, "Поставщик", document.Supplier)
+	party(doc, "Покупатель", document.Customer)
+	doc.Ln(4)
+
+	doc.MultiCell(0, 5, contract.Content, "", "L", false)
+	doc.Ln(6)
+
+	doc.SetFont(pdf.FontFamily, "B", 12)
+	doc.CellFormat(0, 8, "Подписи сторон", "", 1, "L", false, 0, "")
+	doc.SetFont(pdf.FontFamily, "", 10)
+	doc.CellFormat(0, 6, "Статус: "+status(contract.Status), "", 1, "L", false, 0, "")
+	signature(doc, "Поставщик", contract.SupplierSig.Valid, contract.SupplierSignedAt.Time, contract.SupplierKeyID.Int64)
+	signature(doc, "Покупатель", contract.CustomerSig.Valid, contract.CustomerSignedAt.Time, contract.CustomerKeyID.Int64)
+	if contract.RejectReason.Valid {
+		doc.MultiCell(0, 6, "Причина отклонения: "+contract.RejectReason.String, "", "L", false)
+	}
+	if contract.ContentHash != "" {
+		doc.Ln(2)
+		doc.SetFont(pdf.FontFamily, "", 8)
+		doc.MultiCell(0, 5, "Хеш содержания (SHA-256): "+contract.ContentHash, "", "L", false)
+	}
+
+	return doc.Bytes()
+}
+
+func party(doc *pdf.Document, title string, p *model.Party) {
+	doc.SetFont(pdf.FontFamily, "B", 10)
+	doc.CellFormat(30, 6, title+":", "", 0, "L", false, 0, "")
+	doc.SetFont(pdf.FontFamily, "", 10)
+	line := p.Name
+	if line == "" {
+		line = "ID " + strconv.FormatInt(p.ID, 10)
+	}
+	if p.Phone != "" {
+		line += ", тел. " + p.Phone
+	}
+	doc.MultiCell(0, 6, line, "", "L", false)
+}
+
+func signature(doc *pdf.Document, title string, signed bool, at time.Time, keyID int64) {
+	line := "не подписан"
+	if signed {
+		line = "подписан " + at.Format(timestampLayout)
+		if keyID != 0 {
+			line += fmt.Sprintf(", ключ №%d", keyID)
+		}
+	}
+	doc.CellFormat(30, 6, title+":", "", 0, "L", false, 0, "")
+	doc.CellFormat(0, 6, line, "", 1, "L", false, 0, "")
+}
+
+func status(s int) string {
+	switch s {
+	case model.StatusSignedBySupplier:
+		return "подписан поставщиком"
+	case model.StatusSignedByCustomer:
+		return "подписан покупателем"
+	case model.StatusCompleted:
+		return "подписан обеими сторонами"
+	case model.StatusRejected:
+		return "отклонён"
+	case model.StatusAmended:
+		return "заменён новой редакцией"
+	case model.StatusExpired:
+		return "истёк срок подписания"
+	default:
+		return "ожидает подписания"
+	}
+}
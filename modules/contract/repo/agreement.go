@@ -0,0 +1,272 @@
+package repo
+
+import (
+	"context"
+	"diploma/modules/contract/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	agreementTable      = "framework_agreements"
+	agreementPriceTable = "framework_agreement_prices"
+)
+
+var agreementColumns = []string{
+	"id", "supplier_id", "customer_id", "valid_from", "valid_to", "payment_days", "credit_limit",
+	"status", "created_at", "accepted_at", "terminated_at",
+}
+
+func (r *Repository) CreateAgreement(ctx context.Context, a *model.Agreement) (int64, error) {
+	query, args, _ := sq.Insert(agreementTable).
+		Columns("supplier_id", "customer_id", "valid_from", "valid_to", "payment_days", "credit_limit", "status").
+		Values(a.SupplierID, a.CustomerID, a.ValidFrom, a.ValidTo, a.PaymentDays, a.CreditLimit, a.Status).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var id int64
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.create_agreement", QueryRaw: query}, args...).Scan(&id)
+	return id, err
+}
+
+// UpdateAgreementTerms changes the period and credit terms of an agreement.
+func (r *Repository) UpdateAgreementTerms(ctx context.Context, a *model.Agreement) error {
+	query, args, _ := sq.Update(agreementTable).
+		Set("valid_from", a.ValidFrom).
+		Set("valid_to", a.ValidTo).
+		Set("payment_days", a.PaymentDays).
+		Set("credit_limit", a.CreditLimit).
+		Where(sq.Eq{"id": a.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.update_agreement_terms", QueryRaw: query}, args...)
+	return err
+}
+
+// SetAgreementStatus moves the agreement to status and stamps the time the
+// customer accepted or a party terminated it.
+func (r *Repository) SetAgreementStatus(ctx context.Context, id int64, status string) error {
+	update := sq.Update(agreementTable).
+		Set("status", status).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+
+	switch status {
+	case model.AgreementActive:
+		update = update.Set("accepted_at", sq.Expr("NOW()"))
+	case model.AgreementTerminated:
+		update = update.Set("terminated_at", sq.Expr("NOW()"))
+	}
+
+	query, args, _ := update.ToSql()
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.set_agreement_status", QueryRaw: query}, args...)
+	return err
+}
+
+// ReplaceAgreementPrices swaps the negotiated prices of the agreement.
+func (r *Repository) ReplaceAgreementPrices(ctx context.Context, agreementID int64, prices []model.AgreementPrice) error {
+	query, args, _ := sq.Delete(agreementPriceTable).
+		Where(sq.Eq{"agreement_id": agreementID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if _, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.delete_agreement_prices", QueryRaw: query}, args...); err != nil {
+		return err
+	}
+	if len(prices) == 0 {
+		return nil
+	}
+
+	insert := sq.Insert(agreementPriceTable).
+		Columns("agreement_id", "product_id", "price").
+		PlaceholderFormat(sq.Dollar)
+	for _, p := range prices {
+		insert = insert.Values(agreementID, p.ProductID, p.Price)
+	}
+
+	query, args, _ = insert.ToSql()
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.insert_agreement_prices", QueryRaw: query}, args...)
+	return err
+}
+
+func (r *Repository) AgreementByID(ctx context.Context, id int64) (*model.Agreement, error) {
+	return r.agreementByID(ctx, id, false)
+}
+
+// LockAgreement loads the agreement and locks its row until the
+// transaction ends.
+func (r *Repository) LockAgreement(ctx context.Context, id int64) (*model.Agreement, error) {
+	return r.agreementByID(ctx, id, true)
+}
+
+func (r *Repository) agreementByID(ctx context.Context, id int64, lock bool) (*model.Agreement, error) {
+	builder := sq.Select(agreementColumns...).
+		From(agreementTable).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar)
+	if lock {
+		builder = builder.Suffix("FOR UPDATE")
+	}
+
+	query, args, _ := builder.ToSql()
+	row := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.agreement_by_id", QueryRaw: query}, args...)
+
+	a, err := scanAgreement(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// AgreementsByUser returns the agreements the user is a party of, newest
+// first.
+func (r *Repository) AgreementsByUser(ctx context.Context, userID int64) ([]*model.Agreement, error) {
+	query, args, _ := sq.Select(agreementColumns...).
+		From(agreementTable).
+		Where(sq.Or{
+			sq.Eq{"supplier_id": userID},
+			sq.Eq{"customer_id": userID},
+		}).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.agreements_by_user", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var agreements []*model.Agreement
+	for rows.Next() {
+		a, err := scanAgreement(rows)
+		if err != nil {
+			return nil, err
+		}
+		agreements = append(agreements, a)
+	}
+	return agreements, rows.Err()
+}
+
+// ActiveAgreement returns the parties' active agreement covering the day
+// of at.
+func (r *Repository) ActiveAgreement(ctx context.Context, supplierID, customerID int64, at time.Time) (*model.Agreement, error) {
+	query, args, _ := sq.Select(agreementColumns...).
+		From(agreementTable).
+		Where(sq.Eq{
+			"supplier_id": supplierID,
+			"customer_id": customerID,
+			"status":      model.AgreementActive,
+		}).
+		Where(sq.Expr("?::date BETWEEN valid_from AND valid_to", at)).
+		OrderBy("valid_from DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	row := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.active_agreement", QueryRaw: query}, args...)
+
+	a, err := scanAgreement(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// HasOverlappingAgreement reports whether the parties have another active
+// agreement whose period intersects [from, to].
+func (r *Repository) HasOverlappingAgreement(ctx context.Context, a *model.Agreement) (bool, error) {
+	query, args, _ := sq.Select("1").
+		From(agreementTable).
+		Where(sq.Eq{
+			"supplier_id": a.SupplierID,
+			"customer_id": a.CustomerID,
+			"status":      model.AgreementActive,
+		}).
+		Where(sq.NotEq{"id": a.ID}).
+		Where(sq.LtOrEq{"valid_from": a.ValidTo}).
+		Where(sq.GtOrEq{"valid_to": a.ValidFrom}).
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var exists bool
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.overlapping_agreement", QueryRaw: query}, args...).Scan(&exists)
+	return exists, err
+}
+
+func (r *Repository) AgreementPrices(ctx context.Context, agreementID int64) ([]model.AgreementPrice, error) {
+	query, args, _ := sq.Select("product_id", "price").
+		From(agreementPriceTable).
+		Where(sq.Eq{"agreement_id": agreementID}).
+		OrderBy("product_id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.agreement_prices", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []model.AgreementPrice
+	for rows.Next() {
+		var p model.AgreementPrice
+		if err := rows.Scan(&p.ProductID, &p.Price); err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// NegotiatedPrice returns the product's price under the parties' agreement
+// active on the day of at.
+func (r *Repository) NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64, at time.Time) (int, error) {
+	query, args, _ := sq.Select("p.price").
+		From(agreementTable + " a").
+		Join(agreementPriceTable + " p ON p.agreement_id = a.id").
+		Where(sq.Eq{
+			"a.supplier_id": supplierID,
+			"a.customer_id": customerID,
+			"a.status":      model.AgreementActive,
+			"p.product_id":  productID,
+		}).
+		Where(sq.Expr("?::date BETWEEN a.valid_from AND a.valid_to", at)).
+		OrderBy("a.valid_from DESC").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var price int
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.negotiated_price", QueryRaw: query}, args...).Scan(&price)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, model.ErrNoRows
+	}
+	return price, err
+}
+
+func scanAgreement(row pgx.Row) (*model.Agreement, error) {
+	var a model.Agreement
+	err := row.Scan(
+		&a.ID, &a.SupplierID, &a.CustomerID, &a.ValidFrom, &a.ValidTo, &a.PaymentDays, &a.CreditLimit,
+		&a.Status, &a.CreatedAt, &a.AcceptedAt, &a.TerminatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -2,10 +2,14 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"diploma/modules/contract/model"
 	"diploma/pkg/client/db"
+	"errors"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
 )
 
 type Repository struct {
@@ -18,10 +22,21 @@ func NewRepository(db db.Client) *Repository {
 
 const contractTable = "contracts"
 
+var contractColumns = []string{
+	"id", "order_id", "supplier_id", "customer_id", "content", "supplier_sig", "customer_sig",
+	"status", "created_at", "signed_at",
+	"content_hash", "supplier_key_id", "customer_key_id", "supplier_signed_at", "customer_signed_at",
+	"template_id", "template_version", "pdf_sha256",
+	"previous_id", "version", "expires_at", "rejected_by", "reject_reason", "rejected_at",
+	"agreement_id",
+}
+
 func (r *Repository) Create(ctx context.Context, contract *model.Contract) (int64, error) {
 	query, args, _ := sq.Insert(contractTable).
-		Columns("order_id", "supplier_id", "customer_id", "content", "status", "created_at").
-		Values(contract.OrderID, contract.SupplierID, contract.CustomerID, contract.Content, contract.Status, contract.CreatedAt).
+		Columns("order_id", "supplier_id", "customer_id", "content", "status", "created_at", "template_id", "template_version",
+			"previous_id", "version", "expires_at", "agreement_id").
+		Values(contract.OrderID, contract.SupplierID, contract.CustomerID, contract.Content, contract.Status, contract.CreatedAt, contract.TemplateID, contract.TemplateVersion,
+			contract.PreviousID, contract.Version, contract.ExpiresAt, contract.AgreementID).
 		Suffix("RETURNING id").
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
@@ -31,43 +46,106 @@ func (r *Repository) Create(ctx context.Context, contract *model.Contract) (int6
 	return id, err
 }
 
-func (r *Repository) SignByParty(ctx context.Context, contractID int64, role int, signature string) error {
+// SignByParty stores the party's signature together with the key that
+// produced it and moves the status to the party's signing step. A party
+// that has already signed gets model.ErrAlreadySigned.
+func (r *Repository) SignByParty(ctx context.Context, contractID int64, role int, signature string, keyID int64) error {
 	update := sq.Update(contractTable).
 		Where(sq.Eq{"id": contractID}).
 		PlaceholderFormat(sq.Dollar)
 
-	if role == 0 {
-		update = update.Set("customer_sig", signature).Set("status", model.StatusSignedByCustomer)
-	} else if role == 1 {
-		update = update.Set("supplier_sig", signature).Set("status", model.StatusSignedBySupplier)
+	if role == model.CustomerRole {
+		update = update.Set("customer_sig", signature).
+			Set("customer_key_id", keyID).
+			Set("customer_signed_at", sq.Expr("NOW()")).
+			Set("status", model.StatusSignedByCustomer).
+			Where(sq.Eq{"customer_sig": nil})
+	} else {
+		update = update.Set("supplier_sig", signature).
+			Set("supplier_key_id", keyID).
+			Set("supplier_signed_at", sq.Expr("NOW()")).
+			Set("status", model.StatusSignedBySupplier).
+			Where(sq.Eq{"supplier_sig": nil})
 	}
 
 	query, args, _ := update.ToSql()
-	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.sign", QueryRaw: query}, args...)
+	tag, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.sign", QueryRaw: query}, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrAlreadySigned
+	}
+	return nil
+}
+
+func (r *Repository) SetContentHash(ctx context.Context, contractID int64, hash string) error {
+	query, args, _ := sq.Update(contractTable).
+		Set("content_hash", hash).
+		Where(sq.Eq{"id": contractID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.set_content_hash", QueryRaw: query}, args...)
 	return err
 }
 
 func (r *Repository) GetByID(ctx context.Context, id int64) (*model.Contract, error) {
-	query, args, _ := sq.Select("*").From(contractTable).
+	query, args, _ := sq.Select(contractColumns...).From(contractTable).
 		Where(sq.Eq{"id": id}).PlaceholderFormat(sq.Dollar).ToSql()
 
 	row := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.get", QueryRaw: query}, args...)
 
-	var c model.Contract
-	err := row.Scan(&c.ID, &c.OrderID, &c.SupplierID, &c.CustomerID, &c.Content, &c.SupplierSig, &c.CustomerSig, &c.Status, &c.CreatedAt, &c.SignedAt)
+	c, err := scanContract(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
 	if err != nil {
 		return nil, err
 	}
-	return &c, nil
+	return c, nil
 }
 
-func (r *Repository) GetByUser(ctx context.Context, userID int64) ([]*model.Contract, error) {
-	query, args, _ := sq.Select("*").
-		From(contractTable).
-		Where(sq.Or{
-			sq.Eq{"supplier_id": userID},
-			sq.Eq{"customer_id": userID},
-		}).
+// LockByID loads the contract and locks its row until the transaction ends.
+func (r *Repository) LockByID(ctx context.Context, id int64) (*model.Contract, error) {
+	query, args, _ := sq.Select(contractColumns...).From(contractTable).
+		Where(sq.Eq{"id": id}).Suffix("FOR UPDATE").PlaceholderFormat(sq.Dollar).ToSql()
+
+	row := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.lock", QueryRaw: query}, args...)
+
+	c, err := scanContract(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// contractSorts maps the allowed sort options to ORDER BY clauses.
+var contractSorts = map[string]string{
+	model.SortCreatedDesc: "created_at DESC, id DESC",
+	model.SortCreatedAsc:  "created_at ASC, id ASC",
+	model.SortExpiresAsc:  "expires_at ASC NULLS LAST, id ASC",
+	model.SortExpiresDesc: "expires_at DESC NULLS LAST, id DESC",
+}
+
+// GetByUser returns one page of the user's contracts matching the filter.
+func (r *Repository) GetByUser(ctx context.Context, userID int64, filter model.ContractFilter) ([]*model.Contract, error) {
+	orderBy, ok := contractSorts[filter.Sort]
+	if !ok {
+		orderBy = contractSorts[model.SortCreatedDesc]
+	}
+
+	builder := userContracts(sq.Select(contractColumns...).From(contractTable), userID, filter)
+	if len(filter.Statuses) > 0 {
+		builder = builder.Where(sq.Eq{"status": filter.Statuses})
+	}
+	query, args, _ := builder.
+		OrderBy(orderBy).
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset)).
 		PlaceholderFormat(sq.Dollar).
 		ToSql()
 
@@ -79,16 +157,11 @@ func (r *Repository) GetByUser(ctx context.Context, userID int64) ([]*model.Cont
 
 	var contracts []*model.Contract
 	for rows.Next() {
-		var c model.Contract
-		err := rows.Scan(
-			&c.ID, &c.OrderID, &c.SupplierID, &c.CustomerID,
-			&c.Content, &c.SupplierSig, &c.CustomerSig,
-			&c.Status, &c.CreatedAt, &c.SignedAt,
-		)
+		c, err := scanContract(rows)
 		if err != nil {
 			return nil, err
 		}
-		contracts = append(contracts, &c)
+		contracts = append(contracts, c)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -97,6 +170,73 @@ func (r *Repository) GetByUser(ctx context.Context, userID int64) ([]*model.Cont
 	return contracts, nil
 }
 
+// StatusCounts counts the user's contracts per status, applying every
+// filter except the status one, and the open contracts still waiting for
+// the user's signature.
+func (r *Repository) StatusCounts(ctx context.Context, userID int64, filter model.ContractFilter) (map[int]int, int, error) {
+	awaiting := sq.And{
+		sq.Eq{"status": []int{model.StatusCreated, model.StatusSignedBySupplier, model.StatusSignedByCustomer}},
+		sq.Or{sq.Eq{"expires_at": nil}, sq.Expr("expires_at > NOW()")},
+		sq.Or{
+			sq.Eq{"supplier_id": userID, "supplier_sig": nil},
+			sq.Eq{"customer_id": userID, "customer_sig": nil},
+		},
+	}
+	awaitingSQL, awaitingArgs, _ := awaiting.ToSql()
+
+	builder := sq.Select("status", "COUNT(*)").
+		Column(sq.Expr("COUNT(*) FILTER (WHERE "+awaitingSQL+")", awaitingArgs...)).
+		From(contractTable)
+	query, args, _ := userContracts(builder, userID, filter).
+		GroupBy("status").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.status_counts", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	total := 0
+	for rows.Next() {
+		var status, count, waiting int
+		if err := rows.Scan(&status, &count, &waiting); err != nil {
+			return nil, 0, err
+		}
+		counts[status] = count
+		total += waiting
+	}
+	return counts, total, rows.Err()
+}
+
+// userContracts restricts the query to the user's contracts matching the
+// filter, except for the status filter.
+func userContracts(builder sq.SelectBuilder, userID int64, filter model.ContractFilter) sq.SelectBuilder {
+	if filter.CounterpartyID != 0 {
+		builder = builder.Where(sq.Or{
+			sq.Eq{"supplier_id": userID, "customer_id": filter.CounterpartyID},
+			sq.Eq{"customer_id": userID, "supplier_id": filter.CounterpartyID},
+		})
+	} else {
+		builder = builder.Where(sq.Or{
+			sq.Eq{"supplier_id": userID},
+			sq.Eq{"customer_id": userID},
+		})
+	}
+	if filter.OrderID != 0 {
+		builder = builder.Where(sq.Eq{"order_id": filter.OrderID})
+	}
+	if !filter.DateFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{"created_at": filter.DateFrom})
+	}
+	if !filter.DateTo.IsZero() {
+		builder = builder.Where(sq.Lt{"created_at": filter.DateTo})
+	}
+	return builder
+}
+
 func (r *Repository) MarkAsSigned(ctx context.Context, contractID int64) error {
 	query, args, _ := sq.Update(contractTable).
 		Set("status", model.StatusCompleted).
@@ -111,3 +251,106 @@ func (r *Repository) MarkAsSigned(ctx context.Context, contractID int64) error {
 	}, args...)
 	return err
 }
+
+// Reject closes the contract with the rejecting user and the reason. A zero
+// userID records a rejection made by the system.
+func (r *Repository) Reject(ctx context.Context, contractID, userID int64, reason string) error {
+	query, args, _ := sq.Update(contractTable).
+		Set("status", model.StatusRejected).
+		Set("rejected_by", sql.NullInt64{Int64: userID, Valid: userID != 0}).
+		Set("reject_reason", reason).
+		Set("rejected_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": contractID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.reject", QueryRaw: query}, args...)
+	return err
+}
+
+// LockOpenByOrder loads and locks the order's contracts that are not in a
+// final state.
+func (r *Repository) LockOpenByOrder(ctx context.Context, orderID int64) ([]*model.Contract, error) {
+	query, args, _ := sq.Select(contractColumns...).
+		From(contractTable).
+		Where(sq.Eq{
+			"order_id": orderID,
+			"status":   []int{model.StatusCreated, model.StatusSignedBySupplier, model.StatusSignedByCustomer},
+		}).
+		OrderBy("id").
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.lock_open_by_order", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contracts []*model.Contract
+	for rows.Next() {
+		c, err := scanContract(rows)
+		if err != nil {
+			return nil, err
+		}
+		contracts = append(contracts, c)
+	}
+	return contracts, rows.Err()
+}
+
+func (r *Repository) SetStatus(ctx context.Context, contractID int64, status int) error {
+	query, args, _ := sq.Update(contractTable).
+		Set("status", status).
+		Where(sq.Eq{"id": contractID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.set_status", QueryRaw: query}, args...)
+	return err
+}
+
+// ExpiredIDs returns open contracts whose expiry has passed at now.
+func (r *Repository) ExpiredIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error) {
+	query, args, _ := sq.Select("id").
+		From(contractTable).
+		Where(sq.Eq{"status": []int{model.StatusCreated, model.StatusSignedBySupplier, model.StatusSignedByCustomer}}).
+		Where(sq.LtOrEq{"expires_at": now}).
+		OrderBy("expires_at").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.expired_ids", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func scanContract(row pgx.Row) (*model.Contract, error) {
+	var c model.Contract
+	err := row.Scan(
+		&c.ID, &c.OrderID, &c.SupplierID, &c.CustomerID,
+		&c.Content, &c.SupplierSig, &c.CustomerSig,
+		&c.Status, &c.CreatedAt, &c.SignedAt,
+		&c.ContentHash, &c.SupplierKeyID, &c.CustomerKeyID, &c.SupplierSignedAt, &c.CustomerSignedAt,
+		&c.TemplateID, &c.TemplateVersion, &c.PDFHash,
+		&c.PreviousID, &c.Version, &c.ExpiresAt, &c.RejectedBy, &c.RejectReason, &c.RejectedAt,
+		&c.AgreementID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
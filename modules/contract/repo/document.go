@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"diploma/modules/contract/model"
+	"diploma/pkg/client/db"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const documentTable = "contract_documents"
+
+// SaveArchive stores the final PDF and its hash. An archive is written
+// once; a second call for the same contract keeps the first document.
+func (r *Repository) SaveArchive(ctx context.Context, archive *model.Archive) error {
+	query, args, _ := sq.Insert(documentTable).
+		Columns("contract_id", "pdf", "sha256").
+		Values(archive.ContractID, archive.PDF, archive.SHA256).
+		Suffix("ON CONFLICT (contract_id) DO NOTHING").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	tag, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.save_archive", QueryRaw: query}, args...)
+	if err != nil || tag.RowsAffected() == 0 {
+		return err
+	}
+
+	query, args, _ = sq.Update(contractTable).
+		Set("pdf_sha256", archive.SHA256).
+		Where(sq.Eq{"id": archive.ContractID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err = r.db.DB().ExecContext(ctx, db.Query{Name: "contract.set_pdf_hash", QueryRaw: query}, args...)
+	return err
+}
+
+func (r *Repository) Archive(ctx context.Context, contractID int64) (*model.Archive, error) {
+	query, args, _ := sq.Select("contract_id", "pdf", "sha256", "created_at").
+		From(documentTable).
+		Where(sq.Eq{"contract_id": contractID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var a model.Archive
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.archive", QueryRaw: query}, args...).
+		Scan(&a.ContractID, &a.PDF, &a.SHA256, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,115 @@
+package repo
+
+import (
+	"context"
+	"crypto/ed25519"
+	"diploma/modules/contract/model"
+	"diploma/pkg/client/db"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const signingKeyTable = "signing_keys"
+
+var signingKeyColumns = []string{"id", "user_id", "public_key", "algorithm", "issued_by_server", "created_at", "revoked_at"}
+
+// CreateKey revokes the user's active key and stores the new one.
+func (r *Repository) CreateKey(ctx context.Context, key *model.SigningKey) (int64, error) {
+	query, args, _ := sq.Update(signingKeyTable).
+		Set("revoked_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"user_id": key.UserID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if _, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.revoke_keys", QueryRaw: query}, args...); err != nil {
+		return 0, err
+	}
+
+	query, args, _ = sq.Insert(signingKeyTable).
+		Columns("user_id", "public_key", "algorithm", "issued_by_server").
+		Values(key.UserID, base64.StdEncoding.EncodeToString(key.PublicKey), key.Algorithm, key.IssuedByServer).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var id int64
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.create_key", QueryRaw: query}, args...).Scan(&id)
+	return id, err
+}
+
+func (r *Repository) ActiveKey(ctx context.Context, userID int64) (*model.SigningKey, error) {
+	query, args, _ := sq.Select(signingKeyColumns...).
+		From(signingKeyTable).
+		Where(sq.Eq{"user_id": userID, "revoked_at": nil}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	key, err := scanKey(r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.active_key", QueryRaw: query}, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoSigningKey
+	}
+	return key, err
+}
+
+func (r *Repository) KeyByID(ctx context.Context, id int64) (*model.SigningKey, error) {
+	query, args, _ := sq.Select(signingKeyColumns...).
+		From(signingKeyTable).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	key, err := scanKey(r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.key_by_id", QueryRaw: query}, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	return key, err
+}
+
+func (r *Repository) KeysByUser(ctx context.Context, userID int64) ([]*model.SigningKey, error) {
+	query, args, _ := sq.Select(signingKeyColumns...).
+		From(signingKeyTable).
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("id DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.keys_by_user", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*model.SigningKey
+	for rows.Next() {
+		key, err := scanKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func scanKey(row pgx.Row) (*model.SigningKey, error) {
+	var (
+		key       model.SigningKey
+		publicKey string
+		revokedAt *time.Time
+	)
+	err := row.Scan(&key.ID, &key.UserID, &publicKey, &key.Algorithm, &key.IssuedByServer, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, model.ErrInvalidPublicKey
+	}
+	key.PublicKey = ed25519.PublicKey(raw)
+	key.RevokedAt = revokedAt
+	return &key, nil
+}
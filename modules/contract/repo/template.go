@@ -0,0 +1,218 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"diploma/modules/contract/model"
+	"diploma/pkg/client/db"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	templateTable        = "contract_templates"
+	templateVersionTable = "contract_template_versions"
+)
+
+// templateColumns select a template joined with one of its versions.
+var templateColumns = []string{
+	"t.id", "COALESCE(t.supplier_id, 0)", "t.name", "t.is_default",
+	"v.version", "v.body", "v.created_by", "v.created_at",
+}
+
+func (r *Repository) CreateTemplate(ctx context.Context, t *model.Template) (int64, error) {
+	query, args, _ := sq.Insert(templateTable).
+		Columns("supplier_id", "name").
+		Values(sql.NullInt64{Int64: t.SupplierID, Valid: t.SupplierID != 0}, t.Name).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var id int64
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.create_template", QueryRaw: query}, args...).Scan(&id)
+	return id, err
+}
+
+// LockTemplate locks the template row so versions and the default flag of
+// one template are changed one at a time.
+func (r *Repository) LockTemplate(ctx context.Context, id int64) (*model.Template, error) {
+	query, args, _ := sq.Select("id", "COALESCE(supplier_id, 0)", "name", "is_default").
+		From(templateTable).
+		Where(sq.Eq{"id": id}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	var t model.Template
+	err := r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.lock_template", QueryRaw: query}, args...).
+		Scan(&t.ID, &t.SupplierID, &t.Name, &t.IsDefault)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateTemplateVersion stores the body as the next version of the
+// template. The caller must hold the lock from LockTemplate.
+func (r *Repository) CreateTemplateVersion(ctx context.Context, templateID int64, body string, createdBy int64) (int, error) {
+	q := db.Query{
+		Name: "contract.create_template_version",
+		QueryRaw: `INSERT INTO ` + templateVersionTable + ` (template_id, version, body, created_by)
+			SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3 FROM ` + templateVersionTable + ` WHERE template_id = $1
+			RETURNING version`,
+	}
+
+	var version int
+	err := r.db.DB().QueryRowContext(ctx, q, templateID, body, createdBy).Scan(&version)
+	return version, err
+}
+
+func (r *Repository) RenameTemplate(ctx context.Context, id int64, name string) error {
+	query, args, _ := sq.Update(templateTable).
+		Set("name", name).
+		Where(sq.Eq{"id": id}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.rename_template", QueryRaw: query}, args...)
+	return err
+}
+
+// SetDefaultTemplate makes the template the default of its supplier (or
+// the global default), clearing the previous one.
+func (r *Repository) SetDefaultTemplate(ctx context.Context, t *model.Template) error {
+	scope := sq.Eq{"supplier_id": nil}
+	if t.SupplierID != 0 {
+		scope = sq.Eq{"supplier_id": t.SupplierID}
+	}
+
+	query, args, _ := sq.Update(templateTable).
+		Set("is_default", false).
+		Where(scope).
+		Where(sq.Eq{"is_default": true}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if _, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.clear_default_template", QueryRaw: query}, args...); err != nil {
+		return err
+	}
+
+	query, args, _ = sq.Update(templateTable).
+		Set("is_default", true).
+		Where(sq.Eq{"id": t.ID}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	_, err := r.db.DB().ExecContext(ctx, db.Query{Name: "contract.set_default_template", QueryRaw: query}, args...)
+	return err
+}
+
+// TemplateByID returns the template with the given version, or with its
+// latest version when version is 0.
+func (r *Repository) TemplateByID(ctx context.Context, id int64, version int) (*model.Template, error) {
+	builder := selectTemplates().Where(sq.Eq{"t.id": id})
+	if version > 0 {
+		builder = builder.Where(sq.Eq{"v.version": version})
+	} else {
+		builder = builder.Where(latestVersion)
+	}
+
+	query, args, _ := builder.ToSql()
+	t, err := scanTemplate(r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.template_by_id", QueryRaw: query}, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	return t, err
+}
+
+// Templates lists templates with their latest version. A non-zero
+// supplierID limits the list to that supplier's and the global templates.
+func (r *Repository) Templates(ctx context.Context, supplierID int64) ([]*model.Template, error) {
+	builder := selectTemplates().Where(latestVersion).OrderBy("t.id")
+	if supplierID != 0 {
+		builder = builder.Where(sq.Or{sq.Eq{"t.supplier_id": supplierID}, sq.Eq{"t.supplier_id": nil}})
+	}
+
+	query, args, _ := builder.ToSql()
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.templates", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []*model.Template
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *Repository) TemplateVersions(ctx context.Context, templateID int64) ([]model.TemplateVersion, error) {
+	query, args, _ := sq.Select("template_id", "version", "body", "created_by", "created_at").
+		From(templateVersionTable).
+		Where(sq.Eq{"template_id": templateID}).
+		OrderBy("version DESC").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	rows, err := r.db.DB().QueryContext(ctx, db.Query{Name: "contract.template_versions", QueryRaw: query}, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []model.TemplateVersion
+	for rows.Next() {
+		var v model.TemplateVersion
+		if err := rows.Scan(&v.TemplateID, &v.Version, &v.Body, &v.CreatedBy, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// DefaultTemplate returns the latest version of the supplier's default
+// template, falling back to the global default.
+func (r *Repository) DefaultTemplate(ctx context.Context, supplierID int64) (*model.Template, error) {
+	query, args, _ := selectTemplates().
+		Where(latestVersion).
+		Where(sq.Eq{"t.is_default": true}).
+		Where(sq.Or{sq.Eq{"t.supplier_id": supplierID}, sq.Eq{"t.supplier_id": nil}}).
+		OrderBy("t.supplier_id NULLS LAST").
+		Limit(1).
+		ToSql()
+
+	t, err := scanTemplate(r.db.DB().QueryRowContext(ctx, db.Query{Name: "contract.default_template", QueryRaw: query}, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	return t, err
+}
+
+var latestVersion = sq.Expr("v.version = (SELECT MAX(version) FROM " + templateVersionTable + " WHERE template_id = t.id)")
+
+func selectTemplates() sq.SelectBuilder {
+	return sq.Select(templateColumns...).
+		From(templateTable + " t").
+		Join(templateVersionTable + " v ON v.template_id = t.id").
+		PlaceholderFormat(sq.Dollar)
+}
+
+func scanTemplate(row pgx.Row) (*model.Template, error) {
+	var t model.Template
+	err := row.Scan(&t.ID, &t.SupplierID, &t.Name, &t.IsDefault, &t.Version, &t.Body, &t.CreatedBy, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
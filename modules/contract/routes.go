@@ -9,7 +9,26 @@ func RegisterRoutes(router *gin.RouterGroup, h *handler.Handler) {
 	routes := router.Group("/contract")
 	{
 		routes.POST("/sign", h.Sign)
+		routes.GET("/keys", h.GetKeys)
+		routes.POST("/keys", h.RegisterKey)
+		routes.POST("/keys/issue", h.IssueKey)
+		routes.GET("/templates", h.GetTemplates)
+		routes.POST("/templates", h.CreateTemplate)
+		routes.GET("/templates/:id", h.GetTemplate)
+		routes.PUT("/templates/:id", h.UpdateTemplate)
+		routes.GET("/templates/:id/versions", h.GetTemplateVersions)
+		routes.POST("/templates/:id/default", h.SetDefaultTemplate)
+		routes.GET("/agreements", h.GetAgreements)
+		routes.POST("/agreements", h.CreateAgreement)
+		routes.GET("/agreements/:id", h.GetAgreement)
+		routes.PUT("/agreements/:id", h.UpdateAgreement)
+		routes.POST("/agreements/:id/accept", h.AcceptAgreement)
+		routes.POST("/agreements/:id/terminate", h.TerminateAgreement)
 		routes.GET("/:id", h.Get)
+		routes.GET("/:id/verify", h.Verify)
+		routes.GET("/:id/pdf", h.GetPDF)
+		routes.POST("/:id/reject", h.Reject)
+		routes.POST("/:id/amend", h.Amend)
 		routes.GET("", h.GetList) // /api/contract
 
 	}
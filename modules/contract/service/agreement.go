@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/contract/model"
+	"errors"
+	"time"
+)
+
+type AgreementRepository interface {
+	CreateAgreement(ctx context.Context, a *model.Agreement) (int64, error)
+	UpdateAgreementTerms(ctx context.Context, a *model.Agreement) error
+	SetAgreementStatus(ctx context.Context, id int64, status string) error
+	ReplaceAgreementPrices(ctx context.Context, agreementID int64, prices []model.AgreementPrice) error
+	AgreementByID(ctx context.Context, id int64) (*model.Agreement, error)
+	LockAgreement(ctx context.Context, id int64) (*model.Agreement, error)
+	AgreementsByUser(ctx context.Context, userID int64) ([]*model.Agreement, error)
+	ActiveAgreement(ctx context.Context, supplierID, customerID int64, at time.Time) (*model.Agreement, error)
+	HasOverlappingAgreement(ctx context.Context, a *model.Agreement) (bool, error)
+	AgreementPrices(ctx context.Context, agreementID int64) ([]model.AgreementPrice, error)
+	NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64, at time.Time) (int, error)
+}
+
+// CreateAgreement stores the supplier's proposal. It takes effect once the
+// customer accepts it.
+func (s *Service) CreateAgreement(ctx context.Context, a *model.Agreement) (int64, error) {
+	if err := a.Validate(); err != nil {
+		return 0, err
+	}
+	a.Status = model.AgreementPending
+
+	var id int64
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var errTx error
+		id, errTx = s.repo.CreateAgreement(ctx, a)
+		if errTx != nil {
+			return errTx
+		}
+		return s.repo.ReplaceAgreementPrices(ctx, id, a.Prices)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateAgreement replaces the period, credit terms and prices of a
+// pending agreement. Only its supplier can change it.
+func (s *Service) UpdateAgreement(ctx context.Context, a *model.Agreement) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.repo.LockAgreement(ctx, a.ID)
+		if err != nil {
+			return err
+		}
+		if current.SupplierID != a.SupplierID {
+			return model.ErrForbidden
+		}
+		if current.Status != model.AgreementPending {
+			return model.ErrAgreementNotPending
+		}
+
+		a.CustomerID = current.CustomerID
+		if err := a.Validate(); err != nil {
+			return err
+		}
+		if err := s.repo.UpdateAgreementTerms(ctx, a); err != nil {
+			return err
+		}
+		return s.repo.ReplaceAgreementPrices(ctx, a.ID, a.Prices)
+	})
+}
+
+// AcceptAgreement activates a pending agreement on behalf of its customer.
+// The parties can only have one active agreement for any day.
+func (s *Service) AcceptAgreement(ctx context.Context, id, customerID int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		a, err := s.repo.LockAgreement(ctx, id)
+		if err != nil {
+			return err
+		}
+		if a.CustomerID != customerID {
+			return model.ErrForbidden
+		}
+		if a.Status != model.AgreementPending {
+			return model.ErrAgreementNotPending
+		}
+
+		overlap, err := s.repo.HasOverlappingAgreement(ctx, a)
+		if err != nil {
+			return err
+		}
+		if overlap {
+			return model.ErrAgreementOverlap
+		}
+		return s.repo.SetAgreementStatus(ctx, id, model.AgreementActive)
+	})
+}
+
+// TerminateAgreement ends a pending or active agreement on behalf of
+// either party. Orders placed from then on use list prices again.
+func (s *Service) TerminateAgreement(ctx context.Context, id, userID int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		a, err := s.repo.LockAgreement(ctx, id)
+		if err != nil {
+			return err
+		}
+		if _, ok := a.Party(userID); !ok {
+			return model.ErrForbidden
+		}
+		if a.Status == model.AgreementTerminated {
+			return nil
+		}
+		return s.repo.SetAgreementStatus(ctx, id, model.AgreementTerminated)
+	})
+}
+
+// Agreement returns the agreement with its prices to one of its parties or
+// an admin.
+func (s *Service) Agreement(ctx context.Context, id, userID int64, role int) (*model.Agreement, error) {
+	a, err := s.repo.AgreementByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := a.Party(userID); !ok && role != model.AdminRole {
+		return nil, model.ErrForbidden
+	}
+
+	a.Prices, err = s.repo.AgreementPrices(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (s *Service) Agreements(ctx context.Context, userID int64) ([]*model.Agreement, error) {
+	return s.repo.AgreementsByUser(ctx, userID)
+}
+
+// NegotiatedPrice returns the customer's price for the supplier's product
+// under their active agreement. ok is false when there is no agreement or
+// the agreement does not cover the product.
+func (s *Service) NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64) (price int, ok bool, err error) {
+	price, err = s.repo.NegotiatedPrice(ctx, supplierID, customerID, productID, time.Now())
+	if errors.Is(err, model.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return price, true, nil
+}
@@ -2,74 +2,212 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"diploma/modules/contract/model"
-	orderModel "diploma/modules/order/model"
-	"fmt"
+	"diploma/pkg/client/db"
 	"time"
 )
 
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
 type Repository interface {
 	Create(ctx context.Context, contract *model.Contract) (int64, error)
-	SignByParty(ctx context.Context, contractID int64, role int, signature string) error
+	SignByParty(ctx context.Context, contractID int64, role int, signature string, keyID int64) error
+	SetContentHash(ctx context.Context, contractID int64, hash string) error
+	SetStatus(ctx context.Context, contractID int64, status int) error
+	Reject(ctx context.Context, contractID, userID int64, reason string) error
+	LockOpenByOrder(ctx context.Context, orderID int64) ([]*model.Contract, error)
+	ExpiredIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error)
 	GetByID(ctx context.Context, id int64) (*model.Contract, error)
+	LockByID(ctx context.Context, id int64) (*model.Contract, error)
 	MarkAsSigned(ctx context.Context, contractID int64) error
-	GetByUser(ctx context.Context, userID int64) ([]*model.Contract, error) // 🔹 новый метод
+	GetByUser(ctx context.Context, userID int64, filter model.ContractFilter) ([]*model.Contract, error)
+	StatusCounts(ctx context.Context, userID int64, filter model.ContractFilter) (map[int]int, int, error)
+	KeyRepository
+	TemplateRepository
+	DocumentRepository
+	AgreementRepository
 }
 
 type Service struct {
-	repo Repository
+	repo        Repository
+	renderer    IDocumentRenderer
+	userClient  IUserClient
+	orderEvents IOrderEvents
+	// ttl is how long a new contract may stay unsigned.
+	ttl       time.Duration
+	txManager db.TxManager
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo}
+func NewService(
+	repo Repository,
+	renderer IDocumentRenderer,
+	userClient IUserClient,
+	orderEvents IOrderEvents,
+	ttl time.Duration,
+	txManager db.TxManager,
+) *Service {
+	return &Service{
+		repo:        repo,
+		renderer:    renderer,
+		userClient:  userClient,
+		orderEvents: orderEvents,
+		ttl:         ttl,
+		txManager:   txManager,
+	}
 }
 
 func (s *Service) CreateContract(ctx context.Context, orderID, supplierID, customerID int64, content string) (int64, error) {
-	contract := &model.Contract{
+	return s.create(ctx, &model.Contract{
 		OrderID:    orderID,
 		SupplierID: supplierID,
 		CustomerID: customerID,
 		Content:    content,
-		Status:     model.StatusCreated,
-		CreatedAt:  time.Now(),
+	})
+}
+
+func (s *Service) create(ctx context.Context, contract *model.Contract) (int64, error) {
+	contract.Status = model.StatusCreated
+	contract.CreatedAt = time.Now()
+	contract.ExpiresAt = sql.NullTime{Time: contract.CreatedAt.Add(s.ttl), Valid: true}
+	if contract.Version == 0 {
+		contract.Version = 1
+	}
+
+	id, err := s.repo.Create(ctx, contract)
+	if err != nil {
+		return 0, err
+	}
+
+	// the hash covers the contract ID, so it can only be recorded after insert
+	contract.ID = id
+	if err := s.repo.SetContentHash(ctx, id, contract.CanonicalHashHex()); err != nil {
+		return 0, err
 	}
-	return s.repo.Create(ctx, contract)
+	return id, nil
 }
 
-func (s *Service) SignContract(ctx context.Context, contractID int64, role int, signature string) error {
-	contract, err := s.repo.GetByID(ctx, contractID)
+// SignContract records the caller's signature. Only the contract's
+// supplier and customer can sign, each of them once and in any order; the
+// status follows each step. The signature must be a base64 ed25519
+// signature of the contract's canonical hash made with the caller's active
+// signing key. The second signature completes the contract and archives
+// its PDF.
+func (s *Service) SignContract(ctx context.Context, contractID, userID int64, signature string) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		return s.sign(ctx, contractID, userID, signature)
+	})
+}
+
+func (s *Service) sign(ctx context.Context, contractID, userID int64, signature string) error {
+	// the lock keeps two parties signing at once from both missing the
+	// other's signature and leaving the contract uncompleted
+	contract, err := s.repo.LockByID(ctx, contractID)
 	if err != nil {
 		return err
 	}
 
-	switch role {
-	case orderModel.CustomerRole:
-		if !contract.SupplierSig.Valid {
-			return fmt.Errorf("supplier has not signed the contract yet")
+	role, ok := contract.Party(userID)
+	if !ok {
+		return model.ErrForbidden
+	}
+	if contract.SignedBy(role) {
+		return model.ErrAlreadySigned
+	}
+	if !contract.Open(time.Now()) {
+		return model.ErrContractClosed
+	}
+
+	hash := contract.CanonicalHashHex()
+	if contract.ContentHash == "" {
+		// contracts created before hashes were recorded
+		if err := s.repo.SetContentHash(ctx, contractID, hash); err != nil {
+			return err
 		}
+	} else if contract.ContentHash != hash {
+		return model.ErrContentTampered
+	}
 
-	case orderModel.SupplierRole:
-	default:
-		return fmt.Errorf("unknown role")
+	key, err := s.repo.ActiveKey(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !verifySignature(key, contract.CanonicalHash(), signature) {
+		return model.ErrInvalidSignature
 	}
 
-	err = s.repo.SignByParty(ctx, contractID, role, signature)
+	err = s.repo.SignByParty(ctx, contractID, role, signature, key.ID)
 	if err != nil {
 		return err
 	}
 
-	if role == orderModel.CustomerRole && contract.SupplierSig.Valid ||
-		role == orderModel.SupplierRole && contract.CustomerSig.Valid {
-		return s.repo.MarkAsSigned(ctx, contractID)
+	other := model.CustomerRole
+	if role == model.CustomerRole {
+		other = model.SupplierRole
+	}
+	if contract.SignedBy(other) {
+		if err := s.repo.MarkAsSigned(ctx, contractID); err != nil {
+			return err
+		}
+		if _, err := s.archive(ctx, contractID); err != nil {
+			return err
+		}
+		return s.orderEvents.ContractCompleted(ctx, contract.OrderID)
 	}
 
 	return nil
 }
 
-func (s *Service) GetContract(ctx context.Context, id int64) (*model.Contract, error) {
-	return s.repo.GetByID(ctx, id)
+// GetContract returns the contract to one of its parties or an admin.
+func (s *Service) GetContract(ctx context.Context, id, userID int64, role int) (*model.Contract, error) {
+	contract, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !contract.CanRead(userID, role) {
+		return nil, model.ErrForbidden
+	}
+	return contract, nil
 }
 
-func (s *Service) GetContractsByUser(ctx context.Context, userID int64) ([]*model.Contract, error) {
-	return s.repo.GetByUser(ctx, userID)
+// GetContractsByUser returns one page of the user's contracts matching the
+// filter together with the per-status counts.
+func (s *Service) GetContractsByUser(ctx context.Context, userID int64, filter model.ContractFilter) (*model.ContractList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	if filter.Limit > maxListLimit {
+		filter.Limit = maxListLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	contracts, err := s.repo.GetByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, awaiting, err := s.repo.StatusCounts(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &model.ContractList{
+		Contracts:         contracts,
+		StatusCounts:      counts,
+		AwaitingSignature: awaiting,
+	}
+	if len(filter.Statuses) == 0 {
+		for _, n := range counts {
+			list.Total += n
+		}
+	} else {
+		for _, status := range filter.Statuses {
+			list.Total += counts[status]
+		}
+	}
+	return list, nil
 }
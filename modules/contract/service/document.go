@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"diploma/modules/contract/model"
+	"encoding/hex"
+	"errors"
+)
+
+type DocumentRepository interface {
+	SaveArchive(ctx context.Context, archive *model.Archive) error
+	Archive(ctx context.Context, contractID int64) (*model.Archive, error)
+}
+
+type IDocumentRenderer interface {
+	Render(document *model.Document) ([]byte, error)
+}
+
+type IUserClient interface {
+	Party(ctx context.Context, userID int64) (*model.Party, error)
+}
+
+// ContractPDF returns the PDF of the contract to one of its parties or an
+// admin. A completed contract is served from the archive together with its
+// hash; any other contract is rendered on the fly and the returned hash is
+// empty.
+func (s *Service) ContractPDF(ctx context.Context, contractID, userID int64, role int) ([]byte, string, error) {
+	contract, err := s.GetContract(ctx, contractID, userID, role)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if contract.Status != model.StatusCompleted {
+		body, err := s.render(ctx, contract)
+		if err != nil {
+			return nil, "", err
+		}
+		return body, "", nil
+	}
+
+	archive, err := s.repo.Archive(ctx, contractID)
+	if errors.Is(err, model.ErrNoRows) {
+		// completed before documents were archived
+		archive, err = s.archive(ctx, contractID)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return archive.PDF, archive.SHA256, nil
+}
+
+// archive renders the completed contract and stores the PDF with its
+// SHA-256, so the document can later be shown to be unchanged.
+func (s *Service) archive(ctx context.Context, contractID int64) (*model.Archive, error) {
+	contract, err := s.repo.GetByID(ctx, contractID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.render(ctx, contract)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(body)
+	archive := &model.Archive{
+		ContractID: contractID,
+		PDF:        body,
+		SHA256:     hex.EncodeToString(sum[:]),
+	}
+	if err := s.repo.SaveArchive(ctx, archive); err != nil {
+		return nil, err
+	}
+
+	// a concurrent call may have archived first; its document wins
+	return s.repo.Archive(ctx, contractID)
+}
+
+func (s *Service) render(ctx context.Context, contract *model.Contract) ([]byte, error) {
+	supplier, err := s.userClient.Party(ctx, contract.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.userClient.Party(ctx, contract.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.renderer.Render(&model.Document{
+		Contract: contract,
+		Supplier: supplier,
+		Customer: customer,
+	})
+}
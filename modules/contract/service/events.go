@@ -0,0 +1,17 @@
+package service
+
+import "context"
+
+// IOrderEvents tells the order module about contracts reaching a final
+// state. It is called inside the transaction that changed the contract, so
+// an error rolls the contract change back and both sides stay consistent.
+type IOrderEvents interface {
+	// ContractCompleted is called once both parties have signed.
+	ContractCompleted(ctx context.Context, orderID int64) error
+	// ContractClosed is called when a contract is rejected.
+	ContractClosed(ctx context.Context, orderID int64) error
+	// ContractExpired is called when a contract expires unsigned. It must
+	// not fail for an order that is already completed: the expiry job would
+	// pick the contract up again on every run.
+	ContractExpired(ctx context.Context, orderID int64) error
+}
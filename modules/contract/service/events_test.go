@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"time"
+
+	"diploma/modules/contract/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testContractID = int64(1)
+	testSupplierID = int64(10)
+	testCustomerID = int64(20)
+	testOrderID    = int64(30)
+)
+
+func newTestContract() *model.Contract {
+	contract := &model.Contract{
+		ID:         testContractID,
+		OrderID:    testOrderID,
+		SupplierID: testSupplierID,
+		CustomerID: testCustomerID,
+		Content:    "Договор поставки",
+		CreatedAt:  time.Now(),
+		Version:    1,
+	}
+	contract.ContentHash = contract.CanonicalHashHex()
+	return contract
+}
+
+// signature signs the contract with a new key of the user and expects the
+// key to be looked up.
+func (s *ContractServiceTestSuite) signature(contract *model.Contract, userID int64) string {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	s.helper.RequireNoError(err)
+	s.repo.On("ActiveKey", mock.Anything, userID).
+		Return(&model.SigningKey{ID: userID, UserID: userID, PublicKey: public}, nil).Once()
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(private, contract.CanonicalHash()))
+}
+
+func (s *ContractServiceTestSuite) TestSignContract_FirstSignature() {
+	contract := newTestContract()
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(contract, nil).Once()
+	signature := s.signature(contract, testCustomerID)
+	s.repo.On("SignByParty", mock.Anything, testContractID, model.CustomerRole, signature, testCustomerID).Return(nil).Once()
+
+	err := s.service.SignContract(context.Background(), testContractID, testCustomerID, signature)
+
+	s.helper.AssertNoError(err)
+	s.repo.AssertNotCalled(s.T(), "MarkAsSigned", mock.Anything, mock.Anything)
+	s.orderEvents.AssertNotCalled(s.T(), "ContractCompleted", mock.Anything, mock.Anything)
+}
+
+func (s *ContractServiceTestSuite) TestSignContract_CompletesOrderOnSecondSignature() {
+	contract := newTestContract()
+	contract.CustomerSig = sql.NullString{String: "c2ln", Valid: true}
+	contract.Status = model.StatusSignedByCustomer
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(contract, nil).Once()
+	signature := s.signature(contract, testSupplierID)
+	s.repo.On("SignByParty", mock.Anything, testContractID, model.SupplierRole, signature, testSupplierID).Return(nil).Once()
+	s.repo.On("MarkAsSigned", mock.Anything, testContractID).Return(nil).Once()
+
+	s.repo.On("GetByID", mock.Anything, testContractID).Return(contract, nil).Once()
+	s.userClient.On("Party", mock.Anything, mock.Anything).Return(&model.Party{}, nil)
+	s.renderer.On("Render", mock.Anything).Return([]byte("%PDF"), nil).Once()
+	s.repo.On("SaveArchive", mock.Anything, mock.Anything).Return(nil).Once()
+	s.repo.On("Archive", mock.Anything, testContractID).Return(&model.Archive{ContractID: testContractID}, nil).Once()
+	s.orderEvents.On("ContractCompleted", mock.Anything, testOrderID).Return(nil).Once()
+
+	err := s.service.SignContract(context.Background(), testContractID, testSupplierID, signature)
+
+	s.helper.AssertNoError(err)
+	s.orderEvents.AssertNotCalled(s.T(), "ContractClosed", mock.Anything, mock.Anything)
+}
+
+func (s *ContractServiceTestSuite) TestSignContract_OncePerParty() {
+	contract := newTestContract()
+	contract.SupplierSig = sql.NullString{String: "c2ln", Valid: true}
+	contract.Status = model.StatusSignedBySupplier
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(contract, nil).Once()
+
+	err := s.service.SignContract(context.Background(), testContractID, testSupplierID, "c2ln")
+
+	s.ErrorIs(err, model.ErrAlreadySigned)
+	s.repo.AssertNotCalled(s.T(), "SignByParty", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ContractServiceTestSuite) TestSignContract_OutsiderForbidden() {
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(newTestContract(), nil).Once()
+
+	err := s.service.SignContract(context.Background(), testContractID, 99, "c2ln")
+
+	s.ErrorIs(err, model.ErrForbidden)
+}
+
+func (s *ContractServiceTestSuite) TestSignContract_RejectedContract() {
+	contract := newTestContract()
+	contract.Status = model.StatusRejected
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(contract, nil).Once()
+
+	err := s.service.SignContract(context.Background(), testContractID, testSupplierID, "c2ln")
+
+	s.ErrorIs(err, model.ErrContractClosed)
+}
+
+func (s *ContractServiceTestSuite) TestRejectContract_ClosesOrder() {
+	reason := "цена не согласована"
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(newTestContract(), nil).Once()
+	s.repo.On("Reject", mock.Anything, testContractID, testCustomerID, reason).Return(nil).Once()
+	s.orderEvents.On("ContractClosed", mock.Anything, testOrderID).Return(nil).Once()
+
+	err := s.service.RejectContract(context.Background(), testContractID, testCustomerID, reason)
+
+	s.helper.AssertNoError(err)
+}
+
+func (s *ContractServiceTestSuite) TestCloseOrderContracts_DoesNotNotifyOrder() {
+	reason := "order cancelled by supplier"
+	s.repo.On("LockOpenByOrder", mock.Anything, testOrderID).Return([]*model.Contract{newTestContract()}, nil).Once()
+	s.repo.On("Reject", mock.Anything, testContractID, int64(0), reason).Return(nil).Once()
+
+	err := s.service.CloseOrderContracts(context.Background(), testOrderID, reason)
+
+	s.helper.AssertNoError(err)
+	s.orderEvents.AssertNotCalled(s.T(), "ContractClosed", mock.Anything, mock.Anything)
+}
+
+func (s *ContractServiceTestSuite) TestExpire_NotifiesOrderOfExpiry() {
+	now := time.Now()
+	contract := newTestContract()
+	contract.ExpiresAt = sql.NullTime{Time: now.Add(-time.Minute), Valid: true}
+	s.repo.On("LockByID", mock.Anything, testContractID).Return(contract, nil).Once()
+	s.repo.On("SetStatus", mock.Anything, testContractID, model.StatusExpired).Return(nil).Once()
+	s.orderEvents.On("ContractExpired", mock.Anything, testOrderID).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.expire(context.Background(), testContractID, now))
+	s.orderEvents.AssertNotCalled(s.T(), "ContractClosed", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"diploma/modules/contract/model"
+	"diploma/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// expiryBatchSize bounds the number of contracts expired per tick.
+const expiryBatchSize = 100
+
+// RejectContract closes an open contract on behalf of one of its parties
+// and cancels the order it was made for.
+func (s *Service) RejectContract(ctx context.Context, contractID, userID int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return model.ErrReasonRequired
+	}
+
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		contract, err := s.repo.LockByID(ctx, contractID)
+		if err != nil {
+			return err
+		}
+		if _, ok := contract.Party(userID); !ok {
+			return model.ErrForbidden
+		}
+		if !contract.Open(time.Now()) {
+			return model.ErrContractClosed
+		}
+
+		if err := s.repo.Reject(ctx, contractID, userID, reason); err != nil {
+			return err
+		}
+		return s.orderEvents.ContractClosed(ctx, contract.OrderID)
+	})
+}
+
+// AmendContract replaces an open contract with a new version carrying the
+// supplier's content. The new contract links to the replaced one and
+// starts unsigned, so signatures made on earlier versions no longer count.
+func (s *Service) AmendContract(ctx context.Context, contractID, supplierID int64, content string) (int64, error) {
+	if strings.TrimSpace(content) == "" {
+		return 0, model.ErrEmptyContent
+	}
+
+	var id int64
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		previous, err := s.repo.LockByID(ctx, contractID)
+		if err != nil {
+			return err
+		}
+		if role, ok := previous.Party(supplierID); !ok || role != model.SupplierRole {
+			return model.ErrForbidden
+		}
+		if !previous.Open(time.Now()) {
+			return model.ErrContractClosed
+		}
+
+		if err := s.repo.SetStatus(ctx, contractID, model.StatusAmended); err != nil {
+			return err
+		}
+		id, err = s.create(ctx, &model.Contract{
+			OrderID:     previous.OrderID,
+			SupplierID:  previous.SupplierID,
+			CustomerID:  previous.CustomerID,
+			Content:     content,
+			PreviousID:  sql.NullInt64{Int64: previous.ID, Valid: true},
+			Version:     previous.Version + 1,
+			AgreementID: previous.AgreementID,
+		})
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// StartExpiry expires overdue contracts every interval until ctx is
+// cancelled.
+func (s *Service) StartExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.ExpireDue(ctx, time.Now()); err != nil {
+			logger.Error("Failed to expire contracts", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ExpireDue expires every contract left unsigned past its expiry and
+// cancels the orders they were made for.
+func (s *Service) ExpireDue(ctx context.Context, now time.Time) error {
+	ids, err := s.repo.ExpiredIDs(ctx, now, expiryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.expire(ctx, id, now); err != nil {
+			logger.Error("Failed to expire contract", zap.Int64("contract_id", id), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (s *Service) expire(ctx context.Context, contractID int64, now time.Time) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		contract, err := s.repo.LockByID(ctx, contractID)
+		if err != nil {
+			return err
+		}
+		if contract.Open(now) || contract.Status >= model.StatusCompleted {
+			// signed or amended since it was selected
+			return nil
+		}
+
+		if err := s.repo.SetStatus(ctx, contractID, model.StatusExpired); err != nil {
+			return err
+		}
+		return s.orderEvents.ContractExpired(ctx, contract.OrderID)
+	})
+}
+
+// CloseOrderContracts rejects the open contracts of an order that was
+// cancelled on the order side. The order is not notified back.
+func (s *Service) CloseOrderContracts(ctx context.Context, orderID int64, reason string) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		contracts, err := s.repo.LockOpenByOrder(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, contract := range contracts {
+			if err := s.repo.Reject(ctx, contract.ID, 0, reason); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"diploma/internal/testutils"
+	"diploma/modules/contract/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockRepository struct {
+	mock.Mock
+}
+
+func (m *mockRepository) ActiveAgreement(ctx context.Context, supplierID, customerID int64, at time.Time) (*model.Agreement, error) {
+	args := m.Called(ctx, supplierID, customerID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Agreement), args.Error(1)
+}
+
+func (m *mockRepository) ActiveKey(ctx context.Context, userID int64) (*model.SigningKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SigningKey), args.Error(1)
+}
+
+func (m *mockRepository) AgreementByID(ctx context.Context, id int64) (*model.Agreement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Agreement), args.Error(1)
+}
+
+func (m *mockRepository) AgreementPrices(ctx context.Context, agreementID int64) ([]model.AgreementPrice, error) {
+	args := m.Called(ctx, agreementID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.AgreementPrice), args.Error(1)
+}
+
+func (m *mockRepository) AgreementsByUser(ctx context.Context, userID int64) ([]*model.Agreement, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Agreement), args.Error(1)
+}
+
+func (m *mockRepository) Archive(ctx context.Context, contractID int64) (*model.Archive, error) {
+	args := m.Called(ctx, contractID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Archive), args.Error(1)
+}
+
+func (m *mockRepository) Create(ctx context.Context, contract *model.Contract) (int64, error) {
+	args := m.Called(ctx, contract)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepository) CreateAgreement(ctx context.Context, a *model.Agreement) (int64, error) {
+	args := m.Called(ctx, a)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepository) CreateKey(ctx context.Context, key *model.SigningKey) (int64, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepository) CreateTemplate(ctx context.Context, t *model.Template) (int64, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepository) CreateTemplateVersion(ctx context.Context, templateID int64, body string, createdBy int64) (int, error) {
+	args := m.Called(ctx, templateID, body, createdBy)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockRepository) DefaultTemplate(ctx context.Context, supplierID int64) (*model.Template, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Template), args.Error(1)
+}
+
+func (m *mockRepository) ExpiredIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *mockRepository) GetByID(ctx context.Context, id int64) (*model.Contract, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Contract), args.Error(1)
+}
+
+func (m *mockRepository) GetByUser(ctx context.Context, userID int64, filter model.ContractFilter) ([]*model.Contract, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Contract), args.Error(1)
+}
+
+func (m *mockRepository) HasOverlappingAgreement(ctx context.Context, a *model.Agreement) (bool, error) {
+	args := m.Called(ctx, a)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockRepository) KeyByID(ctx context.Context, id int64) (*model.SigningKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.SigningKey), args.Error(1)
+}
+
+func (m *mockRepository) KeysByUser(ctx context.Context, userID int64) ([]*model.SigningKey, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.SigningKey), args.Error(1)
+}
+
+func (m *mockRepository) LockAgreement(ctx context.Context, id int64) (*model.Agreement, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Agreement), args.Error(1)
+}
+
+func (m *mockRepository) LockByID(ctx context.Context, id int64) (*model.Contract, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Contract), args.Error(1)
+}
+
+func (m *mockRepository) LockOpenByOrder(ctx context.Context, orderID int64) ([]*model.Contract, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Contract), args.Error(1)
+}
+
+func (m *mockRepository) LockTemplate(ctx context.Context, id int64) (*model.Template, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Template), args.Error(1)
+}
+
+func (m *mockRepository) MarkAsSigned(ctx context.Context, contractID int64) error {
+	args := m.Called(ctx, contractID)
+	return args.Error(0)
+}
+
+func (m *mockRepository) NegotiatedPrice(ctx context.Context, supplierID, customerID, productID int64, at time.Time) (int, error) {
+	args := m.Called(ctx, supplierID, customerID, productID, at)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockRepository) Reject(ctx context.Context, contractID, userID int64, reason string) error {
+	args := m.Called(ctx, contractID, userID, reason)
+	return args.Error(0)
+}
+
+func (m *mockRepository) RenameTemplate(ctx context.Context, id int64, name string) error {
+	args := m.Called(ctx, id, name)
+	return args.Error(0)
+}
+
+func (m *mockRepository) ReplaceAgreementPrices(ctx context.Context, agreementID int64, prices []model.AgreementPrice) error {
+	args := m.Called(ctx, agreementID, prices)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SaveArchive(ctx context.Context, archive *model.Archive) error {
+	args := m.Called(ctx, archive)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SetAgreementStatus(ctx context.Context, id int64, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SetContentHash(ctx context.Context, contractID int64, hash string) error {
+	args := m.Called(ctx, contractID, hash)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SetDefaultTemplate(ctx context.Context, t *model.Template) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SetStatus(ctx context.Context, contractID int64, status int) error {
+	args := m.Called(ctx, contractID, status)
+	return args.Error(0)
+}
+
+func (m *mockRepository) SignByParty(ctx context.Context, contractID int64, role int, signature string, keyID int64) error {
+	args := m.Called(ctx, contractID, role, signature, keyID)
+	return args.Error(0)
+}
+
+func (m *mockRepository) StatusCounts(ctx context.Context, userID int64, filter model.ContractFilter) (map[int]int, int, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).(map[int]int), args.Int(1), args.Error(2)
+}
+
+func (m *mockRepository) TemplateByID(ctx context.Context, id int64, version int) (*model.Template, error) {
+	args := m.Called(ctx, id, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Template), args.Error(1)
+}
+
+func (m *mockRepository) TemplateVersions(ctx context.Context, templateID int64) ([]model.TemplateVersion, error) {
+	args := m.Called(ctx, templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.TemplateVersion), args.Error(1)
+}
+
+func (m *mockRepository) Templates(ctx context.Context, supplierID int64) ([]*model.Template, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Template), args.Error(1)
+}
+
+func (m *mockRepository) UpdateAgreementTerms(ctx context.Context, a *model.Agreement) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+type mockOrderEvents struct {
+	mock.Mock
+}
+
+func (m *mockOrderEvents) ContractClosed(ctx context.Context, orderID int64) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *mockOrderEvents) ContractCompleted(ctx context.Context, orderID int64) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *mockOrderEvents) ContractExpired(ctx context.Context, orderID int64) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+type mockRenderer struct {
+	mock.Mock
+}
+
+func (m *mockRenderer) Render(document *model.Document) ([]byte, error) {
+	args := m.Called(document)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type mockUserClient struct {
+	mock.Mock
+}
+
+func (m *mockUserClient) Party(ctx context.Context, userID int64) (*model.Party, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Party), args.Error(1)
+}
+
+type ContractServiceTestSuite struct {
+	suite.Suite
+	service     *Service
+	repo        *mockRepository
+	orderEvents *mockOrderEvents
+	renderer    *mockRenderer
+	userClient  *mockUserClient
+	txManager   *testutils.MockTxManager
+	helper      *testutils.AssertTestHelper
+}
+
+func TestContractService(t *testing.T) {
+	suite.Run(t, new(ContractServiceTestSuite))
+}
+
+func (s *ContractServiceTestSuite) SetupTest() {
+	s.repo = new(mockRepository)
+	s.orderEvents = new(mockOrderEvents)
+	s.renderer = new(mockRenderer)
+	s.userClient = new(mockUserClient)
+	s.txManager = new(testutils.MockTxManager)
+	s.helper = testutils.NewAssertTestHelper(s.T())
+
+	s.txManager.On("ReadCommitted", mock.Anything, mock.Anything).Return(nil)
+
+	s.service = NewService(s.repo, s.renderer, s.userClient, s.orderEvents, time.Hour, s.txManager)
+}
+
+func (s *ContractServiceTestSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.orderEvents.AssertExpectations(s.T())
+}
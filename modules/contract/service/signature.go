@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"diploma/modules/contract/model"
+	"encoding/base64"
+	"time"
+)
+
+type KeyRepository interface {
+	CreateKey(ctx context.Context, key *model.SigningKey) (int64, error)
+	ActiveKey(ctx context.Context, userID int64) (*model.SigningKey, error)
+	KeyByID(ctx context.Context, id int64) (*model.SigningKey, error)
+	KeysByUser(ctx context.Context, userID int64) ([]*model.SigningKey, error)
+}
+
+// RegisterKey makes the base64 ed25519 public key the user's active
+// signing key. The previous key is revoked but still verifies the
+// signatures made with it.
+func (s *Service) RegisterKey(ctx context.Context, userID int64, publicKey string) (*model.SigningKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, model.ErrInvalidPublicKey
+	}
+
+	key := &model.SigningKey{
+		UserID:    userID,
+		PublicKey: ed25519.PublicKey(raw),
+		Algorithm: model.KeyAlgorithmEd25519,
+		CreatedAt: time.Now(),
+	}
+	key.ID, err = s.repo.CreateKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// IssueKey generates a key pair for users who cannot create one
+// themselves. Only the public key is stored; the private key is handed out
+// once.
+func (s *Service) IssueKey(ctx context.Context, userID int64) (*model.IssuedKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.SigningKey{
+		UserID:         userID,
+		PublicKey:      publicKey,
+		Algorithm:      model.KeyAlgorithmEd25519,
+		IssuedByServer: true,
+		CreatedAt:      time.Now(),
+	}
+	key.ID, err = s.repo.CreateKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &model.IssuedKey{Key: key, PrivateKey: privateKey}, nil
+}
+
+func (s *Service) Keys(ctx context.Context, userID int64) ([]*model.SigningKey, error) {
+	return s.repo.KeysByUser(ctx, userID)
+}
+
+// VerifyContract re-checks the content hash and both signatures against
+// the current contract content.
+func (s *Service) VerifyContract(ctx context.Context, contractID, userID int64, role int) (*model.Verification, error) {
+	contract, err := s.GetContract(ctx, contractID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := contract.CanonicalHash()
+	v := &model.Verification{
+		ContractID:   contract.ID,
+		ContentHash:  contract.CanonicalHashHex(),
+		RecordedHash: contract.ContentHash,
+	}
+	switch {
+	case v.RecordedHash == "":
+		// created before hashes were recorded: nothing to compare with
+		v.ContentReason = "no recorded hash"
+	case v.RecordedHash != v.ContentHash:
+		v.ContentReason = "content does not match the recorded hash"
+	default:
+		v.ContentIntact = true
+	}
+
+	v.Supplier, err = s.verifyParty(ctx, contract.SupplierID, contract.SupplierSig, contract.SupplierKeyID, hash)
+	if err != nil {
+		return nil, err
+	}
+	v.Customer, err = s.verifyParty(ctx, contract.CustomerID, contract.CustomerSig, contract.CustomerKeyID, hash)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// verifyParty checks one party's signature against the current content
+// hash, so a signature made before the content was altered fails.
+func (s *Service) verifyParty(ctx context.Context, partyID int64, signature sql.NullString, keyID sql.NullInt64, hash []byte) (model.PartyVerification, error) {
+	res := model.PartyVerification{Signed: signature.Valid}
+	if !signature.Valid {
+		return res, nil
+	}
+	if !keyID.Valid {
+		res.Reason = "signature was not made with a registered key"
+		return res, nil
+	}
+	res.KeyID = keyID.Int64
+
+	key, err := s.repo.KeyByID(ctx, keyID.Int64)
+	if err != nil {
+		return res, err
+	}
+	if key.UserID != partyID {
+		res.Reason = "signing key does not belong to the contract party"
+		return res, nil
+	}
+	if !verifySignature(key, hash, signature.String) {
+		res.Reason = model.ErrInvalidSignature.Error()
+		return res, nil
+	}
+
+	res.Valid = true
+	return res, nil
+}
+
+func verifySignature(key *model.SigningKey, hash []byte, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(key.PublicKey, hash, sig)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"diploma/modules/contract/model"
+	"errors"
+	"time"
+)
+
+type TemplateRepository interface {
+	CreateTemplate(ctx context.Context, t *model.Template) (int64, error)
+	LockTemplate(ctx context.Context, id int64) (*model.Template, error)
+	CreateTemplateVersion(ctx context.Context, templateID int64, body string, createdBy int64) (int, error)
+	RenameTemplate(ctx context.Context, id int64, name string) error
+	SetDefaultTemplate(ctx context.Context, t *model.Template) error
+	TemplateByID(ctx context.Context, id int64, version int) (*model.Template, error)
+	Templates(ctx context.Context, supplierID int64) ([]*model.Template, error)
+	TemplateVersions(ctx context.Context, templateID int64) ([]model.TemplateVersion, error)
+	DefaultTemplate(ctx context.Context, supplierID int64) (*model.Template, error)
+}
+
+// CreateTemplate stores a new template as version 1.
+func (s *Service) CreateTemplate(ctx context.Context, t *model.Template) (int64, error) {
+	if t.Name == "" {
+		return 0, model.ErrInvalidTemplate
+	}
+	if err := model.ValidateBody(t.Body); err != nil {
+		return 0, err
+	}
+
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var errTx error
+		t.ID, errTx = s.repo.CreateTemplate(ctx, t)
+		if errTx != nil {
+			return errTx
+		}
+		t.Version, errTx = s.repo.CreateTemplateVersion(ctx, t.ID, t.Body, t.CreatedBy)
+		if errTx != nil {
+			return errTx
+		}
+		if t.IsDefault {
+			return s.repo.SetDefaultTemplate(ctx, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return t.ID, nil
+}
+
+// UpdateTemplate saves the body as a new version. Contracts created earlier
+// keep referring to the version they were rendered from.
+func (s *Service) UpdateTemplate(ctx context.Context, id int64, name, body string, adminID int64) (int, error) {
+	if err := model.ValidateBody(body); err != nil {
+		return 0, err
+	}
+
+	var version int
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		t, errTx := s.repo.LockTemplate(ctx, id)
+		if errTx != nil {
+			return errTx
+		}
+		if name != "" && name != t.Name {
+			if errTx = s.repo.RenameTemplate(ctx, id, name); errTx != nil {
+				return errTx
+			}
+		}
+		version, errTx = s.repo.CreateTemplateVersion(ctx, id, body, adminID)
+		return errTx
+	})
+	return version, err
+}
+
+// SetDefaultTemplate makes the template the default of its supplier, or
+// the global default for a global template.
+func (s *Service) SetDefaultTemplate(ctx context.Context, id int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		t, err := s.repo.LockTemplate(ctx, id)
+		if err != nil {
+			return err
+		}
+		return s.repo.SetDefaultTemplate(ctx, t)
+	})
+}
+
+func (s *Service) Template(ctx context.Context, id int64, version int) (*model.Template, error) {
+	return s.repo.TemplateByID(ctx, id, version)
+}
+
+func (s *Service) Templates(ctx context.Context, supplierID int64) ([]*model.Template, error) {
+	return s.repo.Templates(ctx, supplierID)
+}
+
+func (s *Service) TemplateVersions(ctx context.Context, id int64) ([]model.TemplateVersion, error) {
+	return s.repo.TemplateVersions(ctx, id)
+}
+
+// CreateOrderContract creates the contract for an order. Under an active
+// framework agreement the contract is a specification referring to the
+// agreement; otherwise the supplier's default template (or the global one,
+// or the built-in text) is rendered with the order details.
+func (s *Service) CreateOrderContract(ctx context.Context, details *model.OrderDetails) (int64, error) {
+	contract := &model.Contract{
+		OrderID:    details.OrderID,
+		SupplierID: details.SupplierID,
+		CustomerID: details.CustomerID,
+	}
+
+	agreement, err := s.repo.ActiveAgreement(ctx, details.SupplierID, details.CustomerID, time.Now())
+	if err != nil && !errors.Is(err, model.ErrNoRows) {
+		return 0, err
+	}
+
+	body := model.DefaultTemplateBody
+	if agreement != nil {
+		body = model.SpecificationTemplateBody
+		details.Agreement = agreement
+		contract.AgreementID = sql.NullInt64{Int64: agreement.ID, Valid: true}
+	} else {
+		t, err := s.repo.DefaultTemplate(ctx, details.SupplierID)
+		switch {
+		case err == nil:
+			body = t.Body
+			contract.TemplateID = sql.NullInt64{Int64: t.ID, Valid: true}
+			contract.TemplateVersion = sql.NullInt64{Int64: int64(t.Version), Valid: true}
+		case !errors.Is(err, model.ErrNoRows):
+			return 0, err
+		}
+	}
+
+	if details.Date.IsZero() {
+		details.Date = time.Now()
+	}
+	contract.Content, err = model.Render(body, details)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.create(ctx, contract)
+}
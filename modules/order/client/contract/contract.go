@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"context"
+	contractModel "diploma/modules/contract/model"
+	"diploma/modules/order/model"
+)
+
+type ContractClient struct {
+	contractService IContractService
+}
+
+func NewClient(contractService IContractService) *ContractClient {
+	return &ContractClient{contractService: contractService}
+}
+
+type IContractService interface {
+	CreateOrderContract(ctx context.Context, details *contractModel.OrderDetails) (int64, error)
+	CloseOrderContracts(ctx context.Context, orderID int64, reason string) error
+}
+
+func (c *ContractClient) CreateContract(ctx context.Context, details *model.ContractDetails) (int64, error) {
+	return c.contractService.CreateOrderContract(ctx, toContractDetails(details))
+}
+
+func (c *ContractClient) CloseContracts(ctx context.Context, orderID int64, reason string) error {
+	return c.contractService.CloseOrderContracts(ctx, orderID, reason)
+}
+
+func toContractDetails(details *model.ContractDetails) *contractModel.OrderDetails {
+	order := details.Order
+	subtotal, deliveryFee, total := order.Totals()
+
+	res := &contractModel.OrderDetails{
+		OrderID:    order.ID,
+		SupplierID: order.SupplierID,
+		CustomerID: order.CustomerID,
+		Supplier: contractModel.Party{
+			ID:    details.Seller.ID,
+			Name:  details.Seller.Name,
+			Phone: details.Seller.PhoneNumber,
+		},
+		Customer: contractModel.Party{
+			ID:    details.Customer.ID,
+			Name:  details.Customer.Name,
+			Phone: details.Customer.PhoneNumber,
+		},
+		Lines:           make([]contractModel.OrderLine, 0, len(order.ProductList)),
+		Subtotal:        subtotal,
+		DeliveryFee:     deliveryFee,
+		Total:           total,
+		DeliveryAddress: order.DeliveryAddress,
+		DeliveryDate:    order.OrderDate,
+	}
+	if order.Supplier != nil {
+		res.MinOrderAmount = order.Supplier.OrderAmount
+		res.FreeDeliveryAmount = order.Supplier.FreeDeliveryAmount
+	}
+
+	for i, op := range order.ProductList {
+		line := contractModel.OrderLine{
+			No:       i + 1,
+			Quantity: op.Quantity,
+			Price:    op.Price,
+			Amount:   op.Price * op.Quantity,
+		}
+		if op.Product != nil {
+			line.Name = op.Product.Name
+		}
+		res.Lines = append(res.Lines, line)
+	}
+	return res
+}
@@ -25,9 +25,10 @@ func (a *ProductClient) Product(ctx context.Context, id int64) (*model.Product,
 	}
 	// Convert clientModel.Product to model.Product
 	return &model.Product{
-		ID:       product.ID,
-		Name:     product.Name,
-		ImageUrl: product.ImageUrl,
+		ID:          product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		ImageUrl:    product.ImageUrl,
 	}, nil
 
 }
@@ -0,0 +1,32 @@
+package user
+
+import (
+	"context"
+	"diploma/modules/order/model"
+	userModel "diploma/modules/user/model"
+)
+
+type UserClient struct {
+	userService IUserService
+}
+
+func NewClient(userService IUserService) *UserClient {
+	return &UserClient{userService: userService}
+}
+
+type IUserService interface {
+	User(ctx context.Context, userID int64) (userModel.User, error)
+}
+
+func (c *UserClient) Contact(ctx context.Context, userID int64) (*model.Contact, error) {
+	user, err := c.userService.User(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Contact{
+		ID:          user.ID,
+		Name:        user.Name,
+		PhoneNumber: user.PhoneNumber,
+	}, nil
+}
@@ -8,9 +8,10 @@ import (
 // ConvertOrderToAPI converts service Order model to API Order response
 func ConvertOrderToAPI(order *serviceModel.Order) *apiModel.Order {
 	return &apiModel.Order{
-		ID:        order.ID,
-		Status:    convertStatusIDToString(order.StatusID),
-		OrderDate: order.OrderDate.Format("2006-01-02T15:04:05Z07:00"),
+		ID:              order.ID,
+		Status:          StatusIDToString(order.StatusID),
+		OrderDate:       order.OrderDate.Format("2006-01-02T15:04:05Z07:00"),
+		DeliveryAddress: order.DeliveryAddress,
 		Supplier: &apiModel.Supplier{
 			ID:   order.Supplier.ID,
 			Name: order.Supplier.Name,
@@ -34,8 +35,8 @@ func convertOrderProductsToAPI(products []*serviceModel.OrderProduct) []*apiMode
 	return apiProducts
 }
 
-// convertStatusIDToString converts a status ID to a string representation
-func convertStatusIDToString(statusID int) string {
+// StatusIDToString converts a status ID to a string representation
+func StatusIDToString(statusID int) string {
 	switch statusID {
 	case serviceModel.Pending:
 		return "Pending"
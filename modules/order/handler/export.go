@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"diploma/modules/auth/jwt"
+	"diploma/modules/order/handler/export"
+	modelApi "diploma/modules/order/handler/model"
+	"diploma/modules/order/model"
+	contextkeys "diploma/pkg/context-keys"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportOrders godoc
+// @Summary Export supplier orders
+// @Description Streams the supplier's order lines as CSV or XLSX. Accepts the same filters as the order list.
+// @Tags orders
+// @Security ApiKeyAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "Export format: csv (default) or xlsx"
+// @Param status query int false "Status ID"
+// @Param date_from query string false "Orders dated on or after this day (YYYY-MM-DD)"
+// @Param date_to query string false "Orders dated on or before this day (YYYY-MM-DD)"
+// @Success 200 {file} file "Order lines"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid filter or format"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Router /api/order/export [get]
+func (h *OrderHandler) ExportOrders(c *gin.Context) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: modelApi.ErrUnauthorized.Error()})
+		return
+	}
+
+	if claims.Role != model.SupplierRole {
+		c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: "only suppliers can export orders"})
+		return
+	}
+
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "format must be csv or xlsx"})
+		return
+	}
+
+	// The response is committed with the first row, so the export can
+	// only be aborted once rows have been written.
+	var writer export.Writer
+	err = h.service.ExportSupplierOrders(c.Request.Context(), claims.UserID, filter, func(row *model.OrderExportRow) error {
+		if writer == nil {
+			if writer, err = startExport(c, format); err != nil {
+				return err
+			}
+		}
+		return writer.Write(row)
+	})
+	if err == nil && writer == nil {
+		writer, err = startExport(c, format)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		if writer == nil {
+			c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+			return
+		}
+		c.Abort()
+	}
+}
+
+func startExport(c *gin.Context, format string) (export.Writer, error) {
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="orders-%s.%s"`, time.Now().Format("20060102"), format))
+	c.Status(http.StatusOK)
+	return export.NewWriter(format, c.Writer)
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"diploma/modules/order/handler/converter"
+	"diploma/modules/order/model"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var header = []string{
+	"Order ID",
+	"Status",
+	"Order date",
+	"Customer",
+	"Delivery address",
+	"Product ID",
+	"Product",
+	"GTIN",
+	"Quantity",
+	"Price",
+	"Total",
+}
+
+// Writer writes order lines in one of the export formats.
+type Writer interface {
+	Write(row *model.OrderExportRow) error
+	// Close flushes buffered data. It must be called once all rows are written.
+	Close() error
+}
+
+// ContentType returns the MIME type of the format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// NewWriter creates a writer for the given format.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func record(row *model.OrderExportRow) []string {
+	return []string{
+		strconv.FormatInt(row.OrderID, 10),
+		converter.StatusIDToString(row.StatusID),
+		row.OrderDate.Format("2006-01-02 15:04"),
+		row.CustomerName,
+		row.DeliveryAddress,
+		strconv.FormatInt(row.ProductID, 10),
+		row.ProductName,
+		formatGTIN(row.GTIN),
+		strconv.Itoa(row.Quantity),
+		strconv.Itoa(row.Price),
+		strconv.Itoa(row.Price * row.Quantity),
+	}
+}
+
+func formatGTIN(gtin int64) string {
+	if gtin == 0 {
+		return ""
+	}
+	return strconv.FormatInt(gtin, 10)
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// UTF-8 BOM so that Excel opens Cyrillic text correctly.
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(row *model.OrderExportRow) error {
+	return cw.w.Write(record(row))
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxWriter uses the excelize stream writer, which keeps only a small
+// window of rows in memory and spills the rest to a temporary file.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rowNum int
+}
+
+const sheetName = "Orders"
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheetName); err != nil {
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	xw := &xlsxWriter{out: w, file: file, stream: stream, rowNum: 1}
+	cells := make([]interface{}, len(header))
+	for i, title := range header {
+		cells[i] = title
+	}
+	if err := xw.writeCells(cells); err != nil {
+		return nil, err
+	}
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(row *model.OrderExportRow) error {
+	return xw.writeCells([]interface{}{
+		row.OrderID,
+		converter.StatusIDToString(row.StatusID),
+		row.OrderDate,
+		row.CustomerName,
+		row.DeliveryAddress,
+		row.ProductID,
+		row.ProductName,
+		formatGTIN(row.GTIN),
+		row.Quantity,
+		row.Price,
+		row.Price * row.Quantity,
+	})
+}
+
+func (xw *xlsxWriter) writeCells(cells []interface{}) error {
+	cell, err := excelize.CoordinatesToCellName(1, xw.rowNum)
+	if err != nil {
+		return err
+	}
+	xw.rowNum++
+	return xw.stream.SetRow(cell, cells)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
@@ -6,11 +6,12 @@ import (
 )
 
 type OrderHandler struct {
-	service IOrderService
+	service         IOrderService
+	invoiceRenderer IInvoiceRenderer
 }
 
-func NewHandler(service IOrderService) *OrderHandler {
-	return &OrderHandler{service: service}
+func NewHandler(service IOrderService, invoiceRenderer IInvoiceRenderer) *OrderHandler {
+	return &OrderHandler{service: service, invoiceRenderer: invoiceRenderer}
 }
 
 type IOrderService interface {
@@ -18,4 +19,10 @@ type IOrderService interface {
 	GetOrderByID(ctx context.Context, userID int64, role int, orderID int64) (*model.Order, error)
 	UpdateOrderStatusBySupplier(ctx context.Context, supplierID int64, orderID int64, newStatusID int) error
 	CancelOrderByCustomer(ctx context.Context, customerID int64, orderID int64) error
+	Invoice(ctx context.Context, userID int64, role int, orderID int64) (*model.Invoice, error)
+	ExportSupplierOrders(ctx context.Context, supplierID int64, filter model.OrderFilter, write func(row *model.OrderExportRow) error) error
+}
+
+type IInvoiceRenderer interface {
+	Render(invoice *model.Invoice) ([]byte, error)
 }
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"diploma/modules/auth/jwt"
+	modelApi "diploma/modules/order/handler/model"
+	"diploma/modules/order/model"
+	contextkeys "diploma/pkg/context-keys"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetInvoice godoc
+// @Summary Download order invoice
+// @Description Returns the PDF invoice of the order. The invoice number is issued on the first request and stays the same afterwards.
+// @Tags orders
+// @Security ApiKeyAuth
+// @Produce application/pdf
+// @Param id path int true "Order ID"
+// @Success 200 {file} file "Invoice PDF"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid order ID or cancelled order"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized: invalid or missing JWT token"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error while building invoice"
+// @Router /api/order/{id}/invoice [get]
+func (h *OrderHandler) GetInvoice(c *gin.Context) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: modelApi.ErrUnauthorized.Error()})
+		return
+	}
+
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid order ID"})
+		return
+	}
+
+	invoice, err := h.service.Invoice(c.Request.Context(), claims.UserID, claims.Role, orderID)
+	if err != nil {
+		if errors.Is(err, model.ErrInvoiceUnavailable) {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	body, err := h.invoiceRenderer.Render(invoice)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="invoice-%s.pdf"`, invoice.FullNumber()))
+	c.Data(http.StatusOK, "application/pdf", body)
+}
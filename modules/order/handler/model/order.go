@@ -15,11 +15,12 @@ type GetOrdersResponse struct {
 }
 
 type Order struct {
-	ID          int64      `json:"id"`
-	Status      string     `json:"status"`
-	OrderDate   string     `json:"order_date"`
-	Supplier    *Supplier  `json:"supplier"`
-	ProductList []*Product `json:"product_list"`
+	ID              int64      `json:"id"`
+	Status          string     `json:"status"`
+	OrderDate       string     `json:"order_date"`
+	DeliveryAddress string     `json:"delivery_address"`
+	Supplier        *Supplier  `json:"supplier"`
+	ProductList     []*Product `json:"product_list"`
 }
 type Product struct {
 	ID       int64  `json:"id"`
@@ -7,13 +7,15 @@ import (
 	modelApi "diploma/modules/order/handler/model"
 	"diploma/modules/order/model"
 	contextkeys "diploma/pkg/context-keys"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 type ICreateOrderService interface {
-	Orders(ctx context.Context, userID int64, role int) ([]*model.Order, error)
+	Orders(ctx context.Context, userID int64, role int, filter model.OrderFilter) ([]*model.Order, error)
 	// CreateOrder(userID int64) error
 }
 
@@ -24,7 +26,11 @@ type ICreateOrderService interface {
 // @Security ApiKeyAuth
 // @Accept json
 // @Produce json
+// @Param status query int false "Status ID"
+// @Param date_from query string false "Orders dated on or after this day (YYYY-MM-DD)"
+// @Param date_to query string false "Orders dated on or before this day (YYYY-MM-DD)"
 // @Success 200 {array} modelApi.GetOrdersResponse "List of orders"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid filter"
 // @Failure 401 {object} modelApi.ErrorResponse "Unauthorized: invalid or missing JWT token"
 // @Failure 500 {object} modelApi.ErrorResponse "Internal server error while retrieving orders"
 // @Router /api/order [get]
@@ -36,8 +42,14 @@ func (h *OrderHandler) GetOrders(c *gin.Context) {
 		return
 	}
 
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
 	// Retrieve the orders for the authenticated user
-	orders, err := h.service.Orders(c.Request.Context(), claims.UserID, claims.Role)
+	orders, err := h.service.Orders(c.Request.Context(), claims.UserID, claims.Role, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
 		return
@@ -46,6 +58,37 @@ func (h *OrderHandler) GetOrders(c *gin.Context) {
 	c.JSON(http.StatusOK, converter.ConvertOrdersToAPI(orders))
 }
 
+// parseOrderFilter reads the order filter from the query string.
+func parseOrderFilter(c *gin.Context) (model.OrderFilter, error) {
+	var filter model.OrderFilter
+
+	if status := c.Query("status"); status != "" {
+		statusID, err := strconv.Atoi(status)
+		if err != nil || statusID < model.Pending || statusID > model.Cancelled {
+			return filter, fmt.Errorf("invalid status")
+		}
+		filter.StatusID = statusID
+	}
+
+	if dateFrom := c.Query("date_from"); dateFrom != "" {
+		t, err := time.Parse(time.DateOnly, dateFrom)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_from, expected YYYY-MM-DD")
+		}
+		filter.DateFrom = t
+	}
+
+	if dateTo := c.Query("date_to"); dateTo != "" {
+		t, err := time.Parse(time.DateOnly, dateTo)
+		if err != nil {
+			return filter, fmt.Errorf("invalid date_to, expected YYYY-MM-DD")
+		}
+		filter.DateTo = t.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
 // GetOrderByID godoc
 // @Summary Get order by ID
 // @Description Retrieves an order by its ID.
@@ -0,0 +1,9 @@
+package model
+
+// ContractDetails is the order data rendered into the contract created when
+// the supplier accepts the order.
+type ContractDetails struct {
+	Order    *Order
+	Customer *Contact
+	Seller   *Contact
+}
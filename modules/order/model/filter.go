@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// OrderFilter narrows down order listings and exports. Zero values mean
+// "no restriction".
+type OrderFilter struct {
+	StatusID int
+	DateFrom time.Time
+	// DateTo is exclusive.
+	DateTo time.Time
+}
+
+// OrderExportRow is a single order line joined with its order and customer.
+type OrderExportRow struct {
+	OrderID         int64
+	StatusID        int
+	OrderDate       time.Time
+	CustomerID      int64
+	CustomerName    string
+	DeliveryAddress string
+	ProductID       int64
+	ProductName     string
+	GTIN            int64
+	Quantity        int
+	Price           int
+}
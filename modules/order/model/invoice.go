@@ -0,0 +1,43 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvoiceUnavailable = errors.New("models: invoice is not available for a cancelled order")
+
+type Invoice struct {
+	ID         int64
+	OrderID    int64
+	SupplierID int64
+	Number     int
+	IssuedAt   time.Time
+
+	Order    *Order
+	Customer *Contact
+	Seller   *Contact
+
+	Subtotal    int
+	DeliveryFee int
+	Total       int
+}
+
+// Contact holds the user details printed on documents.
+type Contact struct {
+	ID          int64
+	Name        string
+	PhoneNumber string
+}
+
+// FullNumber is the human-readable invoice number, unique across suppliers.
+func (i *Invoice) FullNumber() string {
+	return fmt.Sprintf("%d-%06d", i.SupplierID, i.Number)
+}
+
+// CalculateTotals fills Subtotal, DeliveryFee and Total from the order lines
+// and the supplier delivery conditions.
+func (i *Invoice) CalculateTotals() {
+	i.Subtotal, i.DeliveryFee, i.Total = i.Order.Totals()
+}
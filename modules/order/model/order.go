@@ -18,9 +18,31 @@ type Order struct {
 	OrderDate   time.Time
 	ProductList []*OrderProduct
 
+	// AddressID points to the address book entry chosen at checkout.
+	// DeliveryAddress is the text of that entry at that moment and is what
+	// the order is delivered to.
+	AddressID       int64
+	DeliveryAddress string
+
+	// DeliverySlotID is the supplier delivery slot booked at checkout;
+	// OrderDate is then the start of that slot.
+	DeliverySlotID int64
+
 	Supplier *Supplier
 }
 
+// Totals returns the sum of the order lines, the delivery fee charged by
+// the supplier for that sum, and the amount due.
+func (o *Order) Totals() (subtotal, deliveryFee, total int) {
+	for _, op := range o.ProductList {
+		subtotal += op.Price * op.Quantity
+	}
+	if o.Supplier != nil && subtotal < o.Supplier.FreeDeliveryAmount {
+		deliveryFee = o.Supplier.DeliveryFee
+	}
+	return subtotal, deliveryFee, subtotal + deliveryFee
+}
+
 type OrderProduct struct {
 	Quantity  int
 	Price     int
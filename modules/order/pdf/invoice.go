@@ -0,0 +1,105 @@
+package pdf
+
+import (
+	"diploma/modules/order/model"
+	"diploma/pkg/pdf"
+	"fmt"
+	"strconv"
+)
+
+type InvoiceRenderer struct {
+	fontDir string
+}
+
+func NewInvoiceRenderer(fontDir string) *InvoiceRenderer {
+	return &InvoiceRenderer{fontDir: fontDir}
+}
+
+// Render builds the invoice document. The output only depends on the invoice,
+// so repeated downloads of the same invoice are identical.
+func (r *InvoiceRenderer) Render(invoice *model.Invoice) ([]byte, error) {
+	doc, err := pdf.New(r.fontDir)
+	if err != nil {
+		return nil, err
+	}
+	doc.SetFixedDates(invoice.IssuedAt)
+	doc.SetTitle("Счёт на оплату №"+invoice.FullNumber(), true)
+	doc.AddPage()
+
+	doc.SetFont(pdf.FontFamily, "B", 16)
+	doc.CellFormat(0, 10, "Счёт на оплату №"+invoice.FullNumber(), "", 1, "L", false, 0, "")
+	doc.SetFont(pdf.FontFamily, "", 10)
+	doc.CellFormat(0, 6, "Дата: "+invoice.IssuedAt.Format("02.01.2006"), "", 1, "L", false, 0, "")
+	doc.CellFormat(0, 6, fmt.Sprintf("Заказ №%d от %s", invoice.Order.ID, invoice.Order.OrderDate.Format("02.01.2006")), "", 1, "L", false, 0, "")
+	doc.Ln(4)
+
+	supplierName := invoice.Seller.Name
+	if invoice.Order.Supplier != nil && invoice.Order.Supplier.Name != "" {
+		supplierName = invoice.Order.Supplier.Name
+	}
+	party(doc, "Поставщик", supplierName, invoice.Seller)
+	party(doc, "Покупатель", invoice.Customer.Name, invoice.Customer)
+	if invoice.Order.DeliveryAddress != "" {
+		doc.SetFont(pdf.FontFamily, "B", 10)
+		doc.CellFormat(30, 6, "Адрес доставки:", "", 0, "L", false, 0, "")
+		doc.SetFont(pdf.FontFamily, "", 10)
+		doc.MultiCell(0, 6, invoice.Order.DeliveryAddress, "", "L", false)
+	}
+	doc.Ln(4)
+
+	widths := []float64{10, 90, 20, 30, 30}
+	doc.SetFont(pdf.FontFamily, "B", 10)
+	for i, title := range []string{"№", "Товар", "Кол-во", "Цена", "Сумма"} {
+		doc.CellFormat(widths[i], 7, title, "1", 0, "C", false, 0, "")
+	}
+	doc.Ln(-1)
+
+	doc.SetFont(pdf.FontFamily, "", 10)
+	for i, op := range invoice.Order.ProductList {
+		name := strconv.FormatInt(op.ProductID, 10)
+		if op.Product != nil {
+			name = op.Product.Name
+		}
+		doc.CellFormat(widths[0], 7, strconv.Itoa(i+1), "1", 0, "C", false, 0, "")
+		doc.CellFormat(widths[1], 7, truncate(doc, name, widths[1]), "1", 0, "L", false, 0, "")
+		doc.CellFormat(widths[2], 7, strconv.Itoa(op.Quantity), "1", 0, "R", false, 0, "")
+		doc.CellFormat(widths[3], 7, pdf.Money(op.Price), "1", 0, "R", false, 0, "")
+		doc.CellFormat(widths[4], 7, pdf.Money(op.Price*op.Quantity), "1", 1, "R", false, 0, "")
+	}
+
+	labelWidth := widths[0] + widths[1] + widths[2] + widths[3]
+	total(doc, labelWidth, widths[4], "Итого по товарам:", invoice.Subtotal)
+	total(doc, labelWidth, widths[4], "Доставка:", invoice.DeliveryFee)
+	doc.SetFont(pdf.FontFamily, "B", 10)
+	total(doc, labelWidth, widths[4], "Всего к оплате:", invoice.Total)
+
+	return doc.Bytes()
+}
+
+func party(doc *pdf.Document, title, name string, contact *model.Contact) {
+	doc.SetFont(pdf.FontFamily, "B", 10)
+	doc.CellFormat(30, 6, title+":", "", 0, "L", false, 0, "")
+	doc.SetFont(pdf.FontFamily, "", 10)
+	line := name
+	if contact.PhoneNumber != "" {
+		line += ", тел. " + contact.PhoneNumber
+	}
+	doc.MultiCell(0, 6, line, "", "L", false)
+}
+
+func total(doc *pdf.Document, labelWidth, valueWidth float64, label string, amount int) {
+	doc.CellFormat(labelWidth, 7, label, "", 0, "R", false, 0, "")
+	doc.CellFormat(valueWidth, 7, pdf.Money(amount), "1", 1, "R", false, 0, "")
+}
+
+// truncate shortens s so it fits in a table cell of the given width.
+func truncate(doc *pdf.Document, s string, width float64) string {
+	runes := []rune(s)
+	for len(runes) > 0 && doc.GetStringWidth(string(runes))+2 > width {
+		runes = runes[:len(runes)-1]
+	}
+	if len(runes) < len([]rune(s)) && len(runes) > 1 {
+		return string(runes[:len(runes)-1]) + "…"
+	}
+	return string(runes)
+}
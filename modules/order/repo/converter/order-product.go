@@ -16,10 +16,13 @@ func ToServiceOrderProductFromRepo(op modelRepo.OrderProduct) *model.OrderProduc
 
 func ToServiceOrderFromRepo(o *modelRepo.Order) *model.Order {
 	return &model.Order{
-		ID:         o.ID,
-		CustomerID: o.CustomerID,
-		SupplierID: o.SupplierID,
-		StatusID:   o.StatusID,
-		OrderDate:  o.OrderDate,
+		ID:              o.ID,
+		CustomerID:      o.CustomerID,
+		SupplierID:      o.SupplierID,
+		StatusID:        o.StatusID,
+		OrderDate:       o.OrderDate,
+		AddressID:       o.AddressID.Int64,
+		DeliveryAddress: o.DeliveryAddress,
+		DeliverySlotID:  o.DeliverySlotID.Int64,
 	}
 }
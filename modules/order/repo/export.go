@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"diploma/modules/order/model"
+	"diploma/pkg/client/db"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ExportOrderLines streams every order line of the supplier that matches the
+// filter to fn, one row at a time, without loading the result into memory.
+func (r *OrderRepo) ExportOrderLines(ctx context.Context, supplierID int64, filter model.OrderFilter, fn func(row *model.OrderExportRow) error) error {
+	builder := sq.
+		Select(
+			"o.id",
+			"o.status_id",
+			"o.order_date",
+			"o.customer_id",
+			"c.name",
+			"o.delivery_address",
+			"op.product_id",
+			"p.name",
+			"COALESCE(p.gtin, 0)",
+			"op.quantity",
+			"op.price",
+		).
+		From(ordersTable+" o").
+		Join(orderProductsTable+" op ON op.order_id = o.id").
+		Join("products p ON p.id = op.product_id").
+		Join("users c ON c.id = o.customer_id").
+		Where(sq.Eq{"o." + oSupplierIDColumn: supplierID}).
+		OrderBy("o.order_date DESC", "o.id", "op.product_id").
+		PlaceholderFormat(sq.Dollar)
+	builder = applyOrderFilter(builder, "o.", filter)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	q := db.Query{
+		Name:     "order_repository.ExportOrderLines",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.OrderExportRow
+		if err := rows.Scan(
+			&row.OrderID,
+			&row.StatusID,
+			&row.OrderDate,
+			&row.CustomerID,
+			&row.CustomerName,
+			&row.DeliveryAddress,
+			&row.ProductID,
+			&row.ProductName,
+			&row.GTIN,
+			&row.Quantity,
+			&row.Price,
+		); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
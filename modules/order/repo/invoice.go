@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"diploma/modules/order/model"
+	"diploma/pkg/client/db"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	invoicesTable       = "invoices"
+	iIDColumn           = "id"
+	iOrderIDColumn      = "order_id"
+	iSupplierIDColumn   = "supplier_id"
+	iNumberColumn       = "number"
+	iIssuedAtColumn     = "issued_at"
+	invoiceCounterTable = "invoice_counters"
+)
+
+// LockOrder takes a row lock on the order until the end of the transaction.
+func (r *OrderRepo) LockOrder(ctx context.Context, orderID int64) error {
+	builder := sq.
+		Select(oIDColumn).
+		From(ordersTable).
+		Where(sq.Eq{oIDColumn: orderID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	q := db.Query{
+		Name:     "order_repository.LockOrder",
+		QueryRaw: query,
+	}
+
+	var id int64
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrNoRows
+	}
+	return err
+}
+
+func (r *OrderRepo) InvoiceByOrderID(ctx context.Context, orderID int64) (*model.Invoice, error) {
+	builder := sq.
+		Select(iIDColumn, iOrderIDColumn, iSupplierIDColumn, iNumberColumn, iIssuedAtColumn).
+		From(invoicesTable).
+		Where(sq.Eq{iOrderIDColumn: orderID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "order_repository.InvoiceByOrderID",
+		QueryRaw: query,
+	}
+
+	var invoice model.Invoice
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(
+		&invoice.ID,
+		&invoice.OrderID,
+		&invoice.SupplierID,
+		&invoice.Number,
+		&invoice.IssuedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// CreateInvoice allocates the next invoice number of the supplier and stores
+// the invoice. Must be called inside a transaction: the counter row stays
+// locked until commit, which keeps the numbers gap-free under concurrency.
+func (r *OrderRepo) CreateInvoice(ctx context.Context, orderID, supplierID int64) (*model.Invoice, error) {
+	q := db.Query{
+		Name: "order_repository.NextInvoiceNumber",
+		QueryRaw: `INSERT INTO ` + invoiceCounterTable + ` (supplier_id, last_number) VALUES ($1, 1)
+			ON CONFLICT (supplier_id) DO UPDATE SET last_number = ` + invoiceCounterTable + `.last_number + 1
+			RETURNING last_number`,
+	}
+
+	var number int
+	if err := r.db.DB().QueryRowContext(ctx, q, supplierID).Scan(&number); err != nil {
+		return nil, err
+	}
+
+	builder := sq.
+		Insert(invoicesTable).
+		Columns(iOrderIDColumn, iSupplierIDColumn, iNumberColumn).
+		Values(orderID, supplierID, number).
+		Suffix("RETURNING " + iIDColumn + ", " + iIssuedAtColumn).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q = db.Query{
+		Name:     "order_repository.CreateInvoice",
+		QueryRaw: query,
+	}
+
+	invoice := &model.Invoice{
+		OrderID:    orderID,
+		SupplierID: supplierID,
+		Number:     number,
+	}
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&invoice.ID, &invoice.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
@@ -1,11 +1,17 @@
 package model
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Order struct {
-	ID         int64
-	SupplierID int64
-	CustomerID int64
-	StatusID   int
-	OrderDate  time.Time
+	ID              int64
+	SupplierID      int64
+	CustomerID      int64
+	StatusID        int
+	OrderDate       time.Time
+	AddressID       sql.NullInt64
+	DeliveryAddress string
+	DeliverySlotID  sql.NullInt64
 }
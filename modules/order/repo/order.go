@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"diploma/modules/order/model"
 	"diploma/modules/order/repo/converter"
 	modelRepo "diploma/modules/order/repo/model"
@@ -11,12 +12,15 @@ import (
 )
 
 const (
-	ordersTable       = "orders"
-	oIDColumn         = "id"
-	oCustomerIDColumn = "customer_id"
-	oOrderDateColumn  = "order_date"
-	oSupplierIDColumn = "supplier_id"
-	oStatusIDColumn   = "status_id"
+	ordersTable            = "orders"
+	oIDColumn              = "id"
+	oCustomerIDColumn      = "customer_id"
+	oOrderDateColumn       = "order_date"
+	oSupplierIDColumn      = "supplier_id"
+	oStatusIDColumn        = "status_id"
+	oAddressIDColumn       = "address_id"
+	oDeliveryAddressColumn = "delivery_address"
+	oDeliverySlotIDColumn  = "delivery_slot_id"
 )
 
 // CreateOrder inserts a new order record and returns its id.
@@ -24,8 +28,16 @@ func (r *OrderRepo) CreateOrder(ctx context.Context, order *model.Order) (int64,
 	builder := sq.
 		Insert(ordersTable).
 		PlaceholderFormat(sq.Dollar).
-		Columns(oCustomerIDColumn, oSupplierIDColumn, oOrderDateColumn, oStatusIDColumn).
-		Values(order.CustomerID, order.SupplierID, order.OrderDate, order.StatusID).
+		Columns(oCustomerIDColumn, oSupplierIDColumn, oOrderDateColumn, oStatusIDColumn, oAddressIDColumn, oDeliveryAddressColumn, oDeliverySlotIDColumn).
+		Values(
+			order.CustomerID,
+			order.SupplierID,
+			order.OrderDate,
+			order.StatusID,
+			sql.NullInt64{Int64: order.AddressID, Valid: order.AddressID != 0},
+			order.DeliveryAddress,
+			sql.NullInt64{Int64: order.DeliverySlotID, Valid: order.DeliverySlotID != 0},
+		).
 		Suffix("RETURNING " + oIDColumn)
 
 	query, args, err := builder.ToSql()
@@ -47,12 +59,13 @@ func (r *OrderRepo) CreateOrder(ctx context.Context, order *model.Order) (int64,
 }
 
 // GetOrder retrieves an order by its id.
-func (r *OrderRepo) OrdersByUserID(ctx context.Context, userID int64) ([]*model.Order, error) {
+func (r *OrderRepo) OrdersByUserID(ctx context.Context, userID int64, filter model.OrderFilter) ([]*model.Order, error) {
 	builder := sq.
-		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn).
+		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn, oAddressIDColumn, oDeliveryAddressColumn, oDeliverySlotIDColumn).
 		PlaceholderFormat(sq.Dollar).
 		From(ordersTable).
 		Where(sq.Eq{oCustomerIDColumn: userID})
+	builder = applyOrderFilter(builder, "", filter)
 
 	query, args, err := builder.ToSql()
 	if err != nil {
@@ -79,6 +92,9 @@ func (r *OrderRepo) OrdersByUserID(ctx context.Context, userID int64) ([]*model.
 			&orderRepo.OrderDate,
 			&orderRepo.SupplierID,
 			&orderRepo.StatusID,
+			&orderRepo.AddressID,
+			&orderRepo.DeliveryAddress,
+			&orderRepo.DeliverySlotID,
 		); err != nil {
 			return nil, err
 		}
@@ -94,12 +110,13 @@ func (r *OrderRepo) OrdersByUserID(ctx context.Context, userID int64) ([]*model.
 }
 
 // GetOrdersBySupplierID retrieves a list of orders by supplier id.
-func (r *OrderRepo) OrdersBySupplierID(ctx context.Context, supplierID int64) ([]*model.Order, error) {
+func (r *OrderRepo) OrdersBySupplierID(ctx context.Context, supplierID int64, filter model.OrderFilter) ([]*model.Order, error) {
 	builder := sq.
-		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn).
+		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn, oAddressIDColumn, oDeliveryAddressColumn, oDeliverySlotIDColumn).
 		PlaceholderFormat(sq.Dollar).
 		From(ordersTable).
 		Where(sq.Eq{oSupplierIDColumn: supplierID})
+	builder = applyOrderFilter(builder, "", filter)
 
 	query, args, err := builder.ToSql()
 	if err != nil {
@@ -126,6 +143,9 @@ func (r *OrderRepo) OrdersBySupplierID(ctx context.Context, supplierID int64) ([
 			&orderRepo.OrderDate,
 			&orderRepo.SupplierID,
 			&orderRepo.StatusID,
+			&orderRepo.AddressID,
+			&orderRepo.DeliveryAddress,
+			&orderRepo.DeliverySlotID,
 		); err != nil {
 			return nil, err
 		}
@@ -140,9 +160,24 @@ func (r *OrderRepo) OrdersBySupplierID(ctx context.Context, supplierID int64) ([
 	return orders, nil
 }
 
+// applyOrderFilter adds the filter conditions to a select over the orders
+// table. prefix is the table alias followed by a dot, or empty.
+func applyOrderFilter(builder sq.SelectBuilder, prefix string, filter model.OrderFilter) sq.SelectBuilder {
+	if filter.StatusID != 0 {
+		builder = builder.Where(sq.Eq{prefix + oStatusIDColumn: filter.StatusID})
+	}
+	if !filter.DateFrom.IsZero() {
+		builder = builder.Where(sq.GtOrEq{prefix + oOrderDateColumn: filter.DateFrom})
+	}
+	if !filter.DateTo.IsZero() {
+		builder = builder.Where(sq.Lt{prefix + oOrderDateColumn: filter.DateTo})
+	}
+	return builder
+}
+
 func (r *OrderRepo) GetOrderByID(ctx context.Context, orderID int64) (*model.Order, error) {
 	builder := sq.
-		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn).
+		Select(oIDColumn, oCustomerIDColumn, oOrderDateColumn, oSupplierIDColumn, oStatusIDColumn, oAddressIDColumn, oDeliveryAddressColumn, oDeliverySlotIDColumn).
 		From(ordersTable).
 		Where(sq.Eq{oIDColumn: orderID}).
 		PlaceholderFormat(sq.Dollar)
@@ -164,6 +199,9 @@ func (r *OrderRepo) GetOrderByID(ctx context.Context, orderID int64) (*model.Ord
 		&order.OrderDate,
 		&order.SupplierID,
 		&order.StatusID,
+		&order.AddressID,
+		&order.DeliveryAddress,
+		&order.DeliverySlotID,
 	)
 	if err != nil {
 		return nil, err
@@ -11,7 +11,9 @@ func RegisterRoutes(router *gin.RouterGroup, h *handler.OrderHandler) {
 	{
 		// orderRoutes.POST("", h.CreateOrder)
 		orderRoutes.GET("", h.GetOrders)
+		orderRoutes.GET("/export", h.ExportOrders)
 		orderRoutes.GET("/:id", h.GetOrderByID)
+		orderRoutes.GET("/:id/invoice", h.GetInvoice)
 		orderRoutes.POST("/status", h.UpdateOrderStatus)
 		orderRoutes.POST("/cancel", h.CancelOrder)
 	}
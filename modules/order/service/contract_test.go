@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"diploma/modules/order/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testOrderID    = int64(1)
+	testSupplierID = int64(10)
+)
+
+func (s *OrderServiceTestSuite) expectOrder(status int) {
+	s.orderRepo.On("GetOrderByID", mock.Anything, testOrderID).Return(&model.Order{
+		ID:         testOrderID,
+		SupplierID: testSupplierID,
+		CustomerID: 20,
+		StatusID:   status,
+	}, nil).Once()
+}
+
+func (s *OrderServiceTestSuite) TestCompleteOrderByContract() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Completed).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.CompleteOrderByContract(context.Background(), testOrderID))
+}
+
+func (s *OrderServiceTestSuite) TestCompleteOrderByContract_AlreadyCompleted() {
+	// repeated events are ignored
+	s.expectOrder(model.Completed)
+
+	s.helper.AssertNoError(s.service.CompleteOrderByContract(context.Background(), testOrderID))
+	s.orderRepo.AssertNotCalled(s.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCompleteOrderByContract_CancelledOrder() {
+	s.expectOrder(model.Cancelled)
+
+	s.helper.AssertError(s.service.CompleteOrderByContract(context.Background(), testOrderID))
+	s.orderRepo.AssertNotCalled(s.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrderByContract() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Cancelled).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.CancelOrderByContract(context.Background(), testOrderID))
+	// the contract side closed the contract itself
+	s.contracts.AssertNotCalled(s.T(), "CloseContracts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestUpdateOrderStatusBySupplier_CancelClosesContracts() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Cancelled).Return(nil).Once()
+	s.contracts.On("CloseContracts", mock.Anything, testOrderID, "order cancelled by supplier").Return(nil).Once()
+
+	err := s.service.UpdateOrderStatusBySupplier(context.Background(), testSupplierID, testOrderID, model.Cancelled)
+
+	s.helper.AssertNoError(err)
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrderByContract_CompletedOrder() {
+	s.expectOrder(model.Completed)
+
+	s.helper.AssertError(s.service.CancelOrderByContract(context.Background(), testOrderID))
+	s.orderRepo.AssertNotCalled(s.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrderByExpiredContract_CompletedOrder() {
+	s.expectOrder(model.Completed)
+
+	// an expired contract leaves a completed order as it is
+	s.helper.AssertNoError(s.service.CancelOrderByExpiredContract(context.Background(), testOrderID))
+	s.orderRepo.AssertNotCalled(s.T(), "UpdateOrderStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *OrderServiceTestSuite) TestCancelOrderByExpiredContract() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Cancelled).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.CancelOrderByExpiredContract(context.Background(), testOrderID))
+}
+
+func (s *OrderServiceTestSuite) TestUpdateOrderStatusBySupplier_CompleteClosesContracts() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Completed).Return(nil).Once()
+	s.contracts.On("CloseContracts", mock.Anything, testOrderID, "order completed by supplier").Return(nil).Once()
+
+	err := s.service.UpdateOrderStatusBySupplier(context.Background(), testSupplierID, testOrderID, model.Completed)
+
+	s.helper.AssertNoError(err)
+}
+
+func (s *OrderServiceTestSuite) TestUpdateOrderStatusBySupplier_CompleteFailsWhenContractsDoNotClose() {
+	s.expectOrder(model.InProgress)
+	s.orderRepo.On("UpdateOrderStatus", mock.Anything, testOrderID, model.Completed).Return(nil).Once()
+	s.contracts.On("CloseContracts", mock.Anything, testOrderID, "order completed by supplier").
+		Return(errors.New("connection reset")).Once()
+
+	err := s.service.UpdateOrderStatusBySupplier(context.Background(), testSupplierID, testOrderID, model.Completed)
+
+	s.helper.AssertError(err)
+}
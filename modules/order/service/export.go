@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/order/model"
+
+	"go.uber.org/zap"
+)
+
+type IExportRepo interface {
+	ExportOrderLines(ctx context.Context, supplierID int64, filter model.OrderFilter, fn func(row *model.OrderExportRow) error) error
+}
+
+// ExportSupplierOrders passes every order line of the supplier matching the
+// filter to write as soon as it is read from the database.
+func (s *OrderService) ExportSupplierOrders(ctx context.Context, supplierID int64, filter model.OrderFilter, write func(row *model.OrderExportRow) error) error {
+	s.LogInfo(ctx, "Exporting supplier orders",
+		zap.Int64("supplier_id", supplierID),
+		zap.Int("status_id", filter.StatusID),
+	)
+
+	rowCount := 0
+	err := s.orderRepo.ExportOrderLines(ctx, supplierID, filter, func(row *model.OrderExportRow) error {
+		rowCount++
+		return write(row)
+	})
+	if err != nil {
+		s.LogError(ctx, "Failed to export supplier orders", err,
+			zap.Int64("supplier_id", supplierID),
+		)
+		return err
+	}
+
+	s.LogInfo(ctx, "Successfully exported supplier orders",
+		zap.Int64("supplier_id", supplierID),
+		zap.Int("row_count", rowCount),
+	)
+	return nil
+}
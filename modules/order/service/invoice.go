@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/order/model"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+type IInvoiceRepo interface {
+	LockOrder(ctx context.Context, orderID int64) error
+	InvoiceByOrderID(ctx context.Context, orderID int64) (*model.Invoice, error)
+	CreateInvoice(ctx context.Context, orderID, supplierID int64) (*model.Invoice, error)
+}
+
+// Invoice returns the invoice of the order, issuing a new number the first
+// time it is requested. Later calls return the same number.
+func (s *OrderService) Invoice(ctx context.Context, userID int64, role int, orderID int64) (*model.Invoice, error) {
+	s.LogInfo(ctx, "Fetching order invoice",
+		zap.Int64("user_id", userID),
+		zap.Int64("order_id", orderID),
+	)
+
+	var invoice *model.Invoice
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, errTx := s.GetOrderByID(ctx, userID, role, orderID)
+		if errTx != nil {
+			return errTx
+		}
+
+		if order.StatusID == model.Cancelled {
+			return model.ErrInvoiceUnavailable
+		}
+
+		if errTx = s.orderRepo.LockOrder(ctx, orderID); errTx != nil {
+			s.LogError(ctx, "Failed to lock order", errTx)
+			return errTx
+		}
+
+		invoice, errTx = s.orderRepo.InvoiceByOrderID(ctx, orderID)
+		if errors.Is(errTx, model.ErrNoRows) {
+			s.LogDebug(ctx, "Issuing new invoice",
+				zap.Int64("order_id", orderID),
+				zap.Int64("supplier_id", order.SupplierID),
+			)
+			invoice, errTx = s.orderRepo.CreateInvoice(ctx, orderID, order.SupplierID)
+		}
+		if errTx != nil {
+			s.LogError(ctx, "Failed to get invoice", errTx)
+			return errTx
+		}
+
+		invoice.Order = order
+		invoice.Customer, errTx = s.userClient.Contact(ctx, order.CustomerID)
+		if errTx != nil {
+			s.LogError(ctx, "Failed to get customer details", errTx)
+			return errTx
+		}
+		invoice.Seller, errTx = s.userClient.Contact(ctx, order.SupplierID)
+		if errTx != nil {
+			s.LogError(ctx, "Failed to get supplier details", errTx)
+			return errTx
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.CalculateTotals()
+
+	s.LogInfo(ctx, "Successfully fetched order invoice",
+		zap.Int64("order_id", orderID),
+		zap.String("invoice_number", invoice.FullNumber()),
+	)
+	return invoice, nil
+}
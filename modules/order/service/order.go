@@ -10,12 +10,12 @@ import (
 )
 
 type IOrderRepo interface {
-	OrdersByUserID(ctx context.Context, userID int64) ([]*model.Order, error)
-	OrdersBySupplierID(ctx context.Context, supplierID int64) ([]*model.Order, error)
+	OrdersByUserID(ctx context.Context, userID int64, filter model.OrderFilter) ([]*model.Order, error)
+	OrdersBySupplierID(ctx context.Context, supplierID int64, filter model.OrderFilter) ([]*model.Order, error)
 	OrderProducts(ctx context.Context, orderID int64) ([]*model.OrderProduct, error)
 }
 
-func (s *OrderService) Orders(ctx context.Context, userID int64, role int) ([]*model.Order, error) {
+func (s *OrderService) Orders(ctx context.Context, userID int64, role int, filter model.OrderFilter) ([]*model.Order, error) {
 	s.LogInfo(ctx, "Fetching orders",
 		zap.Int64("user_id", userID),
 		zap.Int("role", role),
@@ -27,7 +27,7 @@ func (s *OrderService) Orders(ctx context.Context, userID int64, role int) ([]*m
 		switch role {
 		case model.CustomerRole:
 			s.LogDebug(ctx, "Fetching customer orders")
-			orders, errTx = s.orderRepo.OrdersByUserID(ctx, userID)
+			orders, errTx = s.orderRepo.OrdersByUserID(ctx, userID, filter)
 			if errTx != nil {
 				s.LogError(ctx, "Failed to fetch customer orders", errTx)
 				return errTx
@@ -45,7 +45,7 @@ func (s *OrderService) Orders(ctx context.Context, userID int64, role int) ([]*m
 
 		case model.SupplierRole:
 			s.LogDebug(ctx, "Fetching supplier orders")
-			orders, errTx = s.orderRepo.OrdersBySupplierID(ctx, userID)
+			orders, errTx = s.orderRepo.OrdersBySupplierID(ctx, userID, filter)
 			if errTx != nil {
 				s.LogError(ctx, "Failed to fetch supplier orders", errTx)
 				return errTx
@@ -107,7 +107,10 @@ func (s *OrderService) CreateOrder(ctx context.Context, orders []*model.Order) e
 	ordersID := make([]int64, len(orders))
 	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
 		for _, order := range orders {
-			order.OrderDate = time.Now().Add(72 * time.Hour)
+			// orders without a booked delivery slot keep the default lead time
+			if order.OrderDate.IsZero() {
+				order.OrderDate = time.Now().Add(72 * time.Hour)
+			}
 			order.StatusID = 1
 
 			s.LogDebug(ctx, "Creating order",
@@ -121,6 +124,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, orders []*model.Order) e
 				s.LogError(ctx, "Failed to create order", errTx)
 				return errTx
 			}
+			order.ID = id
 
 			for _, op := range order.ProductList {
 				op.OrderID = id
@@ -206,17 +210,32 @@ func (s *OrderService) UpdateOrderStatusBySupplier(ctx context.Context, supplier
 			return err
 		}
 
+		// an order finished by hand leaves no contract open for signing
+		var reason string
+		switch newStatusID {
+		case model.Cancelled:
+			reason = "order cancelled by supplier"
+		case model.Completed:
+			reason = "order completed by supplier"
+		}
+		if reason != "" {
+			err = s.contractClient.CloseContracts(ctx, orderID, reason)
+			if err != nil {
+				s.LogError(ctx, "Failed to close order contracts", err)
+				return err
+			}
+		}
+
 		if order.StatusID == model.Pending && newStatusID == model.InProgress {
 			s.LogInfo(ctx, "Creating contract for order",
 				zap.Int64("order_id", order.ID),
 			)
-			_, err := s.contractClient.CreateContract(
-				ctx,
-				order.ID,
-				order.SupplierID,
-				order.CustomerID,
-				fmt.Sprintf("Контракт для заказа #%d", order.ID),
-			)
+			details, err := s.contractDetails(ctx, supplierID, orderID)
+			if err != nil {
+				s.LogError(ctx, "Failed to collect contract details", err)
+				return err
+			}
+			_, err = s.contractClient.CreateContract(ctx, details)
 			if err != nil {
 				s.LogError(ctx, "Failed to create contract", err)
 				return err
@@ -351,3 +370,109 @@ func (s *OrderService) CancelOrderByCustomer(ctx context.Context, customerID int
 		return nil
 	})
 }
+
+// CompleteOrderByContract completes the order once both parties have
+// signed its contract. Orders already completed are left as they are.
+func (s *OrderService) CompleteOrderByContract(ctx context.Context, orderID int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			s.LogError(ctx, "Failed to get order", err)
+			return err
+		}
+
+		switch order.StatusID {
+		case model.Completed:
+			return nil
+		case model.InProgress:
+		default:
+			s.LogWarn(ctx, "Contract completed for an order not in progress",
+				zap.Int64("order_id", orderID),
+				zap.Int("current_status", order.StatusID),
+			)
+			return fmt.Errorf("invalid status transition from %d to %d", order.StatusID, model.Completed)
+		}
+
+		err = s.orderRepo.UpdateOrderStatus(ctx, orderID, model.Completed)
+		if err != nil {
+			s.LogError(ctx, "Failed to update order status", err)
+			return err
+		}
+
+		s.LogInfo(ctx, "Order completed by contract",
+			zap.Int64("order_id", orderID),
+		)
+		return nil
+	})
+}
+
+// CancelOrderByContract cancels the order after its contract was rejected.
+// Orders already cancelled are left as they are.
+func (s *OrderService) CancelOrderByContract(ctx context.Context, orderID int64) error {
+	return s.cancelByContract(ctx, orderID, false)
+}
+
+// CancelOrderByExpiredContract cancels the order after its contract expired.
+// An expired contract does not undo a completed order, so completed orders
+// are left as they are too.
+func (s *OrderService) CancelOrderByExpiredContract(ctx context.Context, orderID int64) error {
+	return s.cancelByContract(ctx, orderID, true)
+}
+
+func (s *OrderService) cancelByContract(ctx context.Context, orderID int64, keepCompleted bool) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			s.LogError(ctx, "Failed to get order", err)
+			return err
+		}
+
+		switch order.StatusID {
+		case model.Cancelled:
+			return nil
+		case model.Completed:
+			if keepCompleted {
+				return nil
+			}
+			s.LogWarn(ctx, "Contract closed for a completed order",
+				zap.Int64("order_id", orderID),
+			)
+			return fmt.Errorf("order %d is already completed", orderID)
+		}
+
+		err = s.orderRepo.UpdateOrderStatus(ctx, orderID, model.Cancelled)
+		if err != nil {
+			s.LogError(ctx, "Failed to update order status", err)
+			return err
+		}
+
+		s.LogInfo(ctx, "Order cancelled by contract",
+			zap.Int64("order_id", orderID),
+		)
+		return nil
+	})
+}
+
+// contractDetails gathers the order lines, totals, parties and delivery
+// terms rendered into the order contract.
+func (s *OrderService) contractDetails(ctx context.Context, supplierID, orderID int64) (*model.ContractDetails, error) {
+	order, err := s.GetOrderByID(ctx, supplierID, model.SupplierRole, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.userClient.Contact(ctx, order.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	seller, err := s.userClient.Contact(ctx, order.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ContractDetails{
+		Order:    order,
+		Customer: customer,
+		Seller:   seller,
+	}, nil
+}
@@ -13,6 +13,7 @@ type OrderService struct {
 	supplierClient ISupplierClient
 	productClient  IProductClient
 	contractClient IContractService
+	userClient     IUserClient
 	txManager      db.TxManager
 }
 
@@ -21,6 +22,7 @@ func NewService(
 	supplierClient ISupplierClient,
 	productClient IProductClient,
 	contractClient IContractService,
+	userClient IUserClient,
 	tx db.TxManager,
 ) *OrderService {
 	return &OrderService{
@@ -29,6 +31,7 @@ func NewService(
 		supplierClient: supplierClient,
 		productClient:  productClient,
 		contractClient: contractClient,
+		userClient:     userClient,
 		txManager:      tx,
 	}
 }
@@ -36,6 +39,8 @@ func NewService(
 type IOrderRepository interface {
 	ICreateOrderRepo
 	IOrderRepo
+	IInvoiceRepo
+	IExportRepo
 	UpdateOrderStatus(ctx context.Context, orderID int64, newStatus int) error
 	GetOrderByID(ctx context.Context, orderID int64) (*model.Order, error)
 }
@@ -49,5 +54,11 @@ type IProductClient interface {
 }
 
 type IContractService interface {
-	CreateContract(ctx context.Context, orderID, supplierID, customerID int64, content string) (int64, error)
+	CreateContract(ctx context.Context, details *model.ContractDetails) (int64, error)
+	// CloseContracts rejects the order's open contracts with the reason.
+	CloseContracts(ctx context.Context, orderID int64, reason string) error
+}
+
+type IUserClient interface {
+	Contact(ctx context.Context, userID int64) (*model.Contact, error)
 }
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"diploma/internal/testutils"
+	"diploma/modules/order/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockOrderRepository struct {
+	mock.Mock
+}
+
+func (m *mockOrderRepository) CreateInvoice(ctx context.Context, orderID, supplierID int64) (*model.Invoice, error) {
+	args := m.Called(ctx, orderID, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Invoice), args.Error(1)
+}
+
+func (m *mockOrderRepository) CreateOrder(ctx context.Context, order *model.Order) (int64, error) {
+	args := m.Called(ctx, order)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockOrderRepository) CreateOrderProduct(ctx context.Context, orderProduct *model.OrderProduct) error {
+	args := m.Called(ctx, orderProduct)
+	return args.Error(0)
+}
+
+func (m *mockOrderRepository) ExportOrderLines(ctx context.Context, supplierID int64, filter model.OrderFilter, fn func(row *model.OrderExportRow) error) error {
+	args := m.Called(ctx, supplierID, filter, fn)
+	return args.Error(0)
+}
+
+func (m *mockOrderRepository) GetOrderByID(ctx context.Context, orderID int64) (*model.Order, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Order), args.Error(1)
+}
+
+func (m *mockOrderRepository) InvoiceByOrderID(ctx context.Context, orderID int64) (*model.Invoice, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Invoice), args.Error(1)
+}
+
+func (m *mockOrderRepository) LockOrder(ctx context.Context, orderID int64) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *mockOrderRepository) OrderProducts(ctx context.Context, orderID int64) ([]*model.OrderProduct, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.OrderProduct), args.Error(1)
+}
+
+func (m *mockOrderRepository) OrdersBySupplierID(ctx context.Context, supplierID int64, filter model.OrderFilter) ([]*model.Order, error) {
+	args := m.Called(ctx, supplierID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Order), args.Error(1)
+}
+
+func (m *mockOrderRepository) OrdersByUserID(ctx context.Context, userID int64, filter model.OrderFilter) ([]*model.Order, error) {
+	args := m.Called(ctx, userID, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Order), args.Error(1)
+}
+
+func (m *mockOrderRepository) UpdateOrderStatus(ctx context.Context, orderID int64, newStatus int) error {
+	args := m.Called(ctx, orderID, newStatus)
+	return args.Error(0)
+}
+
+type mockContractService struct {
+	mock.Mock
+}
+
+func (m *mockContractService) CloseContracts(ctx context.Context, orderID int64, reason string) error {
+	args := m.Called(ctx, orderID, reason)
+	return args.Error(0)
+}
+
+func (m *mockContractService) CreateContract(ctx context.Context, details *model.ContractDetails) (int64, error) {
+	args := m.Called(ctx, details)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type OrderServiceTestSuite struct {
+	suite.Suite
+	service   *OrderService
+	orderRepo *mockOrderRepository
+	contracts *mockContractService
+	txManager *testutils.MockTxManager
+	helper    *testutils.AssertTestHelper
+}
+
+func TestOrderService(t *testing.T) {
+	suite.Run(t, new(OrderServiceTestSuite))
+}
+
+func (s *OrderServiceTestSuite) SetupTest() {
+	s.orderRepo = new(mockOrderRepository)
+	s.contracts = new(mockContractService)
+	s.txManager = new(testutils.MockTxManager)
+	s.helper = testutils.NewAssertTestHelper(s.T())
+
+	s.txManager.On("ReadCommitted", mock.Anything, mock.Anything).Return(nil)
+
+	s.service = NewService(s.orderRepo, nil, nil, s.contracts, nil, s.txManager)
+}
+
+func (s *OrderServiceTestSuite) TearDownTest() {
+	s.orderRepo.AssertExpectations(s.T())
+	s.contracts.AssertExpectations(s.T())
+}
@@ -0,0 +1,116 @@
+package nct
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"sync"
+	"time"
+)
+
+// cacheMaxEntries bounds the number of GTINs a Cache remembers.
+const cacheMaxEntries = 10000
+
+// Catalog looks products up by GTIN.
+type Catalog interface {
+	Lookup(ctx context.Context, gtin string) ([]model.Product, error)
+}
+
+// Cache remembers lookups of a catalog for a TTL, including GTINs the
+// catalog does not know. Failed lookups are not cached. When full, it
+// drops the expired entries, then the ones expiring first.
+type Cache struct {
+	catalog    Catalog
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	products  []model.Product
+	expiresAt time.Time
+}
+
+func NewCache(catalog Catalog, ttl time.Duration) *Cache {
+	return &Cache{
+		catalog:    catalog,
+		ttl:        ttl,
+		maxEntries: cacheMaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+func (c *Cache) Lookup(ctx context.Context, gtin string) ([]model.Product, error) {
+	if products, ok := c.get(gtin); ok {
+		return products, nil
+	}
+
+	products, err := c.catalog.Lookup(ctx, gtin)
+	if err != nil {
+		return nil, err
+	}
+
+	c.set(gtin, products)
+	return clone(products), nil
+}
+
+func (c *Cache) set(gtin string, products []model.Product) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if _, ok := c.entries[gtin]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[gtin] = cacheEntry{products: products, expiresAt: now.Add(c.ttl)}
+}
+
+// evict makes room for an entry: it drops the expired entries, or the one
+// expiring first if none has expired.
+func (c *Cache) evict(now time.Time) {
+	var (
+		first    string
+		firstAt  time.Time
+		released bool
+	)
+	for gtin, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, gtin)
+			released = true
+			continue
+		}
+		if first == "" || entry.expiresAt.Before(firstAt) {
+			first, firstAt = gtin, entry.expiresAt
+		}
+	}
+	if !released {
+		delete(c.entries, first)
+	}
+}
+
+func (c *Cache) get(gtin string) ([]model.Product, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[gtin]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expiresAt) {
+		delete(c.entries, gtin)
+		return nil, false
+	}
+	return clone(entry.products), true
+}
+
+// clone keeps callers, which fill in IDs and categories, from changing
+// cached products.
+func clone(products []model.Product) []model.Product {
+	if products == nil {
+		return nil
+	}
+	return append([]model.Product(nil), products...)
+}
@@ -0,0 +1,112 @@
+package nct
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCatalog struct {
+	calls int
+	err   error
+}
+
+func (c *countingCatalog) Lookup(_ context.Context, gtin string) ([]model.Product, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	if gtin == "unknown" {
+		return nil, nil
+	}
+	return []model.Product{{Name: "product " + gtin}}, nil
+}
+
+func TestCache_ExpiresAfterTTL(t *testing.T) {
+	catalog := &countingCatalog{}
+	cache := NewCache(catalog, time.Hour)
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		products, err := cache.Lookup(context.Background(), "1")
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+	}
+	assert.Equal(t, 1, catalog.calls)
+
+	now = now.Add(time.Hour)
+	_, err := cache.Lookup(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, catalog.calls)
+}
+
+func TestCache_UnknownGTINIsCached(t *testing.T) {
+	catalog := &countingCatalog{}
+	cache := NewCache(catalog, time.Hour)
+
+	for i := 0; i < 2; i++ {
+		products, err := cache.Lookup(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, products)
+	}
+	assert.Equal(t, 1, catalog.calls)
+}
+
+func TestCache_ErrorIsNotCached(t *testing.T) {
+	catalog := &countingCatalog{err: errors.New("timeout")}
+	cache := NewCache(catalog, time.Hour)
+
+	_, err := cache.Lookup(context.Background(), "1")
+	require.Error(t, err)
+
+	catalog.err = nil
+	products, err := cache.Lookup(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, 2, catalog.calls)
+}
+
+func TestCache_CallersDoNotChangeCachedProducts(t *testing.T) {
+	cache := NewCache(&countingCatalog{}, time.Hour)
+
+	products, err := cache.Lookup(context.Background(), "1")
+	require.NoError(t, err)
+	products[0].Name = "changed"
+
+	products, err = cache.Lookup(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "product 1", products[0].Name)
+}
+
+func TestCache_Bounded(t *testing.T) {
+	catalog := &countingCatalog{}
+	cache := NewCache(catalog, time.Hour)
+	cache.maxEntries = 2
+	now := time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for _, gtin := range []string{"1", "2"} {
+		_, err := cache.Lookup(context.Background(), gtin)
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+	}
+	_, err := cache.Lookup(context.Background(), "3")
+	require.NoError(t, err)
+
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "1", "the entry expiring first is dropped")
+
+	// expired entries all go at once
+	now = now.Add(2 * time.Hour)
+	_, err = cache.Lookup(context.Background(), "4")
+	require.NoError(t, err)
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, "4")
+}
@@ -0,0 +1,95 @@
+package nct
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	searchInputSelector  = `input[placeholder="Введите наименование или номер штрихкода"]`
+	searchButtonSelector = `button:has-text("Поиск")`
+	resultTableSelector  = "table"
+)
+
+// Client looks products up in the NCT catalog through a pool of headless
+// browser pages.
+type Client struct {
+	baseURL string
+	timeout float64 // per browser operation, in milliseconds
+	pool    *pool
+}
+
+// NewClient creates a client using up to poolSize pages at once. The browser
+// is started with the first lookup.
+func NewClient(baseURL string, poolSize int, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: baseURL,
+		timeout: float64(timeout.Milliseconds()),
+		pool:    newPool(poolSize),
+	}
+}
+
+// Lookup searches the catalog for the GTIN. A GTIN the catalog does not
+// know yields no products and no error.
+func (c *Client) Lookup(ctx context.Context, gtin string) ([]model.Product, error) {
+	page, err := c.pool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := c.search(page, gtin)
+	// A page that failed may be left in any state, so it is not reused.
+	c.pool.release(page, err == nil)
+	return products, err
+}
+
+func (c *Client) search(page playwright.Page, gtin string) ([]model.Product, error) {
+	if _, err := page.Goto(c.baseURL, playwright.PageGotoOptions{Timeout: &c.timeout}); err != nil {
+		return nil, fmt.Errorf("error navigating to URL: %w", err)
+	}
+
+	if _, err := page.WaitForSelector(searchInputSelector, playwright.PageWaitForSelectorOptions{Timeout: &c.timeout}); err != nil {
+		return nil, fmt.Errorf("search input not found: %w", err)
+	}
+	if err := page.Fill(searchInputSelector, gtin); err != nil {
+		return nil, fmt.Errorf("error filling search input: %w", err)
+	}
+	if err := page.Click(searchButtonSelector); err != nil {
+		return nil, fmt.Errorf("error clicking search button: %w", err)
+	}
+
+	// The results are fetched by the page itself; once its requests have
+	// settled the table holds the matches or nothing at all.
+	if err := page.WaitForLoadState(playwright.PageWaitForLoadStateOptions{
+		State:   playwright.LoadStateNetworkidle,
+		Timeout: &c.timeout,
+	}); err != nil {
+		return nil, fmt.Errorf("error waiting for search results: %w", err)
+	}
+	if _, err := page.WaitForSelector(resultTableSelector, playwright.PageWaitForSelectorOptions{
+		State:   playwright.WaitForSelectorStateAttached,
+		Timeout: &c.timeout,
+	}); err != nil {
+		return nil, fmt.Errorf("result table not found: %w", err)
+	}
+
+	inner, err := page.InnerHTML(resultTableSelector)
+	if err != nil {
+		return nil, fmt.Errorf("error reading table: %w", err)
+	}
+	products, err := ParseTable(strings.NewReader("<table>" + inner + "</table>"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing table: %w", err)
+	}
+	return products, nil
+}
+
+// Close stops the browser.
+func (c *Client) Close() error {
+	return c.pool.close()
+}
@@ -0,0 +1,38 @@
+package nct
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Fake is a catalog backed by HTML fixtures: the page holding the result
+// table for a GTIN is read from <dir>/<gtin>.html. A GTIN without a
+// fixture is unknown.
+type Fake struct {
+	dir string
+}
+
+func NewFake(dir string) *Fake {
+	return &Fake{dir: dir}
+}
+
+func (f *Fake) Lookup(ctx context.Context, gtin string) ([]model.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Join(f.dir, filepath.Base(gtin)+".html"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseTable(file)
+}
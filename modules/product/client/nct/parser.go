@@ -2,102 +2,204 @@ package nct
 
 import (
 	"diploma/modules/product/model"
-	"fmt"
-	"regexp"
+	"errors"
+	"io"
+	"strconv"
 	"strings"
-	"time"
 
-	"github.com/playwright-community/playwright-go"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
-type NCTParser struct {
-	baseURL string
-}
+// ErrUnexpectedTable is returned when the result table lacks the columns
+// a product is built from, typically after a catalog layout change.
+var ErrUnexpectedTable = errors.New("nct: unexpected result table layout")
+
+// Result table columns
+const (
+	colName = iota
+	colGTIN
+	colBrand
+	colManufacturer
+	colCountry
+	colCategory
+	colSubcategory
+	colUnit
+	colCount
+)
 
-func NewNCTParser(baseURL string) *NCTParser {
-	return &NCTParser{
-		baseURL: baseURL,
-	}
+// headers maps normalized header titles to columns.
+var headers = map[string]int{
+	"наименование":         colName,
+	"наименование товара":  colName,
+	"название":             colName,
+	"штрихкод":             colGTIN,
+	"штрих-код":            colGTIN,
+	"gtin":                 colGTIN,
+	"торговая марка":       colBrand,
+	"бренд":                colBrand,
+	"марка":                colBrand,
+	"производитель":        colManufacturer,
+	"изготовитель":         colManufacturer,
+	"страна":               colCountry,
+	"страна производства":  colCountry,
+	"страна происхождения": colCountry,
+	"категория":            colCategory,
+	"подкатегория":         colSubcategory,
+	"единица измерения":    colUnit,
+	"ед. изм.":             colUnit,
+	"ед. измерения":        colUnit,
 }
 
-// ParseProductByGTIN searches for a product by its GTIN and returns found products.
-func (p *NCTParser) ParseProductByGTIN(gtin string) ([]model.Product, error) {
-	fmt.Println("Starting NCT parser...")
-	pw, err := playwright.Run()
-	if err != nil {
-		return nil, fmt.Errorf("error starting Playwright: %w", err)
-	}
-	defer pw.Stop()
-	fmt.Println("Playwright started successfully")
-	browser, err := pw.Firefox.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(true),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("error launching Firefox: %w", err)
-	}
-
-	fmt.Println("Browser launched successfully")
-	page, err := browser.NewPage()
+// ParseTable reads products from the NCT search result table. Columns are
+// found by their header, so their order does not matter and unknown ones
+// are ignored. Rows without a name and message rows such as "nothing
+// found" are skipped.
+func ParseTable(r io.Reader) ([]model.Product, error) {
+	doc, err := html.Parse(r)
 	if err != nil {
-		return nil, fmt.Errorf("error creating page: %w", err)
+		return nil, err
 	}
 
-	// Use the baseURL from the parser
-	if _, err := page.Goto(p.baseURL); err != nil {
-		return nil, fmt.Errorf("error navigating to URL: %w", err)
+	table := find(doc, atom.Table)
+	if table == nil {
+		return nil, nil
 	}
 
-	fmt.Println("Navigated to NCT successfully")
-	inputSelector := `input[placeholder="Введите наименование или номер штрихкода"]`
-	if _, err = page.WaitForSelector(inputSelector); err != nil {
-		return nil, fmt.Errorf("search input not found: %w", err)
-	}
+	var (
+		columns  [colCount]int
+		width    int // number of header cells, 0 until the header is found
+		products []model.Product
+	)
+	for _, tr := range findAll(table, atom.Tr) {
+		cells := children(tr, atom.Th, atom.Td)
+		if width == 0 {
+			if len(children(tr, atom.Th)) == 0 {
+				continue
+			}
+			columns = mapColumns(cells)
+			if columns[colName] == -1 {
+				return nil, ErrUnexpectedTable
+			}
+			width = len(cells)
+			continue
+		}
+		// a single cell spanning the table holds a message, not a product
+		if len(cells) == 1 && width > 1 {
+			continue
+		}
 
-	if err = page.Fill(inputSelector, gtin); err != nil {
-		return nil, fmt.Errorf("error filling search input: %w", err)
-	}
-	fmt.Println("Search input filled successfully")
+		cell := func(col int) string {
+			if columns[col] == -1 || columns[col] >= len(cells) {
+				return ""
+			}
+			return text(cells[columns[col]])
+		}
 
-	if err = page.Click("button:has-text(\"Поиск\")"); err != nil {
-		return nil, fmt.Errorf("error clicking search button: %w", err)
+		name := cell(colName)
+		if name == "" {
+			continue
+		}
+		products = append(products, model.Product{
+			Name:            name,
+			GTIN:            parseGTIN(cell(colGTIN)),
+			CategoryName:    cell(colCategory),
+			SubcategoryName: cell(colSubcategory),
+			ProductAttributes: model.ProductAttributes{
+				Brand:        cell(colBrand),
+				Manufacturer: cell(colManufacturer),
+				Country:      cell(colCountry),
+				Unit:         cell(colUnit),
+			},
+		})
 	}
 
-	// Waiting for the table to load completely.
-	time.Sleep(5 * time.Second)
+	return products, nil
+}
 
-	html, err := page.InnerHTML("table")
-	if err != nil {
-		return nil, fmt.Errorf("error reading table: %w", err)
+// mapColumns returns the cell index of every column, -1 if absent.
+func mapColumns(cells []*html.Node) [colCount]int {
+	var columns [colCount]int
+	for i := range columns {
+		columns[i] = -1
 	}
-	return ConvertHtmlToProduct(html), nil
+	for i, cell := range cells {
+		title := strings.TrimSuffix(strings.ToLower(text(cell)), ":")
+		if col, ok := headers[title]; ok && columns[col] == -1 {
+			columns[col] = i
+		}
+	}
+	return columns
 }
 
-// ConvertHtmlToProduct parses the HTML and returns a slice of Product.
-func ConvertHtmlToProduct(html string) []model.Product {
-	reTr := regexp.MustCompile(`(?s)<tr.*?>(.*?)</tr>`)
-	reTd := regexp.MustCompile(`(?s)<td.*?>(.*?)</td>`)
-	reTags := regexp.MustCompile(`<.*?>`)
+// parseGTIN returns the first barcode of the cell, 0 if there is none.
+func parseGTIN(s string) int64 {
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r < '0' || r > '9' }) {
+		if gtin, err := strconv.ParseInt(field, 10, 64); err == nil && len(field) >= 8 {
+			return gtin
+		}
+	}
+	return 0
+}
 
-	cleanText := func(html string) string {
-		text := reTags.ReplaceAllString(html, "")
-		return strings.TrimSpace(text)
+// text returns the node's text with whitespace collapsed.
+func text(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
 	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
 
-	var products []model.Product
+func find(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := find(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
 
-	trMatches := reTr.FindAllStringSubmatch(html, -1)
-	for _, tr := range trMatches {
-		tdMatches := reTd.FindAllStringSubmatch(tr[1], -1)
-		if len(tdMatches) < 5 {
+// findAll returns the descendants of n of the given kind, in document
+// order, without descending into nested tables.
+func findAll(n *html.Node, a atom.Atom) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom == atom.Table {
 			continue
 		}
-		p := model.Product{
-			Name:        cleanText(tdMatches[0][1]),
-			CategoryName:    cleanText(tdMatches[3][1]),
-			SubcategoryName: cleanText(tdMatches[4][1]),
+		if c.DataAtom == a {
+			nodes = append(nodes, c)
+			continue
 		}
-		products = append(products, p)
+		nodes = append(nodes, findAll(c, a)...)
 	}
+	return nodes
+}
 
-	return products
+func children(n *html.Node, atoms ...atom.Atom) []*html.Node {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		for _, a := range atoms {
+			if c.DataAtom == a {
+				nodes = append(nodes, c)
+				break
+			}
+		}
+	}
+	return nodes
 }
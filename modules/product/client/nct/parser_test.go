@@ -0,0 +1,96 @@
+package nct
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseFixture(t *testing.T, name string) []model.Product {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	require.NoError(t, err)
+	defer file.Close()
+
+	products, err := ParseTable(file)
+	require.NoError(t, err)
+	return products
+}
+
+func TestParseTable(t *testing.T) {
+	products := parseFixture(t, "4870001234560.html")
+	require.Len(t, products, 1)
+
+	assert.Equal(t, model.Product{
+		Name:            "Молоко пастеризованное 3,2% 1 л",
+		GTIN:            4870001234560,
+		CategoryName:    "Молочные продукты",
+		SubcategoryName: "Молоко",
+		ProductAttributes: model.ProductAttributes{
+			Brand:        "Моё",
+			Manufacturer: "ТОО «Молочный завод & К»",
+			Country:      "Казахстан",
+			Unit:         "л",
+		},
+	}, products[0])
+}
+
+func TestParseTable_MultipleMatchesInOtherColumnOrder(t *testing.T) {
+	products := parseFixture(t, "4870001234577.html")
+	require.Len(t, products, 2)
+
+	assert.Equal(t, "Кефир 2,5% 900 г", products[0].Name)
+	assert.Equal(t, int64(4870001234577), products[0].GTIN)
+	assert.Equal(t, "Lactel", products[0].Brand)
+	assert.Equal(t, "ТОО «Foodmaster»", products[0].Manufacturer)
+	assert.Equal(t, "шт", products[0].Unit)
+	assert.Equal(t, "Кефир", products[0].SubcategoryName)
+
+	assert.Equal(t, "Кефир 2,5% 450 г", products[1].Name)
+	assert.Equal(t, int64(4870001234584), products[1].GTIN)
+}
+
+func TestParseTable_NothingFound(t *testing.T) {
+	assert.Empty(t, parseFixture(t, "4870000000005.html"))
+
+	products, err := ParseTable(strings.NewReader("<p>Загрузка…</p>"))
+	require.NoError(t, err)
+	assert.Empty(t, products)
+}
+
+func TestParseTable_UnexpectedLayout(t *testing.T) {
+	_, err := ParseTable(strings.NewReader(
+		"<table><tr><th>Код</th><th>Цена</th></tr><tr><td>1</td><td>2</td></tr></table>"))
+	assert.ErrorIs(t, err, ErrUnexpectedTable)
+}
+
+func TestParseTable_MissingCells(t *testing.T) {
+	products, err := ParseTable(strings.NewReader(
+		"<table><tr><th>Наименование</th><th>Штрихкод</th><th>Страна</th></tr>" +
+			"<tr><td>Хлеб</td><td>нет данных</td></tr></table>"))
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+
+	assert.Equal(t, "Хлеб", products[0].Name)
+	assert.Zero(t, products[0].GTIN)
+	assert.Empty(t, products[0].Country)
+}
+
+func TestFake(t *testing.T) {
+	fake := NewFake("testdata")
+
+	products, err := fake.Lookup(context.Background(), "4870001234560")
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, int64(4870001234560), products[0].GTIN)
+
+	products, err = fake.Lookup(context.Background(), "4870009999997")
+	require.NoError(t, err)
+	assert.Empty(t, products)
+}
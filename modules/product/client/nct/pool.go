@@ -0,0 +1,125 @@
+package nct
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+var errPoolClosed = errors.New("nct: browser pool is closed")
+
+// pool shares one Firefox instance between lookups and keeps idle pages
+// for reuse. At most size pages are in use at a time.
+type pool struct {
+	slots chan struct{}
+	idle  chan playwright.Page
+
+	mu      sync.Mutex
+	pw      *playwright.Playwright
+	browser playwright.Browser
+	closed  bool
+}
+
+func newPool(size int) *pool {
+	if size < 1 {
+		size = 1
+	}
+	return &pool{
+		slots: make(chan struct{}, size),
+		idle:  make(chan playwright.Page, size),
+	}
+}
+
+// acquire waits for a free slot and returns an idle page or a new one.
+func (p *pool) acquire(ctx context.Context) (playwright.Page, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	select {
+	case page := <-p.idle:
+		if !page.IsClosed() {
+			return page, nil
+		}
+	default:
+	}
+
+	page, err := p.newPage()
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	return page, nil
+}
+
+// release returns the page to the pool, or closes it if it is not to be
+// reused.
+func (p *pool) release(page playwright.Page, reuse bool) {
+	if reuse {
+		p.idle <- page
+	} else {
+		_ = page.Close()
+	}
+	<-p.slots
+}
+
+// newPage starts the browser if it is not running and opens a page.
+func (p *pool) newPage() (playwright.Page, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, errPoolClosed
+	}
+
+	if p.browser == nil || !p.browser.IsConnected() {
+		if err := p.start(); err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := p.browser.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("error creating page: %w", err)
+	}
+	return page, nil
+}
+
+func (p *pool) start() error {
+	if p.pw == nil {
+		pw, err := playwright.Run()
+		if err != nil {
+			return fmt.Errorf("error starting Playwright: %w", err)
+		}
+		p.pw = pw
+	}
+
+	browser, err := p.pw.Firefox.Launch(playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("error launching Firefox: %w", err)
+	}
+	p.browser = browser
+	return nil
+}
+
+func (p *pool) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var errs []error
+	if p.browser != nil {
+		errs = append(errs, p.browser.Close())
+	}
+	if p.pw != nil {
+		errs = append(errs, p.pw.Stop())
+	}
+	return errors.Join(errs...)
+}
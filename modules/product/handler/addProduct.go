@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"diploma/modules/auth/jwt"
@@ -13,7 +14,7 @@ import (
 
 // AddProduct godoc
 // @Summary      Add a new product
-// @Description  Create a new product with the provided details
+// @Description  Create a new product with the provided details. The GTIN may be given as EAN-8, UPC-A, EAN-13 or GTIN-14 and must have a valid check digit.
 // @Tags         product
 // @Accept       json
 // @Produce      json
@@ -50,6 +51,10 @@ func (h *CatalogHandler) AddProduct(c *gin.Context) {
 		SubcategoryID: req.SubcategoryID,
 	})
 
+	if errors.Is(err, model.ErrInvalidGTIN) {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
 		return
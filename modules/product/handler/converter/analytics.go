@@ -0,0 +1,42 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToAPIPriceAnalyticsFromService(analytics *model.PriceAnalytics) *modelApi.PriceAnalyticsResponse {
+	history := make([]modelApi.PriceHistoryItem, 0, len(analytics.History))
+	for _, p := range analytics.History {
+		history = append(history, modelApi.PriceHistoryItem{
+			Date:     p.Date,
+			Price:    p.Price,
+			Supplier: p.SupplierName,
+		})
+	}
+
+	suppliers := make([]modelApi.SupplierAnalytics, 0, len(analytics.Suppliers))
+	for _, s := range analytics.Suppliers {
+		suppliers = append(suppliers, modelApi.SupplierAnalytics{
+			SupplierID:   s.SupplierID,
+			SupplierName: s.SupplierName,
+			CurrentPrice: s.CurrentPrice,
+			Active:       s.Active,
+			MinPrice:     s.MinPrice,
+			MaxPrice:     s.MaxPrice,
+			AvgPrice:     s.AvgPrice,
+			PriceChanges: s.PriceChanges,
+		})
+	}
+
+	return &modelApi.PriceAnalyticsResponse{
+		ProductID:    analytics.ProductID,
+		ProductName:  analytics.ProductName,
+		CurrentPrice: analytics.CurrentPrice,
+		MinPrice:     analytics.MinPrice,
+		MaxPrice:     analytics.MaxPrice,
+		AvgPrice:     analytics.AvgPrice,
+		PriceHistory: history,
+		Suppliers:    suppliers,
+	}
+}
@@ -0,0 +1,20 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToServiceProductAttributesUpdateFromAPI(req *modelApi.UpdateProductAttributesRequest) *model.ProductAttributesUpdate {
+	return &model.ProductAttributesUpdate{
+		Description:       req.Description,
+		Brand:             req.Brand,
+		Manufacturer:      req.Manufacturer,
+		Weight:            req.Weight,
+		Country:           req.Country,
+		Unit:              req.Unit,
+		ShelfLifeDays:     req.ShelfLifeDays,
+		StorageConditions: req.StorageConditions,
+		Images:            req.Images,
+	}
+}
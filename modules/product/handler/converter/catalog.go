@@ -3,6 +3,7 @@ package converter
 import (
 	modelApi "diploma/modules/product/handler/model"
 	"diploma/modules/product/model"
+	"strconv"
 )
 
 func ToServieProductQueryFromApi(input modelApi.ProductInput) *model.ProductQuery {
@@ -41,8 +42,10 @@ func ToAPIProductFromService(product *model.Product) *modelApi.Product {
 		ImageUrl:              product.ImageUrl,
 		Category:              product.CategoryName,
 		Subcategory:           product.SubcategoryName,
-		GTIN:                  string(product.GTIN),
+		GTIN:                  strconv.FormatInt(product.GTIN, 10),
 		LowestProductSupplier: ToAPIProductSupplierFromService(product.LowestProductSupplier),
+		ProductAttributes:     modelApi.ProductAttributes(product.ProductAttributes),
+		Images:                product.Images,
 	}
 }
 
@@ -51,6 +54,7 @@ func ToAPIProductSupplierFromService(ps model.ProductSupplier) modelApi.ProductS
 	return modelApi.ProductSupplier{
 		Price:      ps.Price,
 		SellAmount: ps.SellAmount,
+		Available:  ps.Available,
 		Supplier:   ToAPISupplierFromService(ps.Supplier),
 	}
 }
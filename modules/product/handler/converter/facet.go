@@ -0,0 +1,39 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToAPIProductFacetsFromService(facets *model.ProductFacets) *modelApi.ProductFacets {
+	if facets == nil {
+		return nil
+	}
+
+	res := &modelApi.ProductFacets{
+		Categories:   make([]modelApi.CategoryFacet, 0, len(facets.Categories)),
+		Suppliers:    make([]modelApi.SupplierFacet, 0, len(facets.Suppliers)),
+		PriceBuckets: make([]modelApi.PriceBucketFacet, 0, len(facets.PriceBuckets)),
+	}
+
+	for _, c := range facets.Categories {
+		subcategories := make([]modelApi.SubcategoryFacet, 0, len(c.Subcategories))
+		for _, sc := range c.Subcategories {
+			subcategories = append(subcategories, modelApi.SubcategoryFacet(sc))
+		}
+		res.Categories = append(res.Categories, modelApi.CategoryFacet{
+			ID:            c.ID,
+			Name:          c.Name,
+			Count:         c.Count,
+			Subcategories: subcategories,
+		})
+	}
+	for _, s := range facets.Suppliers {
+		res.Suppliers = append(res.Suppliers, modelApi.SupplierFacet(s))
+	}
+	for _, b := range facets.PriceBuckets {
+		res.PriceBuckets = append(res.PriceBuckets, modelApi.PriceBucketFacet(b))
+	}
+
+	return res
+}
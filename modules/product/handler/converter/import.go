@@ -0,0 +1,23 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToAPIImportFromService(imp *model.Import) *modelApi.ImportResponse {
+	return &modelApi.ImportResponse{
+		ID:         imp.ID,
+		FileName:   imp.FileName,
+		CreatedAt:  imp.CreatedAt,
+		FinishedAt: imp.FinishedAt,
+		Done:       imp.Progress.Done(),
+		Progress: modelApi.ImportProgressResponse{
+			Total:    imp.Progress.Total,
+			Pending:  imp.Progress.Pending,
+			Lookup:   imp.Progress.Lookup,
+			Imported: imp.Progress.Imported,
+			Failed:   imp.Progress.Failed,
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToAPIMarketAnalyticsFromService(analytics *model.MarketAnalytics) *modelApi.MarketAnalyticsResponse {
+	trend := make([]modelApi.MarketTrendItem, 0, len(analytics.Trend))
+	for _, p := range analytics.Trend {
+		trend = append(trend, modelApi.MarketTrendItem{
+			Date:        p.Date,
+			MarketIndex: p.Index,
+			Volume:      p.Volume,
+		})
+	}
+
+	categories := make([]modelApi.CategoryAnalytics, 0, len(analytics.Categories))
+	for _, c := range analytics.Categories {
+		categories = append(categories, modelApi.CategoryAnalytics{
+			CategoryName:    c.CategoryName,
+			ProductCount:    c.ProductCount,
+			AvgPriceChange:  c.PriceChange,
+			Volatility:      c.Volatility,
+			TopProduct:      c.TopProduct,
+			TopProductPrice: c.TopProductPrice,
+		})
+	}
+
+	return &modelApi.MarketAnalyticsResponse{
+		Period:           analytics.Days,
+		Category:         analytics.Category,
+		TotalProducts:    analytics.TotalProducts,
+		AvgPriceChange:   analytics.PriceChange,
+		MarketVolatility: analytics.Volatility,
+		TopGainers:       toAPIProductTrends(analytics.TopGainers),
+		TopLosers:        toAPIProductTrends(analytics.TopLosers),
+		TrendData:        trend,
+		Categories:       categories,
+	}
+}
+
+func toAPIProductTrends(trends []model.ProductTrend) []modelApi.ProductTrend {
+	res := make([]modelApi.ProductTrend, 0, len(trends))
+	for _, t := range trends {
+		res = append(res, modelApi.ProductTrend{
+			ProductID:    t.ProductID,
+			ProductName:  t.ProductName,
+			CurrentPrice: t.CurrentPrice,
+			PriceChange:  t.PriceChange,
+			Volume:       t.Volume,
+		})
+	}
+	return res
+}
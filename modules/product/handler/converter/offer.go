@@ -0,0 +1,27 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToServiceOfferUpdateFromAPI(req *modelApi.UpdateOfferRequest) *model.OfferUpdate {
+	return &model.OfferUpdate{
+		Price:      req.Price,
+		SellAmount: req.SellAmount,
+		Stock:      req.Stock,
+		Available:  req.Available,
+		Reason:     req.Reason,
+	}
+}
+
+func ToAPIOfferFromService(offer *model.Offer) *modelApi.OfferResponse {
+	return &modelApi.OfferResponse{
+		ProductID:  offer.ProductID,
+		SupplierID: offer.SupplierID,
+		Price:      offer.Price,
+		SellAmount: offer.SellAmount,
+		Stock:      offer.Stock,
+		Available:  offer.Available,
+	}
+}
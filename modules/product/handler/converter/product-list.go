@@ -7,10 +7,16 @@ import (
 
 func ToServiceProductListQueryFromAPI(input *modelApi.ProductListInput) *model.ProductListQuery {
 	return &model.ProductListQuery{
-		Limit:         input.Limit,
-		Offset:        input.Offset,
-		CategoryID:    input.CategoryID,
-		SubcategoryID: input.SubcategoryID,
+		Limit:             input.Limit,
+		Offset:            input.Offset,
+		CategoryID:        input.CategoryID,
+		SubcategoryID:     input.SubcategoryID,
+		Sort:              input.Sort,
+		MinPrice:          input.MinPrice,
+		MaxPrice:          input.MaxPrice,
+		SupplierID:        input.SupplierID,
+		FreeDeliveryBelow: input.FreeDeliveryBelow,
+		MaxMinOrder:       input.MaxMinOrder,
 	}
 }
 
@@ -18,6 +24,7 @@ func ToProductListResponeFromService(producList *model.ProductList) *modelApi.Pr
 	return &modelApi.ProductListResponse{
 		ProductList: ToProductsFromService(producList.Products),
 		Total:       producList.Total,
+		Facets:      ToAPIProductFacetsFromService(producList.Facets),
 	}
 }
 
@@ -0,0 +1,30 @@
+package converter
+
+import (
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+)
+
+func ToServiceProductSearchQueryFromAPI(input *modelApi.ProductSearchInput) *model.ProductSearchQuery {
+	return &model.ProductSearchQuery{
+		Query:  input.Query,
+		Limit:  input.Limit,
+		Offset: input.Offset,
+	}
+}
+
+func ToProductSearchResponseFromService(list *model.ProductSearchList) *modelApi.ProductSearchResponse {
+	res := make([]modelApi.ProductSearchItem, 0, len(list.Products))
+	for _, e := range list.Products {
+		res = append(res, modelApi.ProductSearchItem{
+			Product:   *ToAPIProductFromService(&e.Product),
+			Highlight: e.Highlight,
+			Rank:      e.Rank,
+		})
+	}
+
+	return &modelApi.ProductSearchResponse{
+		ProductList: res,
+		Total:       list.Total,
+	}
+}
@@ -1,18 +1,20 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"diploma/modules/product/handler/converter"
 	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetProduct godoc
 // @Summary      Get product by ID
-// @Description  Retrieve product information by its ID
+// @Description  Retrieve product information by its ID, with its attributes and image gallery
 // @Tags         product
 // @Accept       json
 // @Produce      json
@@ -34,6 +36,10 @@ func (h *CatalogHandler) GetProduct(c *gin.Context) {
 
 	input := modelApi.ProductInput{ID: productIdInt}
 	product, err := h.service.Product(c.Request.Context(), converter.ToServieProductQueryFromApi(input))
+	if errors.Is(err, model.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
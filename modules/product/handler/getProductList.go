@@ -1,18 +1,20 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"diploma/modules/product/handler/converter"
 	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetProductList godoc
 // @Summary      Get product list
-// @Description  Retrieve a list of products with pagination support and optional category filtering
+// @Description  Retrieve a list of products with pagination support, sorting and optional category and offer filtering. Offer filters match products that a single supplier offers satisfying all of them. The response carries product counts by category, supplier and price bucket under the same filters.
 // @Tags         product
 // @Accept       json
 // @Produce      json
@@ -20,6 +22,12 @@ import (
 // @Param        offset        query     int     false "Offset for pagination"
 // @Param        category_id   query     int     false "Filter by category ID"
 // @Param        subcategory_id query    int     false "Filter by subcategory ID"
+// @Param        sort          query     string  false "Sort order" Enums(price, name, newest)
+// @Param        min_price     query     int     false "Offered at this price or higher"
+// @Param        max_price     query     int     false "Offered at this price or lower"
+// @Param        supplier_id   query     int     false "Offered by this supplier"
+// @Param        free_delivery_below query int  false "Offered by a supplier with free delivery from this amount or less"
+// @Param        max_min_order query     int     false "Offered by a supplier whose minimum order is this amount or less"
 // @Success      200  {object}  modelApi.ProductListResponse
 // @Failure      400  {object}  modelApi.ErrorResponse
 // @Router       /api/product/list [get]
@@ -69,13 +77,62 @@ func (h *CatalogHandler) GetProductList(c *gin.Context) {
 		input.SubcategoryID = &subcategoryID
 	}
 
+	if !bindProductListFilters(c, &input) {
+		return
+	}
+
 	// Call the service layer to get the product list
 	productList, err := h.service.ProductList(c.Request.Context(), converter.ToServiceProductListQueryFromAPI(&input))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeProductListError(c, err)
 		return
 	}
 
 	// Convert service response to API response and return it
 	c.JSON(http.StatusOK, converter.ToProductListResponeFromService(productList))
 }
+
+// bindProductListFilters reads the sort order and offer filters shared by
+// the product list endpoints. It writes a 400 response and returns false on
+// a malformed parameter.
+func bindProductListFilters(c *gin.Context, input *modelApi.ProductListInput) bool {
+	input.Sort = c.Query("sort")
+
+	ints := map[string]**int{
+		"min_price":           &input.MinPrice,
+		"max_price":           &input.MaxPrice,
+		"free_delivery_below": &input.FreeDeliveryBelow,
+		"max_min_order":       &input.MaxMinOrder,
+	}
+	for name, dst := range ints {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid " + name + " parameter"})
+			return false
+		}
+		*dst = &v
+	}
+
+	if raw := c.Query("supplier_id"); raw != "" {
+		supplierID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid supplier_id parameter"})
+			return false
+		}
+		input.SupplierID = &supplierID
+	}
+
+	return true
+}
+
+func writeProductListError(c *gin.Context, err error) {
+	if errors.Is(err, model.ErrInvalidListQuery) {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid sort or filter parameters"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+}
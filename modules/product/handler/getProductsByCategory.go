@@ -12,7 +12,7 @@ import (
 
 // GetProductsByCategory godoc
 // @Summary      Get products by category
-// @Description  Retrieve all products in a specific category with optional subcategory filtering
+// @Description  Retrieve all products in a specific category with optional subcategory filtering, together with facet counts
 // @Tags         product
 // @Accept       json
 // @Produce      json
@@ -20,6 +20,12 @@ import (
 // @Param        subcategory_id query    int     false "Filter by subcategory ID (optional)"
 // @Param        limit         query     int     false "Limit number of products (default 20)"
 // @Param        offset        query     int     false "Offset for pagination (default 0)"
+// @Param        sort          query     string  false "Sort order" Enums(price, name, newest)
+// @Param        min_price     query     int     false "Offered at this price or higher"
+// @Param        max_price     query     int     false "Offered at this price or lower"
+// @Param        supplier_id   query     int     false "Offered by this supplier"
+// @Param        free_delivery_below query int  false "Offered by a supplier with free delivery from this amount or less"
+// @Param        max_min_order query     int     false "Offered by a supplier whose minimum order is this amount or less"
 // @Success      200  {object}  modelApi.ProductListResponse
 // @Failure      400  {object}  modelApi.ErrorResponse
 // @Failure      404  {object}  modelApi.ErrorResponse
@@ -68,10 +74,14 @@ func (h *CatalogHandler) GetProductsByCategory(c *gin.Context) {
 		input.SubcategoryID = &subcategoryID
 	}
 
+	if !bindProductListFilters(c, &input) {
+		return
+	}
+
 	// Call service
 	productList, err := h.service.ProductList(c.Request.Context(), converter.ToServiceProductListQueryFromAPI(&input))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		writeProductListError(c, err)
 		return
 	}
 
@@ -86,13 +96,19 @@ func (h *CatalogHandler) GetProductsByCategory(c *gin.Context) {
 
 // GetProductsBySubcategory godoc
 // @Summary      Get products by subcategory
-// @Description  Retrieve all products in a specific subcategory
+// @Description  Retrieve all products in a specific subcategory, together with facet counts
 // @Tags         product
 // @Accept       json
 // @Produce      json
 // @Param        subcategory_id path     int     true  "Subcategory ID"
 // @Param        limit          query    int     false "Limit number of products (default 20)"
 // @Param        offset         query    int     false "Offset for pagination (default 0)"
+// @Param        sort          query     string  false "Sort order" Enums(price, name, newest)
+// @Param        min_price     query     int     false "Offered at this price or higher"
+// @Param        max_price     query     int     false "Offered at this price or lower"
+// @Param        supplier_id   query     int     false "Offered by this supplier"
+// @Param        free_delivery_below query int  false "Offered by a supplier with free delivery from this amount or less"
+// @Param        max_min_order query     int     false "Offered by a supplier whose minimum order is this amount or less"
 // @Success      200  {object}  modelApi.ProductListResponse
 // @Failure      400  {object}  modelApi.ErrorResponse
 // @Failure      404  {object}  modelApi.ErrorResponse
@@ -130,10 +146,14 @@ func (h *CatalogHandler) GetProductsBySubcategory(c *gin.Context) {
 		SubcategoryID: &subcategoryID,
 	}
 
+	if !bindProductListFilters(c, &input) {
+		return
+	}
+
 	// Call service
 	productList, err := h.service.ProductList(c.Request.Context(), converter.ToServiceProductListQueryFromAPI(&input))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		writeProductListError(c, err)
 		return
 	}
 
@@ -8,8 +8,17 @@ import (
 type IProductService interface {
 	Product(ctx context.Context, query *productModel.ProductQuery) (*productModel.DetailedProduct, error)
 	ProductList(ctx context.Context, query *productModel.ProductListQuery) (*productModel.ProductList, error)
+	SearchProducts(ctx context.Context, query *productModel.ProductSearchQuery) (*productModel.ProductSearchList, error)
 	AddProduct(ctx context.Context, req *productModel.AddProductSupplier) error
 	GetProductListBySupplier(ctx context.Context, supplierID int64, limit, offset int) (*productModel.ProductList, error)
+	UpdateOffer(ctx context.Context, productID, supplierID int64, update *productModel.OfferUpdate) (*productModel.Offer, error)
+	DeactivateOffer(ctx context.Context, productID, supplierID int64) (*productModel.Offer, error)
+	PriceAnalytics(ctx context.Context, productID int64, days int) (*productModel.PriceAnalytics, error)
+	MarketAnalytics(ctx context.Context, days int, category string) (*productModel.MarketAnalytics, error)
+	UpdateProductAttributes(ctx context.Context, productID, userID int64, role int, update *productModel.ProductAttributesUpdate) (*productModel.DetailedProduct, error)
+	CreateImport(ctx context.Context, supplierID int64, fileName string, rows []productModel.ImportRow) (*productModel.Import, error)
+	Import(ctx context.Context, supplierID, id int64) (*productModel.Import, error)
+	ImportErrors(ctx context.Context, supplierID, id int64) ([]productModel.ImportRow, error)
 }
 
 type CatalogHandler struct {
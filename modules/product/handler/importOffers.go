@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"diploma/modules/product/handler/converter"
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/handler/upload"
+	"diploma/modules/product/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize bounds uploaded import files.
+const maxImportFileSize = 10 << 20
+
+// ImportOffers godoc
+// @Summary      Import offers from a file
+// @Description  Supplier only. Uploads a CSV or XLSX file with columns gtin, price, min qty, stock and category (the first two required). Rows are validated on upload and imported in the background: known GTINs update or create the supplier's offer, unknown ones are looked up in NCT first. Poll the returned import for progress.
+// @Tags         product
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        file formData  file  true "CSV or XLSX file"
+// @Success      202  {object}  modelApi.ImportResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/import [post]
+func (h *CatalogHandler) ImportOffers(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "file is required"})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "file is larger than 10 MB"})
+		return
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	if format != upload.FormatCSV && format != upload.FormatXLSX {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "file must be .csv or .xlsx"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+	defer file.Close()
+
+	rows, err := upload.Parse(format, file)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	imp, err := h.service.CreateImport(c.Request.Context(), claims.UserID, fileHeader.Filename, rows)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, converter.ToAPIImportFromService(imp))
+}
+
+// GetImport godoc
+// @Summary      Get import progress
+// @Description  Supplier only. Returns the supplier's import with row counts by status.
+// @Tags         product
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true "Import ID"
+// @Success      200  {object}  modelApi.ImportResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      404  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/import/{id} [get]
+func (h *CatalogHandler) GetImport(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := importIDParam(c)
+	if !ok {
+		return
+	}
+
+	imp, err := h.service.Import(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToAPIImportFromService(imp))
+}
+
+// GetImportErrors godoc
+// @Summary      Download import error report
+// @Description  Supplier only. Returns the failed rows of the import as CSV with the file row number, GTIN and reason.
+// @Tags         product
+// @Produce      text/csv
+// @Security ApiKeyAuth
+// @Param        id   path      int  true "Import ID"
+// @Success      200  {file}    file "Failed rows"
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      404  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/import/{id}/errors [get]
+func (h *CatalogHandler) GetImportErrors(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := importIDParam(c)
+	if !ok {
+		return
+	}
+
+	rows, err := h.service.ImportErrors(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		writeImportError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="import-%d-errors.csv"`, id))
+	c.Status(http.StatusOK)
+	if err := upload.WriteErrorReport(c.Writer, rows); err != nil {
+		c.Abort()
+	}
+}
+
+func writeImportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidImportFile):
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+	case errors.Is(err, model.ErrNoRows):
+		c.JSON(http.StatusNotFound, modelApi.ErrorResponse{Err: "import not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+	}
+}
+
+func importIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid import ID"})
+		return 0, false
+	}
+	return id, true
+}
@@ -28,6 +28,7 @@ type SupplierAnalytics struct {
 	SupplierID   int64   `json:"supplier_id"`
 	SupplierName string  `json:"supplier_name"`
 	CurrentPrice int     `json:"current_price"`
+	Active       bool    `json:"active"`
 	MinPrice     int     `json:"min_price"`
 	MaxPrice     int     `json:"max_price"`
 	AvgPrice     float64 `json:"avg_price"`
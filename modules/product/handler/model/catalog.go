@@ -1,13 +1,20 @@
 package model
 
 type ProductListInput struct {
-	Limit         int  `json:"limit"`
-	Offset        int  `json:"offset"`
-	CategoryID    *int `json:"category_id,omitempty"`
-	SubcategoryID *int `json:"subcategory_id,omitempty"`
+	Limit             int    `json:"limit"`
+	Offset            int    `json:"offset"`
+	CategoryID        *int   `json:"category_id,omitempty"`
+	SubcategoryID     *int   `json:"subcategory_id,omitempty"`
+	Sort              string `json:"sort,omitempty"`
+	MinPrice          *int   `json:"min_price,omitempty"`
+	MaxPrice          *int   `json:"max_price,omitempty"`
+	SupplierID        *int64 `json:"supplier_id,omitempty"`
+	FreeDeliveryBelow *int   `json:"free_delivery_below,omitempty"`
+	MaxMinOrder       *int   `json:"max_min_order,omitempty"`
 }
 
 type ProductListResponse struct {
-	ProductList []Product `json:"product_list"`
-	Total       int       `json:"total"`
+	ProductList []Product      `json:"product_list"`
+	Total       int            `json:"total"`
+	Facets      *ProductFacets `json:"facets,omitempty"`
 }
@@ -0,0 +1,34 @@
+package model
+
+type ProductFacets struct {
+	Categories   []CategoryFacet    `json:"categories"`
+	Suppliers    []SupplierFacet    `json:"suppliers"`
+	PriceBuckets []PriceBucketFacet `json:"price_buckets"`
+}
+
+type CategoryFacet struct {
+	ID            int                `json:"id"`
+	Name          string             `json:"name"`
+	Count         int                `json:"count"`
+	Subcategories []SubcategoryFacet `json:"subcategories"`
+}
+
+type SubcategoryFacet struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type SupplierFacet struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// PriceBucketFacet counts products whose lowest price is at least Min and
+// below Max; Max is omitted for the last bucket.
+type PriceBucketFacet struct {
+	Min   int  `json:"min"`
+	Max   *int `json:"max,omitempty"`
+	Count int  `json:"count"`
+}
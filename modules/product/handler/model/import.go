@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type ImportResponse struct {
+	ID         int64                  `json:"id"`
+	FileName   string                 `json:"file_name"`
+	CreatedAt  time.Time              `json:"created_at"`
+	FinishedAt *time.Time             `json:"finished_at"`
+	Done       bool                   `json:"done"`
+	Progress   ImportProgressResponse `json:"progress"`
+}
+
+type ImportProgressResponse struct {
+	Total    int `json:"total"`
+	Pending  int `json:"pending"`
+	Lookup   int `json:"lookup"` // waiting for NCT lookup
+	Imported int `json:"imported"`
+	Failed   int `json:"failed"`
+}
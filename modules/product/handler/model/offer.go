@@ -0,0 +1,18 @@
+package model
+
+type UpdateOfferRequest struct {
+	Price      *int   `json:"price,omitempty"`
+	SellAmount *int   `json:"sell_amount,omitempty"`
+	Stock      *int   `json:"stock,omitempty"`
+	Available  *bool  `json:"available,omitempty"`
+	Reason     string `json:"reason,omitempty"` // recorded with a price change
+}
+
+type OfferResponse struct {
+	ProductID  int64 `json:"product_id"`
+	SupplierID int64 `json:"supplier_id"`
+	Price      int   `json:"price"`
+	SellAmount int   `json:"sell_amount"`
+	Stock      *int  `json:"stock"`
+	Available  bool  `json:"available"`
+}
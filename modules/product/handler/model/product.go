@@ -16,11 +16,41 @@ type Product struct {
 	Subcategory           string          `json:"subcategory"`
 	GTIN                  string          `json:"gtin"`
 	LowestProductSupplier ProductSupplier `json:"lowest_product_supplier"`
+	ProductAttributes
+	Images []string `json:"images,omitempty"` // gallery, returned for a single product
+}
+
+// ProductAttributes are returned when known.
+type ProductAttributes struct {
+	Description       string `json:"description,omitempty"`
+	Brand             string `json:"brand,omitempty"`
+	Manufacturer      string `json:"manufacturer,omitempty"`
+	Weight            string `json:"weight,omitempty"`
+	Country           string `json:"country,omitempty"`
+	Unit              string `json:"unit,omitempty"`
+	ShelfLifeDays     *int   `json:"shelf_life_days,omitempty"`
+	StorageConditions string `json:"storage_conditions,omitempty"`
+}
+
+// UpdateProductAttributesRequest changes the fields that are set. An empty
+// string or a zero shelf life clears an attribute; images replace the
+// gallery, the first one becoming the main image.
+type UpdateProductAttributesRequest struct {
+	Description       *string   `json:"description,omitempty"`
+	Brand             *string   `json:"brand,omitempty"`
+	Manufacturer      *string   `json:"manufacturer,omitempty"`
+	Weight            *string   `json:"weight,omitempty"`
+	Country           *string   `json:"country,omitempty"`
+	Unit              *string   `json:"unit,omitempty"`
+	ShelfLifeDays     *int      `json:"shelf_life_days,omitempty"`
+	StorageConditions *string   `json:"storage_conditions,omitempty"`
+	Images            *[]string `json:"images,omitempty"`
 }
 
 type ProductSupplier struct {
 	Price      *int     `json:"price"`
 	SellAmount *int     `json:"sell_amount"`
+	Available  *bool    `json:"available,omitempty"`
 	Supplier   Supplier `json:"supplier"`
 }
 
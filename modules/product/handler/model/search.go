@@ -0,0 +1,18 @@
+package model
+
+type ProductSearchInput struct {
+	Query  string `json:"q"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+type ProductSearchItem struct {
+	Product
+	Highlight string  `json:"highlight"`
+	Rank      float64 `json:"rank"`
+}
+
+type ProductSearchResponse struct {
+	ProductList []ProductSearchItem `json:"product_list"`
+	Total       int                 `json:"total"`
+}
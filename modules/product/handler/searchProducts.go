@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/product/handler/converter"
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchProducts godoc
+// @Summary      Search products
+// @Description  Full-text search over product names (Russian and Kazakh) tolerant to typos. A query of 8-14 digits also matches the product GTIN exactly; such a match is ranked first. Matched words are wrapped in <b></b> in the highlight field.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Param        q       query     string  true  "Search query"
+// @Param        limit   query     int     false "Limit number of products"
+// @Param        offset  query     int     false "Offset for pagination"
+// @Success      200  {object}  modelApi.ProductSearchResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/search [get]
+func (h *CatalogHandler) SearchProducts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 0 {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid limit parameter"})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid offset parameter"})
+		return
+	}
+
+	input := modelApi.ProductSearchInput{
+		Query:  c.Query("q"),
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	result, err := h.service.SearchProducts(c.Request.Context(), converter.ToServiceProductSearchQueryFromAPI(&input))
+	if err != nil {
+		if errors.Is(err, model.ErrEmptySearchQuery) {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "q parameter is required"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToProductSearchResponseFromService(result))
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diploma/modules/auth/jwt"
+	"diploma/modules/product/handler/converter"
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+	contextkeys "diploma/pkg/context-keys"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateOffer godoc
+// @Summary      Update own product offer
+// @Description  Supplier only. Changes price, minimum sell amount, stock and/or availability of the supplier's offer of the product. A price change is recorded in the price history with the given reason.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id    path      int                          true "Product ID"
+// @Param        input body      modelApi.UpdateOfferRequest  true "Fields to change"
+// @Success      200  {object}  modelApi.OfferResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      404  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/{id}/offer [patch]
+func (h *CatalogHandler) UpdateOffer(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req modelApi.UpdateOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid request body"})
+		return
+	}
+
+	offer, err := h.service.UpdateOffer(c.Request.Context(), productID, claims.UserID, converter.ToServiceOfferUpdateFromAPI(&req))
+	if err != nil {
+		writeOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToAPIOfferFromService(offer))
+}
+
+// DeactivateOffer godoc
+// @Summary      Deactivate own product offer
+// @Description  Supplier only. Withdraws the supplier's offer of the product; it can be made available again with PATCH.
+// @Tags         product
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id   path      int  true "Product ID"
+// @Success      200  {object}  modelApi.OfferResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      404  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/{id}/offer [delete]
+func (h *CatalogHandler) DeactivateOffer(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	offer, err := h.service.DeactivateOffer(c.Request.Context(), productID, claims.UserID)
+	if err != nil {
+		writeOfferError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToAPIOfferFromService(offer))
+}
+
+func writeOfferError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidOffer):
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "price must be positive, sell amount and stock non-negative and at least one field set"})
+	case errors.Is(err, model.ErrNoRows):
+		c.JSON(http.StatusNotFound, modelApi.ErrorResponse{Err: "you do not offer this product"})
+	default:
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+	}
+}
+
+// supplierClaims returns the claims of an authenticated supplier, writing
+// the error response otherwise.
+func supplierClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: "unauthorized: invalid or missing JWT token"})
+		return nil, false
+	}
+	if claims.Role != model.SupplierRole {
+		c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: "only suppliers can manage offers"})
+		return nil, false
+	}
+	return claims, true
+}
+
+func productIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid product ID"})
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"diploma/modules/auth/jwt"
+	"diploma/modules/product/handler/converter"
+	modelApi "diploma/modules/product/handler/model"
+	"diploma/modules/product/model"
+	contextkeys "diploma/pkg/context-keys"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateProductAttributes godoc
+// @Summary      Update product attributes and gallery
+// @Description  Admins and the supplier who added the product. Changes the given attributes (description, brand, manufacturer, weight, country of origin, unit, shelf life, storage conditions); an empty string or zero shelf life clears one. Images, when given, replace the gallery and the first one becomes the main image.
+// @Tags         product
+// @Accept       json
+// @Produce      json
+// @Security ApiKeyAuth
+// @Param        id    path      int                                      true "Product ID"
+// @Param        input body      modelApi.UpdateProductAttributesRequest  true "Fields to change"
+// @Success      200  {object}  modelApi.ProductResponse
+// @Failure      400  {object}  modelApi.ErrorResponse
+// @Failure      401  {object}  modelApi.ErrorResponse
+// @Failure      403  {object}  modelApi.ErrorResponse
+// @Failure      404  {object}  modelApi.ErrorResponse
+// @Failure      500  {object}  modelApi.ErrorResponse
+// @Router       /api/product/{id} [patch]
+func (h *CatalogHandler) UpdateProductAttributes(c *gin.Context) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: "unauthorized: invalid or missing JWT token"})
+		return
+	}
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req modelApi.UpdateProductAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid request body"})
+		return
+	}
+
+	product, err := h.service.UpdateProductAttributes(c.Request.Context(), productID, claims.UserID, claims.Role,
+		converter.ToServiceProductAttributesUpdateFromAPI(&req))
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidAttributes):
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "set at least one field; texts up to 255 characters (description 5000), non-negative shelf life, up to 20 http(s) image URLs"})
+		case errors.Is(err, model.ErrForbidden):
+			c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: "only admins and suppliers offering the product can edit it"})
+		case errors.Is(err, model.ErrNoRows):
+			c.JSON(http.StatusNotFound, modelApi.ErrorResponse{Err: "Product not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToApiProductResponeFromService(product))
+}
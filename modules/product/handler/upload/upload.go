@@ -0,0 +1,285 @@
+package upload
+
+import (
+	"bytes"
+	"diploma/modules/product/model"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// Import file columns
+const (
+	colGTIN = iota
+	colPrice
+	colSellAmount
+	colStock
+	colCategory
+	colCount
+)
+
+// headers maps lower-cased header titles to columns.
+var headers = map[string]int{
+	"gtin":        colGTIN,
+	"ean":         colGTIN,
+	"barcode":     colGTIN,
+	"штрихкод":    colGTIN,
+	"штрих-код":   colGTIN,
+	"price":       colPrice,
+	"цена":        colPrice,
+	"min qty":     colSellAmount,
+	"min_qty":     colSellAmount,
+	"sell_amount": colSellAmount,
+	"минимальное количество": colSellAmount,
+	"мин. количество":        colSellAmount,
+	"stock":                  colStock,
+	"остаток":                colStock,
+	"category":               colCategory,
+	"category_id":            colCategory,
+	"категория":              colCategory,
+}
+
+// Parse reads import rows from a CSV or XLSX file. The first line is the
+// header; gtin and price columns are required, the others optional. Rows
+// that fail validation are returned failed with the reason, so that they
+// show up in the error report.
+func Parse(format string, r io.Reader) ([]model.ImportRow, error) {
+	var (
+		records [][]string
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatXLSX:
+		records, err = readXLSX(r)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", model.ErrInvalidImportFile, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: empty file", model.ErrInvalidImportFile)
+	}
+
+	columns, err := mapColumns(records[0])
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]model.ImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		if len(rows) == model.MaxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", model.ErrInvalidImportFile, model.MaxImportRows)
+		}
+		rows = append(rows, parseRow(i+2, record, columns))
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", model.ErrInvalidImportFile)
+	}
+	return rows, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	// Excel saves CSV with ';' in locales that use a decimal comma.
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidImportFile, err)
+	}
+	return records, nil
+}
+
+// readXLSX reads the first sheet.
+func readXLSX(r io.Reader) ([][]string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidImportFile, err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("%w: no sheets", model.ErrInvalidImportFile)
+	}
+	records, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", model.ErrInvalidImportFile, err)
+	}
+	return records, nil
+}
+
+// mapColumns returns the record index of every column, -1 if absent.
+func mapColumns(header []string) ([colCount]int, error) {
+	var columns [colCount]int
+	for i := range columns {
+		columns[i] = -1
+	}
+	for i, title := range header {
+		col, ok := headers[strings.ToLower(strings.TrimSpace(title))]
+		if ok && columns[col] == -1 {
+			columns[col] = i
+		}
+	}
+
+	if columns[colGTIN] == -1 {
+		return columns, fmt.Errorf("%w: gtin column is missing", model.ErrInvalidImportFile)
+	}
+	if columns[colPrice] == -1 {
+		return columns, fmt.Errorf("%w: price column is missing", model.ErrInvalidImportFile)
+	}
+	return columns, nil
+}
+
+func parseRow(rowNo int, record []string, columns [colCount]int) model.ImportRow {
+	cell := func(col int) string {
+		if columns[col] == -1 || columns[col] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[columns[col]])
+	}
+
+	row := model.ImportRow{
+		RowNo:   rowNo,
+		RawGTIN: cell(colGTIN),
+		Status:  model.ImportRowPending,
+	}
+	var errs []string
+
+	gtin, err := parseGTIN(row.RawGTIN)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	price, err := parsePrice(cell(colPrice))
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	sellAmount, err := parseCount(cell(colSellAmount), "min qty")
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	stock, err := parseCount(cell(colStock), "stock")
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	category, err := parseCount(cell(colCategory), "category")
+	if err == nil && category != nil && *category == 0 {
+		err = errors.New("category must be positive")
+	}
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		row.Fail(strings.Join(errs, "; "))
+		return row
+	}
+
+	row.GTIN = gtin
+	row.Price = price
+	row.SellAmount = sellAmount
+	row.Stock = stock
+	row.CategoryID = category
+	return row
+}
+
+func parseGTIN(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("gtin is empty")
+	}
+	gtin, err := model.NormalizeGTIN(s)
+	if err != nil {
+		return 0, errors.New(strings.TrimPrefix(err.Error(), "models: "))
+	}
+	return gtin, nil
+}
+
+// parsePrice accepts a decimal comma and rounds to whole tenge.
+func parsePrice(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("price is empty")
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, " ", ""), ",", ".")
+	price, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, errors.New("price is not a number")
+	}
+	rounded := math.Round(price)
+	if rounded <= 0 || rounded > math.MaxInt32 {
+		return 0, errors.New("price must be positive")
+	}
+	return int(rounded), nil
+}
+
+// parseCount parses an optional non-negative whole number.
+func parseCount(s, name string) (*int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a whole number", name)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("%s must not be negative", name)
+	}
+	return &n, nil
+}
+
+func isBlank(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+var reportHeader = []string{"Row", "GTIN", "Error"}
+
+// WriteErrorReport writes the failed rows as CSV.
+func WriteErrorReport(w io.Writer, rows []model.ImportRow) error {
+	// UTF-8 BOM so that Excel opens Cyrillic text correctly.
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(reportHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write([]string{strconv.Itoa(row.RowNo), row.RawGTIN, row.Error}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
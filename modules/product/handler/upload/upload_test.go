@@ -0,0 +1,98 @@
+package upload
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParse_CSV(t *testing.T) {
+	file := "\ufeffШтрихкод;Цена;Мин. количество;Остаток;Категория\n" +
+		"4870001234560;1 250,40;6;100;3\n" +
+		";;;;\n" +
+		"48700016;99;;;\n"
+
+	rows, err := Parse(FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, 2, rows[0].RowNo)
+	assert.Equal(t, model.ImportRowPending, rows[0].Status)
+	assert.Equal(t, int64(4870001234560), rows[0].GTIN)
+	assert.Equal(t, 1250, rows[0].Price)
+	require.NotNil(t, rows[0].SellAmount)
+	assert.Equal(t, 6, *rows[0].SellAmount)
+	require.NotNil(t, rows[0].Stock)
+	assert.Equal(t, 100, *rows[0].Stock)
+	require.NotNil(t, rows[0].CategoryID)
+	assert.Equal(t, 3, *rows[0].CategoryID)
+
+	assert.Equal(t, 4, rows[1].RowNo)
+	assert.Equal(t, int64(48700016), rows[1].GTIN)
+	assert.Nil(t, rows[1].SellAmount)
+	assert.Nil(t, rows[1].Stock)
+	assert.Nil(t, rows[1].CategoryID)
+}
+
+func TestParse_InvalidRowsFail(t *testing.T) {
+	file := "gtin,price,stock,category\n" +
+		"12345,100,,\n" +
+		"4870001234560,0,-1,\n" +
+		"4870001234560,abc,,0\n"
+
+	rows, err := Parse(FormatCSV, strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	for _, row := range rows {
+		assert.Equal(t, model.ImportRowFailed, row.Status)
+	}
+	assert.Equal(t, "invalid GTIN: must be 8, 12, 13 or 14 digits", rows[0].Error)
+	assert.Equal(t, "price must be positive; stock must not be negative", rows[1].Error)
+	assert.Equal(t, "price is not a number; category must be positive", rows[2].Error)
+	assert.Equal(t, "12345", rows[0].RawGTIN)
+}
+
+func TestParse_MissingRequiredColumn(t *testing.T) {
+	_, err := Parse(FormatCSV, strings.NewReader("gtin,stock\n4870001234560,1\n"))
+	assert.ErrorIs(t, err, model.ErrInvalidImportFile)
+
+	_, err = Parse(FormatCSV, strings.NewReader("gtin,price\n"))
+	assert.ErrorIs(t, err, model.ErrInvalidImportFile)
+}
+
+func TestParse_XLSX(t *testing.T) {
+	file := excelize.NewFile()
+	require.NoError(t, file.SetSheetRow("Sheet1", "A1", &[]interface{}{"Category", "GTIN", "Price"}))
+	require.NoError(t, file.SetSheetRow("Sheet1", "A2", &[]interface{}{2, "04870001234560", 350}))
+	var buf bytes.Buffer
+	require.NoError(t, file.Write(&buf))
+
+	rows, err := Parse(FormatXLSX, &buf)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	assert.Equal(t, int64(4870001234560), rows[0].GTIN)
+	assert.Equal(t, 350, rows[0].Price)
+	require.NotNil(t, rows[0].CategoryID)
+	assert.Equal(t, 2, *rows[0].CategoryID)
+}
+
+func TestWriteErrorReport(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteErrorReport(&buf, []model.ImportRow{
+		{RowNo: 3, RawGTIN: "4870001234567", Error: "invalid GTIN: wrong check digit"},
+		{RowNo: 5, RawGTIN: "4870001234560", Error: "price is not a number; stock must not be negative"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "\ufeffRow,GTIN,Error\n"+
+		"3,4870001234567,invalid GTIN: wrong check digit\n"+
+		"5,4870001234560,price is not a number; stock must not be negative\n", buf.String())
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// PricePoint is a supplier's price of a product at a moment: a price_history
+// row, or a day of the analytics series.
+type PricePoint struct {
+	SupplierID   int64
+	SupplierName string
+	Price        int
+	Date         time.Time
+}
+
+// PriceAnalytics summarizes a product's prices over a period.
+type PriceAnalytics struct {
+	ProductID    int64
+	ProductName  string
+	CurrentPrice int // lowest price on offer now
+	MinPrice     int
+	MaxPrice     int
+	AvgPrice     float64
+	History      []PricePoint // price of each supplier on each day of the period
+	Suppliers    []SupplierPriceStats
+}
+
+// SupplierPriceStats are one supplier's prices over the period.
+type SupplierPriceStats struct {
+	SupplierID   int64
+	SupplierName string
+	CurrentPrice int  // 0 when the supplier no longer offers the product
+	Active       bool // the supplier offers the product now
+	MinPrice     int
+	MaxPrice     int
+	AvgPrice     float64 // average of the daily prices
+	PriceChanges int     // price history records within the period
+}
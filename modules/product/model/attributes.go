@@ -0,0 +1,107 @@
+package model
+
+import (
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxProductImages bounds the size of a product's gallery.
+	MaxProductImages = 20
+
+	maxAttributeLength   = 255
+	maxDescriptionLength = 5000
+)
+
+// ProductAttributes are the structured properties of a product. Empty
+// strings and nil mean unknown.
+type ProductAttributes struct {
+	Description       string
+	Brand             string
+	Manufacturer      string
+	Weight            string // as labelled, e.g. "900 мл"
+	Country           string // country of origin
+	Unit              string // unit of sale, e.g. "шт", "кг"
+	ShelfLifeDays     *int
+	StorageConditions string
+}
+
+// ProductAttributesUpdate changes the attributes that are set; an empty
+// string or a zero shelf life clears one. Images, when set, replace the
+// gallery.
+type ProductAttributesUpdate struct {
+	Description       *string
+	Brand             *string
+	Manufacturer      *string
+	Weight            *string
+	Country           *string
+	Unit              *string
+	ShelfLifeDays     *int
+	StorageConditions *string
+	Images            *[]string
+}
+
+// HasAttributes reports whether the update changes any attribute, as
+// opposed to only the gallery.
+func (u *ProductAttributesUpdate) HasAttributes() bool {
+	return u.Description != nil || u.Brand != nil || u.Manufacturer != nil ||
+		u.Weight != nil || u.Country != nil || u.Unit != nil ||
+		u.ShelfLifeDays != nil || u.StorageConditions != nil
+}
+
+func (u *ProductAttributesUpdate) Validate() error {
+	if !u.HasAttributes() && u.Images == nil {
+		return ErrInvalidAttributes
+	}
+	if u.Description != nil && utf8.RuneCountInString(*u.Description) > maxDescriptionLength {
+		return ErrInvalidAttributes
+	}
+	for _, attr := range []*string{u.Brand, u.Manufacturer, u.Weight, u.Country, u.Unit, u.StorageConditions} {
+		if attr != nil && utf8.RuneCountInString(*attr) > maxAttributeLength {
+			return ErrInvalidAttributes
+		}
+	}
+	if u.ShelfLifeDays != nil && *u.ShelfLifeDays < 0 {
+		return ErrInvalidAttributes
+	}
+	if u.Images != nil {
+		if len(*u.Images) > MaxProductImages {
+			return ErrInvalidAttributes
+		}
+		for _, image := range *u.Images {
+			if !validImageURL(image) {
+				return ErrInvalidAttributes
+			}
+		}
+	}
+	return nil
+}
+
+// Apply applies the attribute changes; the gallery is replaced separately.
+func (u *ProductAttributesUpdate) Apply(attrs *ProductAttributes) {
+	set := func(dst *string, src *string) {
+		if src != nil {
+			*dst = strings.TrimSpace(*src)
+		}
+	}
+	set(&attrs.Description, u.Description)
+	set(&attrs.Brand, u.Brand)
+	set(&attrs.Manufacturer, u.Manufacturer)
+	set(&attrs.Weight, u.Weight)
+	set(&attrs.Country, u.Country)
+	set(&attrs.Unit, u.Unit)
+	set(&attrs.StorageConditions, u.StorageConditions)
+
+	if u.ShelfLifeDays != nil {
+		attrs.ShelfLifeDays = u.ShelfLifeDays
+		if *u.ShelfLifeDays == 0 {
+			attrs.ShelfLifeDays = nil
+		}
+	}
+}
+
+func validImageURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
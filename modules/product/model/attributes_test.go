@@ -0,0 +1,54 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductAttributesUpdate_Validate(t *testing.T) {
+	str := func(s string) *string { return &s }
+	num := func(n int) *int { return &n }
+	images := func(urls ...string) *[]string { return &urls }
+
+	tests := []struct {
+		name   string
+		update ProductAttributesUpdate
+		valid  bool
+	}{
+		{name: "empty", update: ProductAttributesUpdate{}},
+		{name: "brand", update: ProductAttributesUpdate{Brand: str("Food Master")}, valid: true},
+		{name: "clear gallery", update: ProductAttributesUpdate{Images: images()}, valid: true},
+		{name: "long brand", update: ProductAttributesUpdate{Brand: str(strings.Repeat("я", 256))}},
+		{name: "long description", update: ProductAttributesUpdate{Description: str(strings.Repeat("a", 5001))}},
+		{name: "negative shelf life", update: ProductAttributesUpdate{ShelfLifeDays: num(-1)}},
+		{name: "zero shelf life", update: ProductAttributesUpdate{ShelfLifeDays: num(0)}, valid: true},
+		{name: "image url", update: ProductAttributesUpdate{Images: images("https://cdn.example.com/a.jpg")}, valid: true},
+		{name: "relative image", update: ProductAttributesUpdate{Images: images("/a.jpg")}},
+		{name: "image scheme", update: ProductAttributesUpdate{Images: images("javascript:alert(1)")}},
+		{name: "too many images", update: ProductAttributesUpdate{Images: images(make([]string, MaxProductImages+1)...)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.update.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidAttributes)
+			}
+		})
+	}
+}
+
+func TestProductAttributesUpdate_Apply(t *testing.T) {
+	days := 180
+	attrs := ProductAttributes{Brand: "Old", Country: "Kazakhstan", ShelfLifeDays: &days}
+	brand, country, zero := "  New ", "", 0
+
+	(&ProductAttributesUpdate{Brand: &brand, Country: &country, ShelfLifeDays: &zero}).Apply(&attrs)
+
+	assert.Equal(t, "New", attrs.Brand)
+	assert.Empty(t, attrs.Country)
+	assert.Nil(t, attrs.ShelfLifeDays)
+}
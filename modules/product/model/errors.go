@@ -8,4 +8,18 @@ var (
 	ErrDuplicateNumber = errors.New("models: duplicate email")
 
 	ErrNoRows = errors.New("models: no rows")
+
+	ErrEmptySearchQuery = errors.New("models: empty search query")
+
+	ErrInvalidListQuery = errors.New("models: invalid product list query")
+
+	ErrInvalidOffer = errors.New("models: invalid offer")
+
+	ErrInvalidImportFile = errors.New("models: invalid import file")
+
+	ErrInvalidGTIN = errors.New("models: invalid GTIN")
+
+	ErrInvalidAttributes = errors.New("models: invalid product attributes")
+
+	ErrForbidden = errors.New("models: access denied")
 )
@@ -0,0 +1,55 @@
+package model
+
+// PriceBucketBounds are the lower bounds, in tenge, of the price facet
+// buckets. The first bucket starts at 0 and the last one is open-ended.
+var PriceBucketBounds = []int{500, 1000, 2000, 5000, 10000}
+
+// ProductFacets are product counts of the filtered product list grouped by
+// category, supplier and lowest price.
+type ProductFacets struct {
+	Categories   []CategoryFacet
+	Suppliers    []SupplierFacet
+	PriceBuckets []PriceBucketFacet
+}
+
+type CategoryFacet struct {
+	ID            int
+	Name          string
+	Count         int
+	Subcategories []SubcategoryFacet
+}
+
+type SubcategoryFacet struct {
+	ID    int
+	Name  string
+	Count int
+}
+
+// SupplierFacet counts products offered by the supplier.
+type SupplierFacet struct {
+	ID    int64
+	Name  string
+	Count int
+}
+
+// PriceBucketFacet counts products whose lowest price is in [Min, Max).
+// Max is nil for the last bucket.
+type PriceBucketFacet struct {
+	Min   int
+	Max   *int
+	Count int
+}
+
+// PriceBucket returns the bounds of bucket i as numbered by width_bucket
+// over PriceBucketBounds (0 is below the first bound).
+func PriceBucket(i int) (int, *int) {
+	var min int
+	if i > 0 {
+		min = PriceBucketBounds[i-1]
+	}
+	if i >= len(PriceBucketBounds) {
+		return min, nil
+	}
+	max := PriceBucketBounds[i]
+	return min, &max
+}
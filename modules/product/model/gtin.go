@@ -0,0 +1,80 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// gtinLength is the length of a GTIN-14; shorter forms are left-padded
+// with zeros to it.
+const gtinLength = 14
+
+// NormalizeGTIN validates a barcode in any GTIN form (EAN-8, UPC-A, EAN-13
+// or GTIN-14) and returns it as a GTIN-14 number. Spaces and hyphens are
+// ignored. Since the forms differ only by leading zeros, every form of the
+// same item yields the same number.
+func NormalizeGTIN(s string) (int64, error) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(s))
+
+	switch len(digits) {
+	case 8, 12, 13, 14:
+	default:
+		return 0, fmt.Errorf("%w: must be 8, 12, 13 or 14 digits", ErrInvalidGTIN)
+	}
+	if strings.Trim(digits, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: must be 8, 12, 13 or 14 digits", ErrInvalidGTIN)
+	}
+
+	gtin, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidGTIN, err)
+	}
+	if !ValidGTIN(gtin) {
+		return 0, fmt.Errorf("%w: wrong check digit", ErrInvalidGTIN)
+	}
+	return gtin, nil
+}
+
+// ValidGTIN reports whether the number is a GTIN-14 with a correct check
+// digit.
+func ValidGTIN(gtin int64) bool {
+	if gtin <= 0 {
+		return false
+	}
+	digits := FormatGTIN(gtin)
+	if len(digits) != gtinLength {
+		return false
+	}
+	return checkDigit(digits[:gtinLength-1]) == digits[gtinLength-1]-'0'
+}
+
+// FormatGTIN returns the GTIN-14 form of the number.
+func FormatGTIN(gtin int64) string {
+	return fmt.Sprintf("%0*d", gtinLength, gtin)
+}
+
+// LookupGTIN returns the form of the GTIN to search catalogs by: the
+// EAN-13 form, which keeps the leading zeros of UPC-A and EAN-8 codes, or
+// the GTIN-14 form of a case, whose indicator digit is not 0.
+func LookupGTIN(gtin int64) string {
+	digits := FormatGTIN(gtin)
+	if digits[0] == '0' {
+		return digits[1:]
+	}
+	return digits
+}
+
+// checkDigit computes the GS1 check digit: digits are weighted 3 and 1
+// alternately, starting with 3 next to the check digit.
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte((10 - sum%10) % 10)
+}
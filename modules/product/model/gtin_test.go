@@ -0,0 +1,71 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGTIN_SameItemInEveryForm(t *testing.T) {
+	for _, s := range []string{
+		"012345678905",   // UPC-A
+		"0012345678905",  // EAN-13
+		"00012345678905", // GTIN-14
+		" 0 012345 678905 ",
+		"0-012345-678905",
+	} {
+		gtin, err := NormalizeGTIN(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, int64(12345678905), gtin, s)
+		assert.Equal(t, "00012345678905", FormatGTIN(gtin))
+	}
+}
+
+func TestNormalizeGTIN(t *testing.T) {
+	for s, want := range map[string]int64{
+		"96385074":       96385074,       // EAN-8
+		"4870001234560":  4870001234560,  // EAN-13
+		"14870001234567": 14870001234567, // GTIN-14 of a case, another item
+	} {
+		gtin, err := NormalizeGTIN(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, gtin, s)
+	}
+}
+
+func TestNormalizeGTIN_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"4870001234567",   // wrong check digit
+		"96385075",        // wrong check digit
+		"1234567",         // too short
+		"48700012345601",  // wrong check digit
+		"487000123456012", // too long
+		"48700O1234560",   // letter O
+		"00000000000000",
+	} {
+		_, err := NormalizeGTIN(s)
+		assert.ErrorIs(t, err, ErrInvalidGTIN, s)
+	}
+}
+
+func TestLookupGTIN(t *testing.T) {
+	for gtin, want := range map[int64]string{
+		4870001234560:  "4870001234560",
+		12345678905:    "0012345678905", // UPC-A
+		96385074:       "0000096385074", // EAN-8
+		14870001234567: "14870001234567",
+	} {
+		assert.Equal(t, want, LookupGTIN(gtin), gtin)
+	}
+}
+
+func TestValidGTIN(t *testing.T) {
+	assert.True(t, ValidGTIN(4870001234560))
+	assert.True(t, ValidGTIN(96385074))
+	assert.False(t, ValidGTIN(4870001234567))
+	assert.False(t, ValidGTIN(0))
+	assert.False(t, ValidGTIN(-4870001234560))
+	assert.False(t, ValidGTIN(100000000000000))
+}
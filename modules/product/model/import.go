@@ -0,0 +1,103 @@
+package model
+
+import "time"
+
+// Import row statuses
+const (
+	ImportRowPending  = "pending"  // not processed yet
+	ImportRowLookup   = "lookup"   // GTIN unknown locally, queued for NCT lookup
+	ImportRowImported = "imported" // offer created or updated
+	ImportRowFailed   = "failed"   // see Error
+)
+
+// MaxImportRows bounds the number of rows in one uploaded file.
+const MaxImportRows = 10000
+
+const (
+	// MaxLookupAttempts is the number of failed NCT lookups after which the
+	// rows waiting for the GTIN are failed.
+	MaxLookupAttempts = 5
+	// LookupRetryDelay is the delay after the first failed lookup; it
+	// doubles with every further one.
+	LookupRetryDelay = 5 * time.Minute
+)
+
+// ImportPriceChangeReason is recorded in the price history for prices set
+// by an import.
+const ImportPriceChangeReason = "bulk import"
+
+// Import is a bulk offer import uploaded by a supplier.
+type Import struct {
+	ID         int64
+	SupplierID int64
+	FileName   string
+	CreatedAt  time.Time
+	FinishedAt *time.Time
+	Progress   ImportProgress
+}
+
+// ImportProgress counts the rows of an import by status.
+type ImportProgress struct {
+	Total    int
+	Pending  int
+	Lookup   int
+	Imported int
+	Failed   int
+}
+
+func (p ImportProgress) Done() bool {
+	return p.Pending == 0 && p.Lookup == 0
+}
+
+// ImportRow is one line of an import file. Numeric fields are nil when the
+// cell is empty or, for failed rows, could not be parsed.
+type ImportRow struct {
+	ImportID   int64
+	SupplierID int64
+	RowNo      int // 1-based line of the file, the header being line 1
+	RawGTIN    string
+	GTIN       int64
+	Price      int
+	SellAmount *int
+	Stock      *int
+	CategoryID *int
+	Status     string
+	Error      string
+	ProductID  *int64
+	// LookupAttempts counts failed NCT lookups; NextLookupAt is when the
+	// next one is due, zero when it is due now.
+	LookupAttempts int
+	NextLookupAt   time.Time
+}
+
+// Fail marks the row failed with the reason.
+func (r *ImportRow) Fail(reason string) {
+	r.Status = ImportRowFailed
+	r.Error = reason
+}
+
+// RetryLookup records a failed NCT lookup of the row's GTIN and schedules
+// the next one, or fails the row with the reason once MaxLookupAttempts
+// lookups failed.
+func (r *ImportRow) RetryLookup(now time.Time, reason string) {
+	r.LookupAttempts++
+	if r.LookupAttempts >= MaxLookupAttempts {
+		r.Fail(reason)
+		return
+	}
+	r.NextLookupAt = now.Add(LookupRetryDelay << (r.LookupAttempts - 1))
+}
+
+// Update returns the offer change the row describes: the price, and the
+// minimum quantity and stock only when the file gives them. An imported
+// offer is made available.
+func (r *ImportRow) Update() *OfferUpdate {
+	price, available := r.Price, true
+	return &OfferUpdate{
+		Price:      &price,
+		SellAmount: r.SellAmount,
+		Stock:      r.Stock,
+		Available:  &available,
+		Reason:     ImportPriceChangeReason,
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+// DailyPrice is a product's row of the daily market aggregates.
+type DailyPrice struct {
+	Day          time.Time
+	ProductID    int64
+	ProductName  string
+	CategoryID   int // 0 when the product has no category
+	CategoryName string
+	AvgPrice     float64 // mean of the suppliers' prices at the end of the day; 0 if none was offered
+	Volume       int     // quantity ordered that day
+}
+
+// MarketAnalytics describes price movements over a period. Indices start
+// at 100 on the first day of the period and are weighted by the quantity
+// of each product ordered during the period.
+type MarketAnalytics struct {
+	Days          int
+	Category      string // set when filtered by category
+	TotalProducts int
+	PriceChange   float64 // change of the index over the period, %
+	Volatility    float64 // standard deviation of daily index changes, %
+	TopGainers    []ProductTrend
+	TopLosers     []ProductTrend
+	Trend         []MarketIndexPoint
+	Categories    []CategoryMarket
+}
+
+// ProductTrend is a product's price change over the period.
+type ProductTrend struct {
+	ProductID    int64
+	ProductName  string
+	CurrentPrice int
+	PriceChange  float64 // %
+	Volume       int     // quantity ordered during the period
+}
+
+// MarketIndexPoint is the value of the index on a day.
+type MarketIndexPoint struct {
+	Date   time.Time
+	Index  float64
+	Volume int // quantity ordered that day
+}
+
+// CategoryMarket is the index of one category over the period.
+type CategoryMarket struct {
+	CategoryID      int
+	CategoryName    string
+	ProductCount    int
+	PriceChange     float64 // %
+	Volatility      float64 // %
+	TopProduct      string  // most ordered product
+	TopProductPrice int
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// DefaultPriceChangeReason is recorded in the price history when the
+// supplier does not give a reason.
+const DefaultPriceChangeReason = "supplier update"
+
+// DefaultSellAmount is the minimum quantity of an offer created without one.
+const DefaultSellAmount = 1
+
+// maxChangeReasonLength is the size of price_history.change_reason.
+const maxChangeReasonLength = 100
+
+// Offer is a supplier's offer of a product (a products_supplier row).
+type Offer struct {
+	ProductID  int64
+	SupplierID int64
+	Price      int
+	SellAmount int  // minimum quantity sold
+	Stock      *int // nil when the supplier does not report stock
+	Available  bool
+}
+
+// OfferUpdate changes the fields that are set.
+type OfferUpdate struct {
+	Price      *int
+	SellAmount *int
+	Stock      *int
+	Available  *bool
+	Reason     string
+}
+
+func (u *OfferUpdate) Validate() error {
+	if u.Price == nil && u.SellAmount == nil && u.Stock == nil && u.Available == nil {
+		return ErrInvalidOffer
+	}
+	if u.Price != nil && *u.Price <= 0 {
+		return ErrInvalidOffer
+	}
+	if u.SellAmount != nil && *u.SellAmount < 0 {
+		return ErrInvalidOffer
+	}
+	if u.Stock != nil && *u.Stock < 0 {
+		return ErrInvalidOffer
+	}
+	if len([]rune(u.Reason)) > maxChangeReasonLength {
+		return ErrInvalidOffer
+	}
+	return nil
+}
+
+// Apply applies the update to the offer and reports whether the price changed.
+func (u *OfferUpdate) Apply(offer *Offer) bool {
+	priceChanged := u.Price != nil && *u.Price != offer.Price
+	if u.Price != nil {
+		offer.Price = *u.Price
+	}
+	if u.SellAmount != nil {
+		offer.SellAmount = *u.SellAmount
+	}
+	if u.Stock != nil {
+		offer.Stock = u.Stock
+	}
+	if u.Available != nil {
+		offer.Available = *u.Available
+	}
+	return priceChanged
+}
+
+// PriceChange is a price_history row.
+type PriceChange struct {
+	ProductID  int64
+	SupplierID int64
+	Price      int
+	Date       time.Time
+	Reason     string
+}
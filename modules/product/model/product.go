@@ -2,16 +2,57 @@ package model
 
 import "time"
 
+// Product list sort orders
+const (
+	ProductSortDefault = ""       // by product ID
+	ProductSortPrice   = "price"  // lowest price first
+	ProductSortName    = "name"   // alphabetically
+	ProductSortNewest  = "newest" // most recently added first
+)
+
 type ProductListQuery struct {
 	Offset        int
 	Limit         int
 	CategoryID    *int // Optional category filter
 	SubcategoryID *int // Optional subcategory filter
+	Sort          string
+
+	// Offer filters. A product matches when at least one supplier offers it
+	// satisfying all of them.
+	MinPrice          *int
+	MaxPrice          *int
+	SupplierID        *int64
+	FreeDeliveryBelow *int // supplier's free delivery threshold is at most this amount
+	MaxMinOrder       *int // supplier's minimum order amount is at most this amount
+}
+
+func (q *ProductListQuery) Validate() error {
+	switch q.Sort {
+	case ProductSortDefault, ProductSortPrice, ProductSortName, ProductSortNewest:
+	default:
+		return ErrInvalidListQuery
+	}
+	for _, v := range []*int{q.MinPrice, q.MaxPrice, q.FreeDeliveryBelow, q.MaxMinOrder} {
+		if v != nil && *v < 0 {
+			return ErrInvalidListQuery
+		}
+	}
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return ErrInvalidListQuery
+	}
+	return nil
+}
+
+// HasOfferFilter reports whether any of the offer filters is set.
+func (q *ProductListQuery) HasOfferFilter() bool {
+	return q.MinPrice != nil || q.MaxPrice != nil || q.SupplierID != nil ||
+		q.FreeDeliveryBelow != nil || q.MaxMinOrder != nil
 }
 
 type ProductList struct {
 	Products []Product
 	Total    int
+	Facets   *ProductFacets
 }
 
 type ProductQuery struct {
@@ -30,16 +71,20 @@ type Product struct {
 	ImageUrl              string
 	CreatedAt             time.Time
 	UpdatedAt             time.Time
+	CreatedBy             *int64 // supplier who added the product, nil if not recorded
 	LowestProductSupplier ProductSupplier
 	CategoryID            *int   `json:"category_id"`
 	SubcategoryID         *int   `json:"subcategory_id"`
 	CategoryName          string `json:"category_name,omitempty"`
 	SubcategoryName       string `json:"subcategory_name,omitempty"`
+	ProductAttributes
+	Images []string // gallery in display order, filled for a single product only
 }
 
 type ProductSupplier struct {
 	Price      *int
 	SellAmount *int
+	Available  *bool // set on the supplier's own product list only
 	Supplier   Supplier
 }
 
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ProductSearchQuery is a free-text product search. Query is matched against
+// product names; when it is made of digits only it is also tried as a GTIN.
+type ProductSearchQuery struct {
+	Query  string
+	Offset int
+	Limit  int
+}
+
+// Normalized returns the query lower-cased with ё folded to е, the same
+// normalization the search index applies to product names.
+func (q *ProductSearchQuery) Normalized() string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(q.Query)), "ё", "е")
+}
+
+// GTIN returns the query as a GTIN if it looks like one.
+func (q *ProductSearchQuery) GTIN() (int64, bool) {
+	s := strings.TrimSpace(q.Query)
+	if len(s) < 8 || len(s) > 14 {
+		return 0, false
+	}
+	gtin, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || gtin <= 0 {
+		return 0, false
+	}
+	return gtin, true
+}
+
+type ProductSearchResult struct {
+	Product
+	// Highlight is the product name with matched words wrapped in <b></b>.
+	Highlight string
+	Rank      float64
+}
+
+type ProductSearchList struct {
+	Products []ProductSearchResult
+	Total    int
+}
@@ -0,0 +1,85 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/pkg/client/db"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+var (
+	pricePointColumns = []string{
+		"ph." + phSupplierIDCol,
+		"COALESCE(s." + sNameCol + ", '')",
+		"ph." + phPriceCol,
+		"ph." + phDateCol,
+	}
+	pricePointFrom         = priceHistoryTbl + " AS ph"
+	pricePointSupplierJoin = supplierTbl + " AS s ON s." + sIDCol + " = ph." + phSupplierIDCol
+)
+
+// GetPriceHistory returns the product's price changes recorded since from,
+// oldest first.
+func (r *repo) GetPriceHistory(ctx context.Context, productID int64, from time.Time) ([]model.PricePoint, error) {
+	builder := sq.
+		Select(pricePointColumns...).
+		From(pricePointFrom).
+		LeftJoin(pricePointSupplierJoin).
+		Where(sq.Eq{"ph." + phProductIDCol: productID}).
+		Where(sq.GtOrEq{"ph." + phDateCol: from}).
+		OrderBy("ph."+phDateCol, "ph.id").
+		PlaceholderFormat(sq.Dollar)
+
+	return r.pricePoints(ctx, "product_repository.GetPriceHistory", builder)
+}
+
+// GetPricesAt returns each supplier's last recorded price of the product
+// before at.
+func (r *repo) GetPricesAt(ctx context.Context, productID int64, at time.Time) ([]model.PricePoint, error) {
+	builder := sq.
+		Select(pricePointColumns...).
+		Options("DISTINCT ON (ph.supplier_id)").
+		From(pricePointFrom).
+		LeftJoin(pricePointSupplierJoin).
+		Where(sq.Eq{"ph." + phProductIDCol: productID}).
+		Where(sq.Lt{"ph." + phDateCol: at}).
+		OrderBy("ph."+phSupplierIDCol, "ph."+phDateCol+" DESC", "ph.id DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	return r.pricePoints(ctx, "product_repository.GetPricesAt", builder)
+}
+
+func (r *repo) pricePoints(ctx context.Context, name string, builder sq.SelectBuilder) ([]model.PricePoint, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build price history query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     name,
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history: %w", err)
+	}
+	defer rows.Close()
+
+	var points []model.PricePoint
+	for rows.Next() {
+		var p model.PricePoint
+		if err := rows.Scan(&p.SupplierID, &p.SupplierName, &p.Price, &p.Date); err != nil {
+			return nil, fmt.Errorf("failed to scan price history: %w", err)
+		}
+		points = append(points, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read price history: %w", err)
+	}
+
+	return points, nil
+}
@@ -0,0 +1,297 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/modules/product/repository/product/converter"
+	repoModel "diploma/modules/product/repository/product/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	pDescriptionCol       = "description"
+	pBrandCol             = "brand"
+	pManufacturerCol      = "manufacturer"
+	pWeightCol            = "weight"
+	pCountryCol           = "country_of_origin"
+	pUnitCol              = "unit"
+	pShelfLifeDaysCol     = "shelf_life_days"
+	pStorageConditionsCol = "storage_conditions"
+
+	// ======== product images table ========
+	productImagesTbl = "product_images"
+	piProductIDCol   = "product_id"
+	piPositionCol    = "position"
+	piURLCol         = "url"
+)
+
+// attributeColumns are the products columns scanned by scanAttributes, in
+// its order.
+var attributeColumns = []string{
+	pDescriptionCol,
+	pBrandCol,
+	pManufacturerCol,
+	pWeightCol,
+	pCountryCol,
+	pUnitCol,
+	pShelfLifeDaysCol,
+	pStorageConditionsCol,
+}
+
+// prefixed returns the columns qualified with the table alias.
+func prefixed(alias string, columns []string) []string {
+	res := make([]string, 0, len(columns))
+	for _, col := range columns {
+		res = append(res, alias+"."+col)
+	}
+	return res
+}
+
+// attributeDest returns scan destinations for attributeColumns.
+func attributeDest(attrs *repoModel.ProductAttributes) []interface{} {
+	return []interface{}{
+		&attrs.Description,
+		&attrs.Brand,
+		&attrs.Manufacturer,
+		&attrs.Weight,
+		&attrs.Country,
+		&attrs.Unit,
+		&attrs.ShelfLifeDays,
+		&attrs.StorageConditions,
+	}
+}
+
+// attributeValues returns the values of attributeColumns, NULL for unknown
+// attributes.
+func attributeValues(attrs *model.ProductAttributes) []interface{} {
+	return []interface{}{
+		nullString(attrs.Description),
+		nullString(attrs.Brand),
+		nullString(attrs.Manufacturer),
+		nullString(attrs.Weight),
+		nullString(attrs.Country),
+		nullString(attrs.Unit),
+		attrs.ShelfLifeDays,
+		nullString(attrs.StorageConditions),
+	}
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// LockProductAttributes returns the product's attributes and locks the
+// product until the end of the transaction.
+func (r *repo) LockProductAttributes(ctx context.Context, productID int64) (*model.ProductAttributes, error) {
+	builder := sq.
+		Select(attributeColumns...).
+		From(productsTbl).
+		Where(sq.Eq{pIdCol: productID}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build lock product attributes query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.LockProductAttributes",
+		QueryRaw: query,
+	}
+
+	var attrs repoModel.ProductAttributes
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(attributeDest(&attrs)...)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to lock product attributes: %w", err)
+	}
+
+	res := converter.ToProductAttributesFromRepo(attrs)
+	return &res, nil
+}
+
+// UpdateProductAttributes saves all attributes of the product.
+func (r *repo) UpdateProductAttributes(ctx context.Context, productID int64, attrs *model.ProductAttributes) error {
+	builder := sq.
+		Update(productsTbl).
+		Set(pUpdatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{pIdCol: productID}).
+		PlaceholderFormat(sq.Dollar)
+	for i, value := range attributeValues(attrs) {
+		builder = builder.Set(attributeColumns[i], value)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update product attributes query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.UpdateProductAttributes",
+		QueryRaw: query,
+	}
+
+	tag, err := r.db.DB().ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update product attributes: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNoRows
+	}
+	return nil
+}
+
+// GetProductImages returns the product's gallery in display order.
+func (r *repo) GetProductImages(ctx context.Context, productID int64) ([]string, error) {
+	builder := sq.
+		Select(piURLCol).
+		From(productImagesTbl).
+		Where(sq.Eq{piProductIDCol: productID}).
+		OrderBy(piPositionCol).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build get product images query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetProductImages",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product images: %w", err)
+	}
+	defer rows.Close()
+
+	images := []string{}
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan product image: %w", err)
+		}
+		images = append(images, url)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product images: %w", err)
+	}
+
+	return images, nil
+}
+
+// ReplaceProductImages replaces the product's gallery. The first image
+// becomes the product's main image; clearing the gallery keeps it.
+func (r *repo) ReplaceProductImages(ctx context.Context, productID int64, images []string) error {
+	deleteBuilder := sq.
+		Delete(productImagesTbl).
+		Where(sq.Eq{piProductIDCol: productID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := deleteBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build delete product images query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.DeleteProductImages",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to delete product images: %w", err)
+	}
+
+	if len(images) == 0 {
+		return nil
+	}
+
+	insertBuilder := sq.
+		Insert(productImagesTbl).
+		Columns(piProductIDCol, piPositionCol, piURLCol).
+		PlaceholderFormat(sq.Dollar)
+	for i, url := range images {
+		insertBuilder = insertBuilder.Values(productID, i, url)
+	}
+
+	query, args, err = insertBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build insert product images query: %w", err)
+	}
+
+	q = db.Query{
+		Name:     "product_repository.InsertProductImages",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to insert product images: %w", err)
+	}
+
+	updateBuilder := sq.
+		Update(productsTbl).
+		Set(pImageUrlCol, images[0]).
+		Set(pUpdatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{pIdCol: productID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err = updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update product image query: %w", err)
+	}
+
+	q = db.Query{
+		Name:     "product_repository.UpdateProductImage",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to update product image: %w", err)
+	}
+	return nil
+}
+
+// IsProductCreator reports whether the supplier added the product to the
+// catalog.
+func (r *repo) IsProductCreator(ctx context.Context, productID, supplierID int64) (bool, error) {
+	sub := sq.
+		Select("1").
+		From(productsTbl).
+		Where(sq.Eq{
+			pIdCol:        productID,
+			pCreatedByCol: supplierID,
+		})
+
+	builder := sq.
+		Select().
+		Column(sq.Expr("EXISTS (?)", sub)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build is product creator query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.IsProductCreator",
+		QueryRaw: query,
+	}
+
+	var exists bool
+	if err := r.db.DB().QueryRowContext(ctx, q, args...).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check product creator: %w", err)
+	}
+	return exists, nil
+}
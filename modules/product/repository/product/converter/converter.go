@@ -23,19 +23,21 @@ func ToProductFromRepo(product repoModel.Product) *model.Product {
 	}
 
 	return &model.Product{
-		ID:              product.ID,
-		GTIN:            product.GTIN,
-		Name:            product.Name,
-		ImageUrl:        product.ImageUrl,
-		CreatedAt:       product.CreatedAt,
-		UpdatedAt:       product.UpdatedAt,
-		CategoryID:      product.CategoryID,
-		SubcategoryID:   product.SubcategoryID,
-		CategoryName:    categoryName,
-		SubcategoryName: subcategoryName,
+		ID:                product.ID,
+		GTIN:              product.GTIN,
+		Name:              product.Name,
+		ImageUrl:          product.ImageUrl,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+		CategoryID:        product.CategoryID,
+		SubcategoryID:     product.SubcategoryID,
+		CategoryName:      categoryName,
+		SubcategoryName:   subcategoryName,
+		ProductAttributes: ToProductAttributesFromRepo(product.Attributes),
 		LowestProductSupplier: model.ProductSupplier{
 			Price:      &price,
 			SellAmount: &sellAmount,
+			Available:  product.LowestSupplier.Available,
 			Supplier:   ToSupplierFromRepo(product.LowestSupplier.Supplier),
 		},
 	}
@@ -74,3 +76,23 @@ func ToProductListFromRepo(products []repoModel.Product) []model.Product {
 	}
 	return result
 }
+
+func ToProductAttributesFromRepo(attrs repoModel.ProductAttributes) model.ProductAttributes {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	return model.ProductAttributes{
+		Description:       deref(attrs.Description),
+		Brand:             deref(attrs.Brand),
+		Manufacturer:      deref(attrs.Manufacturer),
+		Weight:            deref(attrs.Weight),
+		Country:           deref(attrs.Country),
+		Unit:              deref(attrs.Unit),
+		ShelfLifeDays:     attrs.ShelfLifeDays,
+		StorageConditions: deref(attrs.StorageConditions),
+	}
+}
@@ -21,6 +21,7 @@ func ToProductSupplierFromRepo(sp repoModel.ProductSupplier) model.ProductSuppli
 	return model.ProductSupplier{
 		Price:      sp.Price,
 		SellAmount: sp.SellAmount,
+		Available:  sp.Available,
 		Supplier:   model.Supplier(sp.Supplier),
 	}
 }
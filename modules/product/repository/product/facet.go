@@ -0,0 +1,190 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/pkg/client/db"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// Grouping bitmasks of GROUPING(p.category_id, p.subcategory_id,
+// o.supplier_id, bucket): a set bit is a column not grouped by.
+const (
+	categoryFacetGroup    = 0b0111
+	subcategoryFacetGroup = 0b0011
+	supplierFacetGroup    = 0b1101
+	priceFacetGroup       = 0b1110
+)
+
+type subcategoryFacet struct {
+	categoryID int
+	facet      model.SubcategoryFacet
+}
+
+// priceBucketExpr numbers the lowest price bucket of a product. The bounds
+// are inlined so that the expression in SELECT and GROUP BY is identical.
+func priceBucketExpr() string {
+	bounds := make([]string, 0, len(model.PriceBucketBounds))
+	for _, b := range model.PriceBucketBounds {
+		bounds = append(bounds, strconv.Itoa(b))
+	}
+	return "width_bucket(ps." + psPriceCol + ", ARRAY[" + strings.Join(bounds, ",") + "])"
+}
+
+// productFacetsQuery builds the query of GetProductFacets.
+func productFacetsQuery(queryParam *model.ProductListQuery) sq.SelectBuilder {
+	bucket := priceBucketExpr()
+	groupingSets := "GROUPING SETS (" +
+		"(p.category_id, c.name), " +
+		"(p.category_id, c.name, p.subcategory_id, sc.name), " +
+		"(o." + psSupplierIDCol + ", s." + sNameCol + "), " +
+		"(" + bucket + "))"
+
+	builder := sq.
+		Select(
+			"GROUPING(p.category_id, p.subcategory_id, o."+psSupplierIDCol+", "+bucket+") AS grp",
+			"p.category_id",
+			"c.name",
+			"p.subcategory_id",
+			"sc.name",
+			"o."+psSupplierIDCol,
+			"s."+sNameCol,
+			bucket+" AS bucket",
+			"COUNT(DISTINCT p."+pIdCol+")",
+		).
+		From(productsTbl + " AS p").
+		LeftJoin("categories AS c ON p.category_id = c.id").
+		LeftJoin("subcategories AS sc ON p.subcategory_id = sc.id").
+		LeftJoin(productsSupplierTbl + " AS ps ON ps." + psProductIDCol + " = p." + pIdCol +
+			" AND ps." + psSupplierIDCol + " = p." + pLowestSupplierIDCol).
+		LeftJoin(productsSupplierTbl + " AS o ON o." + psProductIDCol + " = p." + pIdCol +
+			" AND o." + psIsAvailableCol).
+		LeftJoin(supplierTbl + " AS s ON s." + sIDCol + " = o." + psSupplierIDCol)
+
+	return builder.
+		Where(productListFilter(queryParam)).
+		GroupBy(groupingSets).
+		// price buckets in order, other facets by product count
+		OrderBy("bucket", "COUNT(DISTINCT p."+pIdCol+") DESC", "c.name", "sc.name", "s."+sNameCol).
+		PlaceholderFormat(sq.Dollar)
+}
+
+// GetProductFacets counts the products matching the list filters by
+// category, subcategory, offering supplier and lowest price bucket in a
+// single scan using grouping sets.
+func (r *repo) GetProductFacets(ctx context.Context, queryParam *model.ProductListQuery) (*model.ProductFacets, error) {
+	query, args, err := productFacetsQuery(queryParam).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build get product facets query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetProductFacets",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product facets: %w", err)
+	}
+	defer rows.Close()
+
+	facets := &model.ProductFacets{}
+	categories := make(map[int]int) // category ID -> index in facets.Categories
+	var subcategories []subcategoryFacet
+
+	for rows.Next() {
+		var (
+			grp             int
+			categoryID      *int
+			categoryName    *string
+			subcategoryID   *int
+			subcategoryName *string
+			supplierID      *int64
+			supplierName    *string
+			priceBucket     *int
+			count           int
+		)
+		err := rows.Scan(
+			&grp,
+			&categoryID,
+			&categoryName,
+			&subcategoryID,
+			&subcategoryName,
+			&supplierID,
+			&supplierName,
+			&priceBucket,
+			&count,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product facet: %w", err)
+		}
+
+		switch grp {
+		case categoryFacetGroup:
+			if categoryID == nil {
+				continue
+			}
+			categories[*categoryID] = len(facets.Categories)
+			facets.Categories = append(facets.Categories, model.CategoryFacet{
+				ID:    *categoryID,
+				Name:  deref(categoryName),
+				Count: count,
+			})
+		case subcategoryFacetGroup:
+			if categoryID == nil || subcategoryID == nil {
+				continue
+			}
+			subcategories = append(subcategories, subcategoryFacet{
+				categoryID: *categoryID,
+				facet: model.SubcategoryFacet{
+					ID:    *subcategoryID,
+					Name:  deref(subcategoryName),
+					Count: count,
+				},
+			})
+		case supplierFacetGroup:
+			if supplierID == nil {
+				continue
+			}
+			facets.Suppliers = append(facets.Suppliers, model.SupplierFacet{
+				ID:    *supplierID,
+				Name:  deref(supplierName),
+				Count: count,
+			})
+		case priceFacetGroup:
+			if priceBucket == nil {
+				continue
+			}
+			min, max := model.PriceBucket(*priceBucket)
+			facets.PriceBuckets = append(facets.PriceBuckets, model.PriceBucketFacet{
+				Min:   min,
+				Max:   max,
+				Count: count,
+			})
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating product facets: %w", err)
+	}
+
+	for _, sub := range subcategories {
+		if i, ok := categories[sub.categoryID]; ok {
+			facets.Categories[i].Subcategories = append(facets.Categories[i].Subcategories, sub.facet)
+		}
+	}
+
+	return facets, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,71 @@
+package product
+
+import (
+	"diploma/internal/testutils"
+	"diploma/modules/product/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPriceBucket = "width_bucket(ps.price, ARRAY[500,1000,2000,5000,10000])"
+
+func TestProductFacetsQuery(t *testing.T) {
+	testutils.AssertSQL(t,
+		productFacetsQuery(&model.ProductListQuery{
+			CategoryID: intPtr(3),
+			MaxPrice:   intPtr(900),
+		}),
+		"SELECT GROUPING(p.category_id, p.subcategory_id, o.supplier_id, "+testPriceBucket+") AS grp, "+
+			"p.category_id, c.name, p.subcategory_id, sc.name, o.supplier_id, s.name, "+
+			testPriceBucket+" AS bucket, COUNT(DISTINCT p.id) "+
+			"FROM products AS p "+
+			"LEFT JOIN categories AS c ON p.category_id = c.id "+
+			"LEFT JOIN subcategories AS sc ON p.subcategory_id = sc.id "+
+			"LEFT JOIN products_supplier AS ps ON ps.product_id = p.id AND ps.supplier_id = p.lowest_supplier_id "+
+			"LEFT JOIN products_supplier AS o ON o.product_id = p.id AND o.is_available "+
+			"LEFT JOIN suppliers AS s ON s.user_id = o.supplier_id "+
+			"WHERE (p.category_id = $1 AND EXISTS ("+
+			"SELECT 1 FROM products_supplier AS fps "+
+			"JOIN suppliers AS fs ON fs.user_id = fps.supplier_id "+
+			"LEFT JOIN delivery_conditions AS fdc ON fdc.condition_id = fs.condition_id "+
+			"WHERE (fps.product_id = p.id AND fps.is_available = $2 AND fps.price <= $3))) "+
+			"GROUP BY GROUPING SETS ("+
+			"(p.category_id, c.name), "+
+			"(p.category_id, c.name, p.subcategory_id, sc.name), "+
+			"(o.supplier_id, s.name), "+
+			"("+testPriceBucket+")) "+
+			"ORDER BY bucket, COUNT(DISTINCT p.id) DESC, c.name, sc.name, s.name",
+		3, true, 900,
+	)
+}
+
+// TestFacetGroups derives the GROUPING result of each grouping set: the
+// first GROUPING argument is the highest bit and a set bit is a column not
+// grouped by.
+func TestFacetGroups(t *testing.T) {
+	columns := []string{"category", "subcategory", "supplier", "bucket"}
+	grouping := func(grouped ...string) int {
+		mask := 0
+		for i, col := range columns {
+			if !contains(grouped, col) {
+				mask |= 1 << (len(columns) - 1 - i)
+			}
+		}
+		return mask
+	}
+
+	assert.Equal(t, grouping("category"), categoryFacetGroup)
+	assert.Equal(t, grouping("category", "subcategory"), subcategoryFacetGroup)
+	assert.Equal(t, grouping("supplier"), supplierFacetGroup)
+	assert.Equal(t, grouping("bucket"), priceFacetGroup)
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
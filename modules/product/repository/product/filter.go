@@ -0,0 +1,59 @@
+package product
+
+import (
+	"diploma/modules/product/model"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// productListFilter returns the WHERE condition of the product list query
+// over products aliased as p. Offer filters are combined in a single EXISTS
+// so that one supplier has to satisfy all of them.
+func productListFilter(query *model.ProductListQuery) sq.And {
+	cond := sq.And{}
+
+	if query.CategoryID != nil {
+		cond = append(cond, sq.Eq{"p.category_id": *query.CategoryID})
+	}
+	if query.SubcategoryID != nil {
+		cond = append(cond, sq.Eq{"p.subcategory_id": *query.SubcategoryID})
+	}
+	if query.HasOfferFilter() {
+		cond = append(cond, offerExists(query))
+	}
+
+	return cond
+}
+
+func offerExists(query *model.ProductListQuery) sq.Sqlizer {
+	offer := sq.And{
+		sq.Expr("fps." + psProductIDCol + " = p." + pIdCol),
+		sq.Eq{"fps." + psIsAvailableCol: true},
+	}
+
+	if query.MinPrice != nil {
+		offer = append(offer, sq.GtOrEq{"fps." + psPriceCol: *query.MinPrice})
+	}
+	if query.MaxPrice != nil {
+		offer = append(offer, sq.LtOrEq{"fps." + psPriceCol: *query.MaxPrice})
+	}
+	if query.SupplierID != nil {
+		offer = append(offer, sq.Eq{"fps." + psSupplierIDCol: *query.SupplierID})
+	}
+	if query.FreeDeliveryBelow != nil {
+		// suppliers without free delivery have no threshold and never match
+		offer = append(offer, sq.LtOrEq{"fdc." + dcFreeDeliveryAmountCol: *query.FreeDeliveryBelow})
+	}
+	if query.MaxMinOrder != nil {
+		offer = append(offer, sq.LtOrEq{"fs." + sOrderAmountCol: *query.MaxMinOrder})
+	}
+
+	subquery := sq.
+		Select("1").
+		From(productsSupplierTbl + " AS fps").
+		Join(supplierTbl + " AS fs ON fs." + sIDCol + " = fps." + psSupplierIDCol).
+		LeftJoin(deliveryConditionTbl + " AS fdc ON fdc." + dcIDCol + " = fs." + sDeliveryConditionIDCol).
+		Where(offer)
+
+	return sq.Expr("EXISTS (?)", subquery)
+}
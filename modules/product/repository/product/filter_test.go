@@ -0,0 +1,74 @@
+package product
+
+import (
+	"diploma/internal/testutils"
+	"diploma/modules/product/model"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func productListFilterQuery(query *model.ProductListQuery) sq.SelectBuilder {
+	return sq.
+		Select("p.id").
+		From("products AS p").
+		Where(productListFilter(query)).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func TestProductListFilter_NoFilters(t *testing.T) {
+	testutils.AssertSQL(t,
+		productListFilterQuery(&model.ProductListQuery{}),
+		"SELECT p.id FROM products AS p WHERE (1=1)",
+	)
+}
+
+func TestProductListFilter_Category(t *testing.T) {
+	testutils.AssertSQL(t,
+		productListFilterQuery(&model.ProductListQuery{
+			CategoryID:    intPtr(3),
+			SubcategoryID: intPtr(7),
+		}),
+		"SELECT p.id FROM products AS p WHERE (p.category_id = $1 AND p.subcategory_id = $2)",
+		3, 7,
+	)
+}
+
+func TestProductListFilter_OfferFilters(t *testing.T) {
+	supplierID := int64(42)
+
+	testutils.AssertSQL(t,
+		productListFilterQuery(&model.ProductListQuery{
+			CategoryID:        intPtr(3),
+			MinPrice:          intPtr(100),
+			MaxPrice:          intPtr(900),
+			SupplierID:        &supplierID,
+			FreeDeliveryBelow: intPtr(5000),
+			MaxMinOrder:       intPtr(20000),
+		}),
+		"SELECT p.id FROM products AS p WHERE (p.category_id = $1 AND EXISTS ("+
+			"SELECT 1 FROM products_supplier AS fps "+
+			"JOIN suppliers AS fs ON fs.user_id = fps.supplier_id "+
+			"LEFT JOIN delivery_conditions AS fdc ON fdc.condition_id = fs.condition_id "+
+			"WHERE (fps.product_id = p.id AND fps.is_available = $2 "+
+			"AND fps.price >= $3 AND fps.price <= $4 AND fps.supplier_id = $5 "+
+			"AND fdc.minimum_free_delivery_amount <= $6 AND fs.order_amount <= $7)))",
+		3, true, 100, 900, int64(42), 5000, 20000,
+	)
+}
+
+func TestProductListFilter_SingleOfferFilter(t *testing.T) {
+	testutils.AssertSQL(t,
+		productListFilterQuery(&model.ProductListQuery{MaxPrice: intPtr(900)}),
+		"SELECT p.id FROM products AS p WHERE (EXISTS ("+
+			"SELECT 1 FROM products_supplier AS fps "+
+			"JOIN suppliers AS fs ON fs.user_id = fps.supplier_id "+
+			"LEFT JOIN delivery_conditions AS fdc ON fdc.condition_id = fs.condition_id "+
+			"WHERE (fps.product_id = p.id AND fps.is_available = $1 AND fps.price <= $2)))",
+		true, 900,
+	)
+}
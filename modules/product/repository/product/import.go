@@ -0,0 +1,428 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"diploma/modules/product/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// ======== product imports tables ========
+	importsTbl    = "product_imports"
+	importRowsTbl = "product_import_rows"
+
+	// importRowsChunk bounds the rows inserted per statement (10 parameters
+	// each, well below the PostgreSQL limit of 65535).
+	importRowsChunk = 1000
+)
+
+var importRowColumns = []string{
+	"r.import_id",
+	"i.supplier_id",
+	"r.row_no",
+	"r.raw_gtin",
+	"r.gtin",
+	"r.price",
+	"r.sell_amount",
+	"r.stock",
+	"r.category_id",
+	"r.status",
+	"r.error",
+	"r.product_id",
+	"r.lookup_attempts",
+}
+
+// CreateImport saves the import with its rows and returns its ID.
+func (r *repo) CreateImport(ctx context.Context, imp *model.Import, rows []model.ImportRow) (int64, error) {
+	builder := sq.
+		Insert(importsTbl).
+		Columns("supplier_id", "file_name").
+		Values(imp.SupplierID, imp.FileName).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build create import query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.CreateImport",
+		QueryRaw: query,
+	}
+
+	var id int64
+	if err := r.db.DB().QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	for start := 0; start < len(rows); start += importRowsChunk {
+		end := start + importRowsChunk
+		if end > len(rows) {
+			end = len(rows)
+		}
+		if err := r.createImportRows(ctx, id, rows[start:end]); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, nil
+}
+
+func (r *repo) createImportRows(ctx context.Context, importID int64, rows []model.ImportRow) error {
+	builder := sq.
+		Insert(importRowsTbl).
+		Columns(
+			"import_id",
+			"row_no",
+			"raw_gtin",
+			"gtin",
+			"price",
+			"sell_amount",
+			"stock",
+			"category_id",
+			"status",
+			"error",
+		).
+		PlaceholderFormat(sq.Dollar)
+
+	for _, row := range rows {
+		var (
+			gtin, price, sellAmount interface{}
+			rowError                interface{}
+		)
+		// values of failed rows may be partially parsed; keep them NULL
+		if row.Status != model.ImportRowFailed {
+			gtin, price, sellAmount = row.GTIN, row.Price, row.SellAmount
+		} else {
+			rowError = row.Error
+		}
+		builder = builder.Values(
+			importID,
+			row.RowNo,
+			row.RawGTIN,
+			gtin,
+			price,
+			sellAmount,
+			row.Stock,
+			row.CategoryID,
+			row.Status,
+			rowError,
+		)
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build create import rows query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.CreateImportRows",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to create import rows: %w", err)
+	}
+	return nil
+}
+
+// GetImport returns the import with its progress.
+func (r *repo) GetImport(ctx context.Context, id int64) (*model.Import, error) {
+	builder := sq.
+		Select(
+			"i.id",
+			"i.supplier_id",
+			"i.file_name",
+			"i.created_at",
+			"i.finished_at",
+			"COUNT(r.row_no)",
+			"COUNT(r.row_no) FILTER (WHERE r.status = '"+model.ImportRowPending+"')",
+			"COUNT(r.row_no) FILTER (WHERE r.status = '"+model.ImportRowLookup+"')",
+			"COUNT(r.row_no) FILTER (WHERE r.status = '"+model.ImportRowImported+"')",
+			"COUNT(r.row_no) FILTER (WHERE r.status = '"+model.ImportRowFailed+"')",
+		).
+		From(importsTbl + " AS i").
+		LeftJoin(importRowsTbl + " AS r ON r.import_id = i.id").
+		Where(sq.Eq{"i.id": id}).
+		GroupBy("i.id").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build get import query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetImport",
+		QueryRaw: query,
+	}
+
+	var (
+		imp        model.Import
+		finishedAt sql.NullTime
+	)
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(
+		&imp.ID,
+		&imp.SupplierID,
+		&imp.FileName,
+		&imp.CreatedAt,
+		&finishedAt,
+		&imp.Progress.Total,
+		&imp.Progress.Pending,
+		&imp.Progress.Lookup,
+		&imp.Progress.Imported,
+		&imp.Progress.Failed,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+	if finishedAt.Valid {
+		imp.FinishedAt = &finishedAt.Time
+	}
+
+	return &imp, nil
+}
+
+// LockNextImportRow returns the oldest row with the status and locks it,
+// skipping rows locked by other workers.
+func (r *repo) LockNextImportRow(ctx context.Context, status string) (*model.ImportRow, error) {
+	rows, err := r.lockImportRows(ctx, "product_repository.LockNextImportRow",
+		sq.Eq{"r.status": status}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, model.ErrNoRows
+	}
+	return &rows[0], nil
+}
+
+// LockImportRow returns and locks the row if it has the status.
+func (r *repo) LockImportRow(ctx context.Context, importID int64, rowNo int, status string) (*model.ImportRow, error) {
+	rows, err := r.lockImportRows(ctx, "product_repository.LockImportRow",
+		sq.Eq{"r.import_id": importID, "r.row_no": rowNo, "r.status": status}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, model.ErrNoRows
+	}
+	return &rows[0], nil
+}
+
+// LockImportRowsByGTIN returns and locks the rows with the GTIN and the
+// status, skipping rows locked by other workers.
+func (r *repo) LockImportRowsByGTIN(ctx context.Context, gtin int64, status string) ([]model.ImportRow, error) {
+	return r.lockImportRows(ctx, "product_repository.LockImportRowsByGTIN",
+		sq.Eq{"r.gtin": gtin, "r.status": status}, 0)
+}
+
+func (r *repo) lockImportRows(ctx context.Context, name string, cond sq.Sqlizer, limit uint64) ([]model.ImportRow, error) {
+	builder := sq.
+		Select(importRowColumns...).
+		From(importRowsTbl+" AS r").
+		Join(importsTbl+" AS i ON i.id = r.import_id").
+		Where(cond).
+		OrderBy("r.import_id", "r.row_no").
+		PlaceholderFormat(sq.Dollar)
+	if limit > 0 {
+		builder = builder.Limit(limit)
+	}
+	builder = builder.Suffix("FOR UPDATE OF r SKIP LOCKED")
+
+	return r.importRows(ctx, name, builder)
+}
+
+// ImportGTINs returns distinct GTINs of rows with the status whose lookup
+// is due, oldest first.
+func (r *repo) ImportGTINs(ctx context.Context, status string, limit uint64) ([]int64, error) {
+	builder := sq.
+		Select("gtin").
+		From(importRowsTbl).
+		Where(sq.Eq{"status": status}).
+		Where(sq.Expr("(next_lookup_at IS NULL OR next_lookup_at <= now())")).
+		GroupBy("gtin").
+		OrderBy("MIN(import_id)", "MIN(row_no)").
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build import GTINs query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.ImportGTINs",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import GTINs: %w", err)
+	}
+	defer rows.Close()
+
+	var gtins []int64
+	for rows.Next() {
+		var gtin int64
+		if err := rows.Scan(&gtin); err != nil {
+			return nil, fmt.Errorf("failed to scan import GTIN: %w", err)
+		}
+		gtins = append(gtins, gtin)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import GTINs: %w", err)
+	}
+
+	return gtins, nil
+}
+
+// UpdateImportRow saves the status, error, product and lookup schedule of
+// the row.
+func (r *repo) UpdateImportRow(ctx context.Context, row *model.ImportRow) error {
+	var rowError, nextLookupAt interface{}
+	if row.Error != "" {
+		rowError = row.Error
+	}
+	if !row.NextLookupAt.IsZero() {
+		nextLookupAt = row.NextLookupAt
+	}
+
+	builder := sq.
+		Update(importRowsTbl).
+		Set("status", row.Status).
+		Set("error", rowError).
+		Set("product_id", row.ProductID).
+		Set("lookup_attempts", row.LookupAttempts).
+		Set("next_lookup_at", nextLookupAt).
+		Set("updated_at", sq.Expr("now()")).
+		Where(sq.Eq{
+			"import_id": row.ImportID,
+			"row_no":    row.RowNo,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update import row query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.UpdateImportRow",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to update import row: %w", err)
+	}
+	return nil
+}
+
+// FinishImports marks imports without pending or queued rows finished.
+func (r *repo) FinishImports(ctx context.Context) error {
+	builder := sq.
+		Update(importsTbl+" AS i").
+		Set("finished_at", sq.Expr("now()")).
+		Where(sq.Eq{"i.finished_at": nil}).
+		Where(sq.Expr("NOT EXISTS (SELECT 1 FROM " + importRowsTbl + " AS r" +
+			" WHERE r.import_id = i.id AND r.status IN ('" + model.ImportRowPending + "', '" + model.ImportRowLookup + "'))")).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build finish imports query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.FinishImports",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to finish imports: %w", err)
+	}
+	return nil
+}
+
+// FailedImportRows returns the failed rows of the import in file order.
+func (r *repo) FailedImportRows(ctx context.Context, importID int64) ([]model.ImportRow, error) {
+	builder := sq.
+		Select(importRowColumns...).
+		From(importRowsTbl + " AS r").
+		Join(importsTbl + " AS i ON i.id = r.import_id").
+		Where(sq.Eq{
+			"r.import_id": importID,
+			"r.status":    model.ImportRowFailed,
+		}).
+		OrderBy("r.row_no").
+		PlaceholderFormat(sq.Dollar)
+
+	return r.importRows(ctx, "product_repository.FailedImportRows", builder)
+}
+
+func (r *repo) importRows(ctx context.Context, name string, builder sq.SelectBuilder) ([]model.ImportRow, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build import rows query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     name,
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	var result []model.ImportRow
+	for rows.Next() {
+		var (
+			row      model.ImportRow
+			gtin     sql.NullInt64
+			price    sql.NullInt32
+			rowError sql.NullString
+		)
+		err := rows.Scan(
+			&row.ImportID,
+			&row.SupplierID,
+			&row.RowNo,
+			&row.RawGTIN,
+			&gtin,
+			&price,
+			&row.SellAmount,
+			&row.Stock,
+			&row.CategoryID,
+			&row.Status,
+			&rowError,
+			&row.ProductID,
+			&row.LookupAttempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		row.GTIN = gtin.Int64
+		row.Price = int(price.Int32)
+		row.Error = rowError.String
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating import rows: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,175 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// ======== daily market aggregates ========
+	productPriceDailyTbl = "product_price_daily"
+	ppdDayCol            = "day"
+	ppdProductIDCol      = "product_id"
+	ppdAvgPriceCol       = "avg_price"
+	ppdVolumeCol         = "volume"
+
+	// priceAggregatesLockKey is the advisory lock serializing the
+	// aggregate refreshes of all instances.
+	priceAggregatesLockKey = 20250905
+)
+
+var (
+	dailyPriceFrom         = productPriceDailyTbl + " AS d"
+	dailyPriceProductJoin  = productsTbl + " AS p ON p." + pIdCol + " = d." + ppdProductIDCol
+	dailyPriceCategoryJoin = "categories AS c ON c.id = p.category_id"
+)
+
+// TryLockPriceAggregates takes the transaction-level lock on the daily
+// market aggregates. It reports false without waiting when another
+// transaction holds it.
+func (r *repo) TryLockPriceAggregates(ctx context.Context) (bool, error) {
+	builder := sq.
+		Select().
+		Column(sq.Expr("pg_try_advisory_xact_lock(?)", priceAggregatesLockKey)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build lock price aggregates query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.TryLockPriceAggregates",
+		QueryRaw: query,
+	}
+
+	var locked bool
+	if err := r.db.DB().QueryRowContext(ctx, q, args...).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to lock price aggregates: %w", err)
+	}
+	return locked, nil
+}
+
+// RefreshPriceAggregates recomputes the daily market aggregates from the
+// day of from up to today.
+func (r *repo) RefreshPriceAggregates(ctx context.Context, from time.Time) error {
+	builder := sq.
+		Select().
+		Column(sq.Expr("refresh_product_price_daily(?::date)", from)).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build refresh price aggregates query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.RefreshPriceAggregates",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to refresh price aggregates: %w", err)
+	}
+	return nil
+}
+
+// GetDailyPrices returns the daily aggregates since from, optionally of
+// one category, ordered by day and product.
+func (r *repo) GetDailyPrices(ctx context.Context, from time.Time, categoryID *int) ([]model.DailyPrice, error) {
+	builder := sq.
+		Select(
+			"d."+ppdDayCol,
+			"d."+ppdProductIDCol,
+			"p."+pNameCol,
+			"COALESCE(p.category_id, 0)",
+			"COALESCE(c.name, '')",
+			"COALESCE(d."+ppdAvgPriceCol+", 0)::float8",
+			"d."+ppdVolumeCol,
+		).
+		From(dailyPriceFrom).
+		Join(dailyPriceProductJoin).
+		LeftJoin(dailyPriceCategoryJoin).
+		Where(sq.GtOrEq{"d." + ppdDayCol: from}).
+		OrderBy("d."+ppdDayCol, "d."+ppdProductIDCol).
+		PlaceholderFormat(sq.Dollar)
+
+	if categoryID != nil {
+		builder = builder.Where(sq.Eq{"p.category_id": *categoryID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build get daily prices query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetDailyPrices",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily prices: %w", err)
+	}
+	defer rows.Close()
+
+	var prices []model.DailyPrice
+	for rows.Next() {
+		var p model.DailyPrice
+		if err := rows.Scan(&p.Day, &p.ProductID, &p.ProductName, &p.CategoryID, &p.CategoryName, &p.AvgPrice, &p.Volume); err != nil {
+			return nil, fmt.Errorf("failed to scan daily price: %w", err)
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read daily prices: %w", err)
+	}
+
+	return prices, nil
+}
+
+// GetCategoryByName finds a category by its name, ignoring case.
+func (r *repo) GetCategoryByName(ctx context.Context, name string) (*model.Category, error) {
+	builder := sq.
+		Select("id", "name", "COALESCE(description, '')", "created_at", "updated_at").
+		From("categories").
+		Where(sq.Expr("lower(name) = lower(?)", name)).
+		OrderBy("id").
+		Limit(1).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build get category by name query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetCategoryByName",
+		QueryRaw: query,
+	}
+
+	var category model.Category
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(
+		&category.ID,
+		&category.Name,
+		&category.Description,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to get category by name: %w", err)
+	}
+
+	return &category, nil
+}
@@ -14,11 +14,25 @@ type Product struct {
 	CategoryName    *string
 	SubcategoryName *string
 	LowestSupplier  ProductSupplier
+	Attributes      ProductAttributes
+}
+
+// ProductAttributes are nullable products columns.
+type ProductAttributes struct {
+	Description       *string
+	Brand             *string
+	Manufacturer      *string
+	Weight            *string
+	Country           *string
+	Unit              *string
+	ShelfLifeDays     *int
+	StorageConditions *string
 }
 
 type ProductSupplier struct {
 	Price      *int
 	SellAmount *int
+	Available  *bool
 	Supplier   Supplier
 }
 
@@ -0,0 +1,174 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	psIsAvailableCol = "is_available"
+	psStockCol       = "stock"
+	psUpdatedAtCol   = "updated_at"
+
+	// ======== price history table ========
+	priceHistoryTbl   = "price_history"
+	phProductIDCol    = "product_id"
+	phSupplierIDCol   = "supplier_id"
+	phPriceCol        = "price"
+	phDateCol         = "date"
+	phChangeReasonCol = "change_reason"
+)
+
+// LockProductSupplier returns the supplier's offer of the product and locks
+// it until the end of the transaction.
+func (r *repo) LockProductSupplier(ctx context.Context, productID, supplierID int64) (*model.Offer, error) {
+	builder := sq.
+		Select(psPriceCol, psSellAmountCol, psStockCol, psIsAvailableCol).
+		From(productsSupplierTbl).
+		Where(sq.Eq{
+			psProductIDCol:  productID,
+			psSupplierIDCol: supplierID,
+		}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build lock product supplier query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.LockProductSupplier",
+		QueryRaw: query,
+	}
+
+	offer := model.Offer{
+		ProductID:  productID,
+		SupplierID: supplierID,
+	}
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&offer.Price, &offer.SellAmount, &offer.Stock, &offer.Available)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrNoRows
+		}
+		return nil, fmt.Errorf("failed to lock product supplier: %w", err)
+	}
+
+	return &offer, nil
+}
+
+// UpdateProductSupplier saves price, minimum sell amount, stock and
+// availability of the offer.
+func (r *repo) UpdateProductSupplier(ctx context.Context, offer *model.Offer) error {
+	builder := sq.
+		Update(productsSupplierTbl).
+		Set(psPriceCol, offer.Price).
+		Set(psSellAmountCol, offer.SellAmount).
+		Set(psStockCol, offer.Stock).
+		Set(psIsAvailableCol, offer.Available).
+		Set(psUpdatedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{
+			psProductIDCol:  offer.ProductID,
+			psSupplierIDCol: offer.SupplierID,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build update product supplier query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.UpdateProductSupplier",
+		QueryRaw: query,
+	}
+
+	tag, err := r.db.DB().ExecContext(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update product supplier: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNoRows
+	}
+	return nil
+}
+
+// CreateOffer inserts a new supplier offer.
+func (r *repo) CreateOffer(ctx context.Context, offer *model.Offer) error {
+	builder := sq.
+		Insert(productsSupplierTbl).
+		Columns(
+			psProductIDCol,
+			psSupplierIDCol,
+			psPriceCol,
+			psSellAmountCol,
+			psStockCol,
+			psIsAvailableCol,
+		).
+		Values(
+			offer.ProductID,
+			offer.SupplierID,
+			offer.Price,
+			offer.SellAmount,
+			offer.Stock,
+			offer.Available,
+		).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build create offer query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.CreateOffer",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to create offer: %w", err)
+	}
+	return nil
+}
+
+// CreatePriceChange records a price change in the price history.
+func (r *repo) CreatePriceChange(ctx context.Context, change *model.PriceChange) error {
+	builder := sq.
+		Insert(priceHistoryTbl).
+		Columns(
+			phProductIDCol,
+			phSupplierIDCol,
+			phPriceCol,
+			phDateCol,
+			phChangeReasonCol,
+		).
+		Values(
+			change.ProductID,
+			change.SupplierID,
+			change.Price,
+			change.Date,
+			change.Reason,
+		).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build create price change query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.CreatePriceChange",
+		QueryRaw: query,
+	}
+
+	if _, err := r.db.DB().ExecContext(ctx, q, args...); err != nil {
+		return fmt.Errorf("failed to create price change: %w", err)
+	}
+	return nil
+}
@@ -7,10 +7,12 @@ import (
 	"diploma/modules/product/repository/product/converter"
 	repoModel "diploma/modules/product/repository/product/model"
 	"diploma/pkg/client/db"
+	"errors"
 	"fmt"
 	"strings"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
 )
 
 const (
@@ -23,6 +25,7 @@ const (
 	pGTINCol             = "gtin"
 	pLowestSupplierIDCol = "lowest_supplier_id"
 	pCreatedAtCol        = "created_at"
+	pCreatedByCol        = "created_by"
 	pUpdatedAtCol        = "updated_at"
 
 	// ======== product-supplier table ========
@@ -46,6 +49,15 @@ const (
 	dcDeliveryFeeCol        = "delivery_fee"
 )
 
+// productSorts maps model.ProductSort* values to ORDER BY clauses of the
+// product list query. Ties are broken by product ID to keep pages stable.
+var productSorts = map[string][]string{
+	model.ProductSortDefault: {"p." + pIdCol},
+	model.ProductSortPrice:   {"ps." + psPriceCol + " ASC NULLS LAST", "p." + pIdCol},
+	model.ProductSortName:    {"p." + pNameCol, "p." + pIdCol},
+	model.ProductSortNewest:  {"p." + pCreatedAtCol + " DESC", "p." + pIdCol + " DESC"},
+}
+
 type repo struct {
 	db db.Client
 }
@@ -75,6 +87,7 @@ func (r *repo) GetProduct(ctx context.Context, id int64) (*model.Product, error)
 			"dc."+dcFreeDeliveryAmountCol+" AS dc_min_free_delivery_amount",
 			"dc."+dcDeliveryFeeCol+" AS dc_delivery_fee",
 		).
+		Columns(prefixed("p", attributeColumns)...).
 		From(productsTbl + " AS p").
 		LeftJoin("categories AS c ON p.category_id = c.id").
 		LeftJoin("subcategories AS sc ON p.subcategory_id = sc.id").
@@ -99,7 +112,7 @@ func (r *repo) GetProduct(ctx context.Context, id int64) (*model.Product, error)
 	var ps repoModel.ProductSupplier
 	var s repoModel.Supplier
 
-	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(
+	dest := []interface{}{
 		&product.ID,
 		&product.Name,
 		&product.ImageUrl,
@@ -116,9 +129,10 @@ func (r *repo) GetProduct(ctx context.Context, id int64) (*model.Product, error)
 		&s.OrderAmount,
 		&s.FreeDeliveryAmount,
 		&s.DeliveryFee,
-	)
+	}
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(append(dest, attributeDest(&product.Attributes)...)...)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, model.ErrNoRows
 		}
 		return nil, fmt.Errorf("failed to get product: %w", err)
@@ -159,16 +173,9 @@ func (r *repo) GetProductList(ctx context.Context, queryParam *model.ProductList
 		LeftJoin(supplierTbl + " AS s ON s." + sIDCol + " = ps." + psSupplierIDCol).
 		LeftJoin(deliveryConditionTbl + " AS dc ON dc." + dcIDCol + " = s." + sDeliveryConditionIDCol)
 
-	if queryParam.CategoryID != nil {
-		builder = builder.Where(sq.Eq{"p.category_id": *queryParam.CategoryID})
-	}
-
-	if queryParam.SubcategoryID != nil {
-		builder = builder.Where(sq.Eq{"p.subcategory_id": *queryParam.SubcategoryID})
-	}
-
 	builder = builder.
-		OrderBy("p." + pIdCol).
+		Where(productListFilter(queryParam)).
+		OrderBy(productSorts[queryParam.Sort]...).
 		Limit(uint64(queryParam.Limit)).
 		Offset(uint64(queryParam.Offset)).
 		PlaceholderFormat(sq.Dollar)
@@ -304,7 +311,10 @@ func (r *repo) GetSupplierProductListByProduct(ctx context.Context, id int64) ([
 		From(productsSupplierTbl + " AS ps").
 		Join(supplierTbl + " AS s ON s." + sIDCol + " = ps." + psSupplierIDCol).
 		LeftJoin(deliveryConditionTbl + " AS dc ON dc." + dcIDCol + " = s." + sDeliveryConditionIDCol).
-		Where(sq.Eq{psProductIDCol: id}).
+		Where(sq.Eq{
+			"ps." + psProductIDCol:   id,
+			"ps." + psIsAvailableCol: true,
+		}).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := builder.ToSql()
@@ -349,11 +359,12 @@ func (r *repo) GetSupplierProductListByProduct(ctx context.Context, id int64) ([
 	return results, nil
 }
 
-// GetTotalProducts returns the total number of products
-func (r *repo) GetTotalProducts(ctx context.Context) (int, error) {
+// GetTotalProducts returns the number of products matching the list filters
+func (r *repo) GetTotalProducts(ctx context.Context, queryParam *model.ProductListQuery) (int, error) {
 	builder := sq.
 		Select("COUNT(*)").
-		From(productsTbl).
+		From(productsTbl + " AS p").
+		Where(productListFilter(queryParam)).
 		PlaceholderFormat(sq.Dollar)
 
 	query, args, err := builder.ToSql()
@@ -383,6 +394,7 @@ func (r *repo) GetProductPriceBySupplier(ctx context.Context, productID, supplie
 		Where(sq.And{
 			sq.Eq{psProductIDCol: productID},
 			sq.Eq{psSupplierIDCol: supplierID},
+			sq.Eq{psIsAvailableCol: true},
 		}).
 		PlaceholderFormat(sq.Dollar)
 
@@ -399,7 +411,7 @@ func (r *repo) GetProductPriceBySupplier(ctx context.Context, productID, supplie
 	var price int
 	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&price)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return 0, model.ErrNoRows
 		}
 		return 0, fmt.Errorf("failed to get product price: %w", err)
@@ -407,7 +419,8 @@ func (r *repo) GetProductPriceBySupplier(ctx context.Context, productID, supplie
 	return price, nil
 }
 
-// GetProductByGTIN retrieves a product by its GTIN
+// GetProductByGTIN retrieves a product by its GTIN-14 number (see
+// model.NormalizeGTIN).
 func (r *repo) GetProductByGTIN(ctx context.Context, gtin int64) (*model.Product, error) {
 	builder := sq.
 		Select(
@@ -442,7 +455,7 @@ func (r *repo) GetProductByGTIN(ctx context.Context, gtin int64) (*model.Product
 		&product.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, model.ErrNoRows
 		}
 		return nil, fmt.Errorf("failed to get product by GTIN: %w", err)
@@ -463,8 +476,10 @@ func (r *repo) CreateProduct(ctx context.Context, product *model.Product) (int64
 			"subcategory_id",
 			pCreatedAtCol,
 			pUpdatedAtCol,
+			pCreatedByCol,
 		).
-		Values(
+		Columns(attributeColumns...).
+		Values(append([]interface{}{
 			product.Name,
 			product.ImageUrl,
 			product.GTIN,
@@ -472,7 +487,8 @@ func (r *repo) CreateProduct(ctx context.Context, product *model.Product) (int64
 			product.SubcategoryID,
 			product.CreatedAt,
 			product.UpdatedAt,
-		).
+			product.CreatedBy,
+		}, attributeValues(&product.ProductAttributes)...)...).
 		Suffix(fmt.Sprintf("RETURNING %s", pIdCol)).
 		PlaceholderFormat(sq.Dollar)
 
@@ -539,6 +555,7 @@ func (r *repo) GetProductListBySupplier(ctx context.Context, supplierID int64, l
 		"p."+pGTINCol+" AS product_gtin",
 		"ps."+psPriceCol+" AS ps_price",
 		"ps."+psSellAmountCol+" AS ps_sell_amount",
+		"ps."+psIsAvailableCol+" AS ps_is_available",
 		"s."+sNameCol+" AS supplier_name",
 		"s."+sOrderAmountCol+" AS supplier_order_amount",
 		"dc."+dcFreeDeliveryAmountCol+" AS dc_min_free_delivery_amount",
@@ -591,6 +608,7 @@ func (r *repo) GetProductListBySupplier(ctx context.Context, supplierID int64, l
 			&p.GTIN,
 			&ps.Price,
 			&ps.SellAmount,
+			&ps.Available,
 			&s.Name,
 			&s.OrderAmount,
 			&s.FreeDeliveryAmount,
@@ -807,7 +825,7 @@ func (r *repo) GetCategories(ctx context.Context) ([]model.Category, error) {
 // GetCategory retrieves a single category by ID
 func (r *repo) GetCategory(ctx context.Context, id int) (*model.Category, error) {
 	builder := sq.
-		Select("id", "name", "description", "created_at", "updated_at").
+		Select("id", "name", "COALESCE(description, '')", "created_at", "updated_at").
 		From("categories").
 		Where(sq.Eq{"id": id}).
 		PlaceholderFormat(sq.Dollar)
@@ -831,7 +849,7 @@ func (r *repo) GetCategory(ctx context.Context, id int) (*model.Category, error)
 		&category.UpdatedAt,
 	)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, model.ErrNoRows
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
@@ -888,3 +906,22 @@ func (r *repo) GetSubcategories(ctx context.Context, categoryID int) ([]model.Su
 
 	return subcategories, nil
 }
+
+// RecomputeLowestPrices recalculates products.lowest_price and
+// lowest_supplier_id of every product from products_supplier. The columns
+// are maintained by a trigger; this repairs them after bulk changes made
+// with the trigger disabled. Returns the number of products changed.
+func (r *repo) RecomputeLowestPrices(ctx context.Context) (int, error) {
+	q := db.Query{
+		Name:     "product_repository.RecomputeLowestPrices",
+		QueryRaw: "SELECT recompute_lowest_prices()",
+	}
+
+	var changed int
+	err := r.db.DB().QueryRowContext(ctx, q).Scan(&changed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recompute lowest prices: %w", err)
+	}
+
+	return changed, nil
+}
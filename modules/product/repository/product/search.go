@@ -0,0 +1,185 @@
+package product
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"diploma/modules/product/repository/product/converter"
+	repoModel "diploma/modules/product/repository/product/model"
+	"diploma/pkg/client/db"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const (
+	// tsQueryExpr matches stemmed Russian/Latin words as well as unstemmed
+	// (e.g. Kazakh) ones, mirroring how products.search_vector is built.
+	tsQueryExpr = "(websearch_to_tsquery('product_search', ?) || websearch_to_tsquery('simple', ?))"
+	// trgmNameExpr must match the expression of idx_products_name_brand_trgm.
+	trgmNameExpr = "translate(lower(p." + pNameCol + " || coalesce(' ' || p." + pBrandCol + ", '')), 'ё', 'е')"
+
+	// gtinMatchRank puts an exact GTIN match above any name match.
+	gtinMatchRank = 100
+)
+
+// searchCondition returns the WHERE condition and the rank expression of a
+// product search. A product matches by full-text search, by trigram word
+// similarity (typos) of its name or brand or, when the query is a GTIN, by
+// exact GTIN.
+func searchCondition(query *model.ProductSearchQuery) (sq.Sqlizer, sq.Sqlizer) {
+	text := query.Normalized()
+
+	cond := sq.Or{
+		sq.Expr("p.search_vector @@ "+tsQueryExpr, text, text),
+		sq.Expr("? <% "+trgmNameExpr, text),
+	}
+	rank := "ts_rank_cd(p.search_vector, " + tsQueryExpr + ") + word_similarity(?, " + trgmNameExpr + ")"
+	rankArgs := []interface{}{text, text, text}
+
+	if gtin, ok := query.GTIN(); ok {
+		cond = append(cond, sq.Eq{"p." + pGTINCol: gtin})
+		rank = fmt.Sprintf("CASE WHEN p.%s = ? THEN %d ELSE 0 END + %s", pGTINCol, gtinMatchRank, rank)
+		rankArgs = append([]interface{}{gtin}, rankArgs...)
+	}
+
+	return cond, sq.Expr(rank, rankArgs...)
+}
+
+// SearchProducts returns products matching the search query, best match
+// first, with the matched words of the name highlighted.
+func (r *repo) SearchProducts(ctx context.Context, query *model.ProductSearchQuery) ([]model.ProductSearchResult, error) {
+	cond, rank := searchCondition(query)
+	text := query.Normalized()
+	headline := sq.Expr("ts_headline('product_search', p."+pNameCol+", "+tsQueryExpr+
+		", 'StartSel=<b>, StopSel=</b>, HighlightAll=true')", text, text)
+
+	builder := sq.
+		Select(
+			"p."+pIdCol,
+			"p."+pNameCol,
+			"p."+pImageUrlCol,
+			"p."+pGTINCol,
+			"p."+pCreatedAtCol,
+			"p."+pUpdatedAtCol,
+			"p.category_id",
+			"p.subcategory_id",
+			"c.name AS category_name",
+			"sc.name AS subcategory_name",
+			"ps."+psPriceCol+" AS ps_price",
+			"ps."+psSellAmountCol+" AS ps_sell_amount",
+			"s."+sNameCol+" AS supplier_name",
+			"s."+sOrderAmountCol+" AS supplier_order_amount",
+			"dc."+dcFreeDeliveryAmountCol+" AS dc_min_free_delivery_amount",
+			"dc."+dcDeliveryFeeCol+" AS dc_delivery_fee",
+		).
+		From(productsTbl + " AS p").
+		LeftJoin("categories AS c ON p.category_id = c.id").
+		LeftJoin("subcategories AS sc ON p.subcategory_id = sc.id").
+		LeftJoin(productsSupplierTbl + " AS ps ON ps." + psProductIDCol + " = p." + pIdCol +
+			" AND ps." + psSupplierIDCol + " = p." + pLowestSupplierIDCol).
+		LeftJoin(supplierTbl + " AS s ON s." + sIDCol + " = ps." + psSupplierIDCol).
+		LeftJoin(deliveryConditionTbl + " AS dc ON dc." + dcIDCol + " = s." + sDeliveryConditionIDCol)
+
+	builder = builder.
+		Column(sq.Alias(headline, "highlight")).
+		Column(sq.Alias(rank, "rank")).
+		Where(cond).
+		OrderBy("rank DESC", "p."+pIdCol).
+		Limit(uint64(query.Limit)).
+		Offset(uint64(query.Offset)).
+		PlaceholderFormat(sq.Dollar)
+
+	sqlQuery, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build search products query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.SearchProducts",
+		QueryRaw: sqlQuery,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer rows.Close()
+
+	var results []model.ProductSearchResult
+	for rows.Next() {
+		var (
+			product   repoModel.Product
+			ps        repoModel.ProductSupplier
+			s         repoModel.Supplier
+			highlight string
+			score     float64
+		)
+
+		err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.ImageUrl,
+			&product.GTIN,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+			&product.CategoryID,
+			&product.SubcategoryID,
+			&product.CategoryName,
+			&product.SubcategoryName,
+			&ps.Price,
+			&ps.SellAmount,
+			&s.Name,
+			&s.OrderAmount,
+			&s.FreeDeliveryAmount,
+			&s.DeliveryFee,
+			&highlight,
+			&score,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan product: %w", err)
+		}
+
+		ps.Supplier = s
+		product.LowestSupplier = ps
+		results = append(results, model.ProductSearchResult{
+			Product:   *converter.ToProductFromRepo(product),
+			Highlight: highlight,
+			Rank:      score,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating products: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetTotalSearchProducts returns the number of products matching the search query
+func (r *repo) GetTotalSearchProducts(ctx context.Context, query *model.ProductSearchQuery) (int, error) {
+	cond, _ := searchCondition(query)
+
+	builder := sq.
+		Select("COUNT(*)").
+		From(productsTbl + " AS p").
+		Where(cond).
+		PlaceholderFormat(sq.Dollar)
+
+	sqlQuery, args, err := builder.ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build get total search products query: %w", err)
+	}
+
+	q := db.Query{
+		Name:     "product_repository.GetTotalSearchProducts",
+		QueryRaw: sqlQuery,
+	}
+
+	var total int
+	err = r.db.DB().QueryRowContext(ctx, q, args...).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total search products: %w", err)
+	}
+
+	return total, nil
+}
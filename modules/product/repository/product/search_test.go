@@ -0,0 +1,51 @@
+package product
+
+import (
+	"diploma/internal/testutils"
+	"diploma/modules/product/model"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+const testTrgmName = "translate(lower(p.name || coalesce(' ' || p.brand, '')), 'ё', 'е')"
+
+func searchConditionQuery(query *model.ProductSearchQuery) sq.SelectBuilder {
+	cond, rank := searchCondition(query)
+	return sq.
+		Select("p.id").
+		Column(sq.Alias(rank, "rank")).
+		From("products AS p").
+		Where(cond).
+		PlaceholderFormat(sq.Dollar)
+}
+
+func TestSearchCondition(t *testing.T) {
+	testutils.AssertSQL(t,
+		searchConditionQuery(&model.ProductSearchQuery{Query: "  Молоко Ёлки "}),
+		"SELECT p.id, "+
+			"(ts_rank_cd(p.search_vector, (websearch_to_tsquery('product_search', $1) || websearch_to_tsquery('simple', $2))) "+
+			"+ word_similarity($3, "+testTrgmName+")) AS rank "+
+			"FROM products AS p "+
+			"WHERE (p.search_vector @@ (websearch_to_tsquery('product_search', $4) || websearch_to_tsquery('simple', $5)) "+
+			"OR $6 <% "+testTrgmName+")",
+		"молоко елки", "молоко елки", "молоко елки", "молоко елки", "молоко елки", "молоко елки",
+	)
+}
+
+func TestSearchCondition_GTIN(t *testing.T) {
+	testutils.AssertSQL(t,
+		searchConditionQuery(&model.ProductSearchQuery{Query: "4870001234567"}),
+		"SELECT p.id, "+
+			"(CASE WHEN p.gtin = $1 THEN 100 ELSE 0 END + "+
+			"ts_rank_cd(p.search_vector, (websearch_to_tsquery('product_search', $2) || websearch_to_tsquery('simple', $3))) "+
+			"+ word_similarity($4, "+testTrgmName+")) AS rank "+
+			"FROM products AS p "+
+			"WHERE (p.search_vector @@ (websearch_to_tsquery('product_search', $5) || websearch_to_tsquery('simple', $6)) "+
+			"OR $7 <% "+testTrgmName+" OR p.gtin = $8)",
+		int64(4870001234567),
+		"4870001234567", "4870001234567", "4870001234567",
+		"4870001234567", "4870001234567", "4870001234567",
+		int64(4870001234567),
+	)
+}
@@ -11,6 +11,9 @@ func RegisterRoutes(router *gin.RouterGroup, h *handler.CatalogHandler) {
 	catalogRoutes := router.Group("product")
 	{
 		catalogRoutes.GET("/list", h.GetProductList)
+		catalogRoutes.GET("/search", h.SearchProducts)
+		catalogRoutes.GET("/category/:category_id", h.GetProductsByCategory)
+		catalogRoutes.GET("/subcategory/:subcategory_id", h.GetProductsBySubcategory)
 		catalogRoutes.GET("/market/analytics", h.GetMarketAnalytics)
 		catalogRoutes.GET("/:id", h.GetProduct)
 		catalogRoutes.GET("/:id/analytics", h.GetPriceAnalytics)
@@ -23,5 +26,11 @@ func RegisterSecureRoutes(router *gin.RouterGroup, h *handler.CatalogHandler) {
 	{
 		catalogRoutes.POST("", h.AddProduct)
 		catalogRoutes.GET("/list/supplier", h.GetProductListBySupplier)
+		catalogRoutes.POST("/import", h.ImportOffers)
+		catalogRoutes.GET("/import/:id", h.GetImport)
+		catalogRoutes.GET("/import/:id/errors", h.GetImportErrors)
+		catalogRoutes.PATCH("/:id", h.UpdateProductAttributes)
+		catalogRoutes.PATCH("/:id/offer", h.UpdateOffer)
+		catalogRoutes.DELETE("/:id/offer", h.DeactivateOffer)
 	}
 }
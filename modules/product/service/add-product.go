@@ -3,8 +3,8 @@ package service
 import (
 	"context"
 	"diploma/modules/product/model"
+	"errors"
 	"fmt"
-	"strconv"
 
 	"go.uber.org/zap"
 )
@@ -16,17 +16,27 @@ func (s *ProductService) AddProduct(ctx context.Context, req *model.AddProductSu
 		zap.Float64("price", req.Price),
 	)
 
-	// Use transaction for the entire operation
-	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
-		// First, try to find the product in our database by GTIN
-		gtin, err := strconv.ParseInt(req.GTIN, 10, 64)
-		if err != nil {
-			s.LogError(ctx, "Failed to parse GTIN", err)
-			return fmt.Errorf("invalid GTIN format: %w", err)
-		}
+	gtin, err := model.NormalizeGTIN(req.GTIN)
+	if err != nil {
+		s.LogWarn(ctx, "Invalid GTIN", zap.String("gtin", req.GTIN), zap.Error(err))
+		return err
+	}
 
-		// Try to find the product in our database by GTIN
-		product, err := s.productRepository.GetProductByGTIN(ctx, gtin)
+	// Fetch an unknown product from NCT before opening the transaction:
+	// the lookup drives a browser and takes seconds.
+	var parsed *model.Product
+	_, err = s.getProductByGTIN(ctx, gtin)
+	if errors.Is(err, model.ErrNoRows) {
+		s.LogInfo(ctx, "Product not found in database, fetching from NCT")
+		parsed, err = s.lookupProduct(ctx, gtin)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		// Check again: the product may have been added during the lookup
+		product, err := s.getProductByGTIN(ctx, gtin)
 
 		if err == nil {
 			s.LogInfo(ctx, "Product already exists in the database",
@@ -42,20 +52,16 @@ func (s *ProductService) AddProduct(ctx context.Context, req *model.AddProductSu
 			}
 			return nil
 		}
-
-		// If product not found in our database, try to fetch it from NCT
-		s.LogInfo(ctx, "Product not found in database, fetching from NCT")
-		productParsed, err := s.nctParser.ParseProductByGTIN(req.GTIN)
-		if err != nil {
-			s.LogError(ctx, "Failed to parse product from NCT", err)
-			return fmt.Errorf("failed to parse product from NCT: %w", err)
+		if !errors.Is(err, model.ErrNoRows) {
+			return err
 		}
-		if len(productParsed) == 0 {
-			s.LogError(ctx, "Product not found in NCT", err)
-			return fmt.Errorf("product not found in NCT")
+		if parsed == nil {
+			// deleted after the first check; nothing was fetched for it
+			return fmt.Errorf("product with GTIN %s was removed concurrently", req.GTIN)
 		}
 
-		product = &productParsed[0]
+		product = parsed
+		product.CreatedBy = &req.SupplierID
 
 		// Handle categories intelligently
 		err = s.handleCategories(ctx, product, req)
@@ -64,12 +70,6 @@ func (s *ProductService) AddProduct(ctx context.Context, req *model.AddProductSu
 			return fmt.Errorf("failed to handle categories: %w", err)
 		}
 
-		s.LogInfo(ctx, "Fetched product from NCT",
-			zap.Int64("gtin", product.GTIN),
-			zap.String("name", product.Name),
-			zap.Any("category_id", product.CategoryID),
-			zap.Any("subcategory_id", product.SubcategoryID))
-
 		// Create the product in our database
 		id, err := s.productRepository.CreateProduct(ctx, product)
 		if err != nil {
@@ -88,6 +88,56 @@ func (s *ProductService) AddProduct(ctx context.Context, req *model.AddProductSu
 	})
 }
 
+// getProductByGTIN finds the product with the GTIN. Invalid GTINs match
+// nothing, not even products flagged with one.
+func (s *ProductService) getProductByGTIN(ctx context.Context, gtin int64) (*model.Product, error) {
+	if !model.ValidGTIN(gtin) {
+		return nil, model.ErrInvalidGTIN
+	}
+	return s.productRepository.GetProductByGTIN(ctx, gtin)
+}
+
+// lookupProduct fetches the product from NCT.
+func (s *ProductService) lookupProduct(ctx context.Context, gtin int64) (*model.Product, error) {
+	productParsed, err := s.nctClient.Lookup(ctx, model.LookupGTIN(gtin))
+	if err != nil {
+		s.LogError(ctx, "Failed to parse product from NCT", err)
+		return nil, fmt.Errorf("failed to parse product from NCT: %w", err)
+	}
+	product := matchGTIN(productParsed, gtin)
+	if product == nil {
+		s.LogWarn(ctx, "Product not found in NCT", zap.Int64("gtin", gtin))
+		return nil, fmt.Errorf("product not found in NCT")
+	}
+
+	s.LogInfo(ctx, "Fetched product from NCT",
+		zap.Int64("gtin", product.GTIN),
+		zap.String("name", product.Name),
+		zap.String("brand", product.Brand),
+		zap.String("category_name", product.CategoryName),
+		zap.String("subcategory_name", product.SubcategoryName))
+	return product, nil
+}
+
+// matchGTIN picks the product with the searched GTIN among NCT matches. A
+// match without a GTIN is taken only when none has the searched one; it
+// gets the GTIN set. Nil means NCT does not know the GTIN.
+func matchGTIN(products []model.Product, gtin int64) *model.Product {
+	var product *model.Product
+	for i := range products {
+		if products[i].GTIN == gtin {
+			return &products[i]
+		}
+		if products[i].GTIN == 0 && product == nil {
+			product = &products[i]
+		}
+	}
+	if product != nil {
+		product.GTIN = gtin
+	}
+	return product
+}
+
 // handleCategories handles category resolution - either from request or from NCT data
 func (s *ProductService) handleCategories(ctx context.Context, product *model.Product, req *model.AddProductSupplier) error {
 	// If supplier provided category IDs, use them (override NCT data)
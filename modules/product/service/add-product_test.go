@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"diploma/internal/testutils"
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// outsideTx matches contexts that are not in a transaction.
+var outsideTx = mock.MatchedBy(func(ctx context.Context) bool { return !testutils.InTx(ctx) })
+
+func (s *ProductServiceTestSuite) TestAddProduct_LooksUpNCTOutsideTransaction() {
+	gtin := int64(4870001234560)
+	s.repo.On("GetProductByGTIN", mock.Anything, gtin).Return(nil, model.ErrNoRows).Twice()
+	s.nctClient.On("Lookup", outsideTx, "4870001234560").Return([]model.Product{
+		{Name: "Молоко пастеризованное 3,2% 1 л", GTIN: gtin, ProductAttributes: model.ProductAttributes{Brand: "Моё"}},
+	}, nil).Once()
+	s.repo.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *model.Product) bool {
+		return p.GTIN == gtin && p.Brand == "Моё" && p.CategoryID != nil && *p.CategoryID == 1 &&
+			p.CreatedBy != nil && *p.CreatedBy == 10
+	})).Return(int64(101), nil).Once()
+	s.repo.On("CreateProductSupplier", mock.Anything, int64(10), int64(101), 420).Return(nil).Once()
+
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "4870001234560",
+		Price:      420,
+		SupplierID: 10,
+		CategoryID: intPtr(1),
+	})
+
+	s.helper.AssertNoError(err)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_KnownProductIsNotLookedUp() {
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234577)).
+		Return(&model.Product{ID: 2, GTIN: 4870001234577}, nil).Twice()
+	s.repo.On("CreateProductSupplier", mock.Anything, int64(10), int64(2), 300).Return(nil).Once()
+
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "4870001234577",
+		Price:      300,
+		SupplierID: 10,
+	})
+
+	s.helper.AssertNoError(err)
+	s.nctClient.AssertNotCalled(s.T(), "Lookup", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_GTINFormsOfKnownProduct() {
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234577)).
+		Return(&model.Product{ID: 2, GTIN: 4870001234577}, nil).Times(4)
+	s.repo.On("CreateProductSupplier", mock.Anything, int64(10), int64(2), 300).Return(nil).Once()
+	s.repo.On("CreateProductSupplier", mock.Anything, int64(11), int64(2), 300).Return(nil).Once()
+
+	for supplierID, gtin := range map[int64]string{10: "4870001234577", 11: "04870001234577"} {
+		err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+			GTIN:       gtin,
+			Price:      300,
+			SupplierID: supplierID,
+		})
+		s.helper.AssertNoError(err)
+	}
+	s.nctClient.AssertNotCalled(s.T(), "Lookup", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_NotFoundInNCT() {
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870009999997)).Return(nil, model.ErrNoRows).Once()
+	s.nctClient.On("Lookup", mock.Anything, "4870009999997").Return([]model.Product{}, nil).Once()
+
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "4870009999997",
+		Price:      300,
+		SupplierID: 10,
+	})
+
+	s.helper.AssertError(err)
+	s.repo.AssertNotCalled(s.T(), "CreateProduct", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_UPCIsLookedUpWithLeadingZeros() {
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(12345678905)).Return(nil, model.ErrNoRows).Once()
+	s.nctClient.On("Lookup", mock.Anything, "0012345678905").Return([]model.Product{}, nil).Once()
+
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "012345678905",
+		Price:      300,
+		SupplierID: 10,
+	})
+
+	s.helper.AssertError(err)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_NCTMatchesOtherGTINs() {
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234591)).Return(nil, model.ErrNoRows).Once()
+	s.nctClient.On("Lookup", mock.Anything, "4870001234591").Return([]model.Product{
+		{Name: "Кефир 900 г", GTIN: 4870001234577},
+		{Name: "Кефир 450 г", GTIN: 4870001234584},
+	}, nil).Once()
+
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "4870001234591",
+		Price:      300,
+		SupplierID: 10,
+	})
+
+	s.helper.AssertError(err)
+	s.repo.AssertNotCalled(s.T(), "CreateProduct", mock.Anything, mock.Anything)
+	s.repo.AssertNotCalled(s.T(), "CreateProductSupplier", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestAddProduct_InvalidGTIN() {
+	err := s.service.AddProduct(context.Background(), &model.AddProductSupplier{
+		GTIN:       "4870001234567",
+		Price:      300,
+		SupplierID: 10,
+	})
+
+	s.ErrorIs(err, model.ErrInvalidGTIN)
+	s.repo.AssertNotCalled(s.T(), "GetProductByGTIN", mock.Anything, mock.Anything)
+	s.nctClient.AssertNotCalled(s.T(), "Lookup", mock.Anything, mock.Anything)
+}
+
+func TestMatchGTIN(t *testing.T) {
+	products := []model.Product{
+		{Name: "Кефир 900 г", GTIN: 4870001234577},
+		{Name: "Кефир 450 г", GTIN: 4870001234584},
+	}
+	assert.Equal(t, "Кефир 450 г", matchGTIN(products, 4870001234584).Name)
+
+	// no GTIN in the catalog row: the first match gets the searched one
+	products = []model.Product{{Name: "Хлеб"}, {Name: "Хлеб нарезной"}}
+	product := matchGTIN(products, 4870001234591)
+	assert.Equal(t, "Хлеб", product.Name)
+	assert.Equal(t, int64(4870001234591), product.GTIN)
+
+	// only other products: NCT does not know the GTIN
+	products = []model.Product{
+		{Name: "Кефир 900 г", GTIN: 4870001234577},
+		{Name: "Кефир 450 г", GTIN: 4870001234584},
+	}
+	assert.Nil(t, matchGTIN(products, 4870001234591))
+	assert.Nil(t, matchGTIN(nil, 4870001234591))
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"math"
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// PriceAnalytics summarizes the product's prices over the last days days:
+// the price of each supplier on every day of the period, taken from the
+// price history, and the current offers. Suppliers that no longer offer the
+// product keep their stats but are not active.
+func (s *ProductService) PriceAnalytics(ctx context.Context, productID int64, days int) (*model.PriceAnalytics, error) {
+	product, err := s.productRepository.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(day)
+	from := today.AddDate(0, 0, -days)
+
+	opening, err := s.productRepository.GetPricesAt(ctx, productID, from)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := s.productRepository.GetPriceHistory(ctx, productID, from)
+	if err != nil {
+		return nil, err
+	}
+	offers, err := s.productRepository.GetSupplierProductListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := buildPriceAnalytics(from, today, opening, changes, offers)
+	analytics.ProductID = product.ID
+	analytics.ProductName = product.Name
+	return analytics, nil
+}
+
+// supplierSeries follows one supplier's price through the period.
+type supplierSeries struct {
+	stats model.SupplierPriceStats
+	price int // price in effect, 0 until known
+	sum   int
+	days  int
+}
+
+func (s *supplierSeries) add(price int) {
+	if s.days == 0 || price < s.stats.MinPrice {
+		s.stats.MinPrice = price
+	}
+	if price > s.stats.MaxPrice {
+		s.stats.MaxPrice = price
+	}
+	s.sum += price
+	s.days++
+}
+
+// buildPriceAnalytics walks the days from from to today. Opening holds the
+// prices in effect at from, changes the history since then in date order;
+// the current offers alone set today's prices.
+func buildPriceAnalytics(from, today time.Time, opening, changes []model.PricePoint, offers []model.ProductSupplier) *model.PriceAnalytics {
+	series := make(map[int64]*supplierSeries)
+	supplier := func(id int64, name string) *supplierSeries {
+		s, ok := series[id]
+		if !ok {
+			s = &supplierSeries{stats: model.SupplierPriceStats{SupplierID: id}}
+			series[id] = s
+		}
+		if name != "" {
+			s.stats.SupplierName = name
+		}
+		return s
+	}
+
+	for _, p := range opening {
+		supplier(p.SupplierID, p.SupplierName).price = p.Price
+	}
+
+	analytics := &model.PriceAnalytics{
+		History:   []model.PricePoint{},
+		Suppliers: []model.SupplierPriceStats{},
+	}
+	var ids []int64
+	sum, count := 0, 0
+	next := 0
+	for date := from; !date.After(today); date = date.Add(day) {
+		for ; next < len(changes) && changes[next].Date.Before(date.Add(day)); next++ {
+			c := changes[next]
+			s := supplier(c.SupplierID, c.SupplierName)
+			s.price = c.Price
+			s.stats.PriceChanges++
+		}
+		if date.Equal(today) {
+			// today only the current offers count: a supplier that
+			// dropped the product keeps no price from its history
+			for _, s := range series {
+				s.price = 0
+			}
+			for _, o := range offers {
+				if o.Price == nil {
+					continue
+				}
+				name := ""
+				if o.Supplier.Name != nil {
+					name = *o.Supplier.Name
+				}
+				supplier(o.Supplier.ID, name).price = *o.Price
+			}
+		}
+
+		ids = ids[:0]
+		for id, s := range series {
+			if s.price > 0 {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		for _, id := range ids {
+			s := series[id]
+			s.add(s.price)
+			if count == 0 || s.price < analytics.MinPrice {
+				analytics.MinPrice = s.price
+			}
+			if s.price > analytics.MaxPrice {
+				analytics.MaxPrice = s.price
+			}
+			sum += s.price
+			count++
+			analytics.History = append(analytics.History, model.PricePoint{
+				SupplierID:   id,
+				SupplierName: s.stats.SupplierName,
+				Price:        s.price,
+				Date:         date,
+			})
+		}
+	}
+	if count > 0 {
+		analytics.AvgPrice = round2(float64(sum) / float64(count))
+	}
+
+	for _, s := range series {
+		if s.days == 0 {
+			continue
+		}
+		s.stats.CurrentPrice = s.price
+		s.stats.Active = s.price > 0
+		s.stats.AvgPrice = round2(float64(s.sum) / float64(s.days))
+		analytics.Suppliers = append(analytics.Suppliers, s.stats)
+	}
+	sort.Slice(analytics.Suppliers, func(i, j int) bool {
+		a, b := analytics.Suppliers[i], analytics.Suppliers[j]
+		if a.Active != b.Active {
+			return a.Active
+		}
+		if a.CurrentPrice != b.CurrentPrice {
+			return a.CurrentPrice < b.CurrentPrice
+		}
+		return a.SupplierID < b.SupplierID
+	})
+
+	for _, o := range offers {
+		if o.Price != nil && (analytics.CurrentPrice == 0 || *o.Price < analytics.CurrentPrice) {
+			analytics.CurrentPrice = *o.Price
+		}
+	}
+	return analytics
+}
+
+// round2 rounds prices and percentages to two decimals.
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPriceAnalytics(t *testing.T) {
+	date := func(d, h int) time.Time { return time.Date(2025, 9, d, h, 0, 0, 0, time.UTC) }
+	from, today := date(1, 0), date(4, 0)
+
+	opening := []model.PricePoint{
+		{SupplierID: 1, SupplierName: "A", Price: 100, Date: date(20, 0).AddDate(0, -1, 0)},
+	}
+	changes := []model.PricePoint{
+		{SupplierID: 1, SupplierName: "A", Price: 120, Date: date(2, 10)},
+		{SupplierID: 2, SupplierName: "B", Price: 200, Date: date(3, 9)},
+		{SupplierID: 1, SupplierName: "A", Price: 110, Date: date(3, 12)},
+	}
+	offers := []model.ProductSupplier{
+		{Price: intPtr(115), Supplier: model.Supplier{ID: 1, Name: strPtr("A")}},
+		{Price: intPtr(190), Supplier: model.Supplier{ID: 2, Name: strPtr("B")}},
+	}
+
+	analytics := buildPriceAnalytics(from, today, opening, changes, offers)
+
+	assert.Equal(t, []model.PricePoint{
+		{SupplierID: 1, SupplierName: "A", Price: 100, Date: date(1, 0)},
+		{SupplierID: 1, SupplierName: "A", Price: 120, Date: date(2, 0)},
+		{SupplierID: 1, SupplierName: "A", Price: 110, Date: date(3, 0)},
+		{SupplierID: 2, SupplierName: "B", Price: 200, Date: date(3, 0)},
+		{SupplierID: 1, SupplierName: "A", Price: 115, Date: date(4, 0)},
+		{SupplierID: 2, SupplierName: "B", Price: 190, Date: date(4, 0)},
+	}, analytics.History)
+
+	assert.Equal(t, 115, analytics.CurrentPrice)
+	assert.Equal(t, 100, analytics.MinPrice)
+	assert.Equal(t, 200, analytics.MaxPrice)
+	assert.Equal(t, 139.17, analytics.AvgPrice)
+
+	require.Len(t, analytics.Suppliers, 2)
+	assert.Equal(t, model.SupplierPriceStats{
+		SupplierID: 1, SupplierName: "A",
+		CurrentPrice: 115, Active: true, MinPrice: 100, MaxPrice: 120, AvgPrice: 111.25, PriceChanges: 2,
+	}, analytics.Suppliers[0])
+	assert.Equal(t, model.SupplierPriceStats{
+		SupplierID: 2, SupplierName: "B",
+		CurrentPrice: 190, Active: true, MinPrice: 190, MaxPrice: 200, AvgPrice: 195, PriceChanges: 1,
+	}, analytics.Suppliers[1])
+}
+
+func TestBuildPriceAnalytics_SupplierWithoutOffer(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	from, today := date(1), date(3)
+
+	opening := []model.PricePoint{
+		{SupplierID: 1, SupplierName: "A", Price: 100},
+		{SupplierID: 2, SupplierName: "B", Price: 80},
+	}
+	// B deactivated its offer: it is no longer among the current ones
+	offers := []model.ProductSupplier{
+		{Price: intPtr(100), Supplier: model.Supplier{ID: 1, Name: strPtr("A")}},
+	}
+
+	analytics := buildPriceAnalytics(from, today, opening, nil, offers)
+
+	for _, p := range analytics.History {
+		if p.Date.Equal(today) {
+			assert.Equal(t, int64(1), p.SupplierID)
+		}
+	}
+	assert.Len(t, analytics.History, 5)
+	assert.Equal(t, 100, analytics.CurrentPrice)
+
+	require.Len(t, analytics.Suppliers, 2)
+	assert.Equal(t, int64(1), analytics.Suppliers[0].SupplierID)
+	assert.True(t, analytics.Suppliers[0].Active)
+	assert.Equal(t, model.SupplierPriceStats{
+		SupplierID: 2, SupplierName: "B",
+		MinPrice: 80, MaxPrice: 80, AvgPrice: 80,
+	}, analytics.Suppliers[1])
+}
+
+func TestBuildPriceAnalytics_NoPrices(t *testing.T) {
+	today := time.Date(2025, 9, 4, 0, 0, 0, 0, time.UTC)
+
+	analytics := buildPriceAnalytics(today.AddDate(0, 0, -30), today, nil, nil, nil)
+
+	assert.Zero(t, analytics.CurrentPrice)
+	assert.Zero(t, analytics.AvgPrice)
+	assert.Empty(t, analytics.History)
+	assert.NotNil(t, analytics.History)
+	assert.Empty(t, analytics.Suppliers)
+	assert.NotNil(t, analytics.Suppliers)
+}
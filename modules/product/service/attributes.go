@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+
+	"go.uber.org/zap"
+)
+
+// UpdateProductAttributes changes the product's attributes and gallery.
+// Admins may edit any product, suppliers only products they added: the
+// catalog is shared by every supplier offering the product.
+func (s *ProductService) UpdateProductAttributes(ctx context.Context, productID, userID int64, role int, update *model.ProductAttributesUpdate) (*model.DetailedProduct, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		attrs, errTx := s.productRepository.LockProductAttributes(ctx, productID)
+		if errTx != nil {
+			return errTx
+		}
+		if errTx = s.checkProductEditor(ctx, productID, userID, role); errTx != nil {
+			return errTx
+		}
+
+		if update.HasAttributes() {
+			update.Apply(attrs)
+			if errTx = s.productRepository.UpdateProductAttributes(ctx, productID, attrs); errTx != nil {
+				return errTx
+			}
+		}
+		if update.Images != nil {
+			return s.productRepository.ReplaceProductImages(ctx, productID, *update.Images)
+		}
+		return nil
+	})
+	if err != nil {
+		s.LogError(ctx, "Failed to update product attributes", err,
+			zap.Int64("product_id", productID),
+			zap.Int64("user_id", userID),
+		)
+		return nil, err
+	}
+
+	s.LogInfo(ctx, "Product attributes updated",
+		zap.Int64("product_id", productID),
+		zap.Int64("user_id", userID),
+		zap.Bool("images", update.Images != nil),
+	)
+	return s.Product(ctx, &model.ProductQuery{ID: productID})
+}
+
+func (s *ProductService) checkProductEditor(ctx context.Context, productID, userID int64, role int) error {
+	switch role {
+	case model.AdminRole:
+		return nil
+	case model.SupplierRole:
+		creator, err := s.productRepository.IsProductCreator(ctx, productID, userID)
+		if err != nil {
+			return err
+		}
+		if creator {
+			return nil
+		}
+	}
+	return model.ErrForbidden
+}
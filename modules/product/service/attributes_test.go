@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+const testProductID = int64(1)
+
+// expectAttributes expects the product's attributes to be locked.
+func (s *ProductServiceTestSuite) expectAttributes() {
+	s.repo.On("LockProductAttributes", mock.Anything, testProductID).
+		Return(&model.ProductAttributes{Brand: "Old", Country: "Kazakhstan"}, nil).Once()
+}
+
+// expectProduct expects the updated product to be read back.
+func (s *ProductServiceTestSuite) expectProduct(attrs model.ProductAttributes, images []string) {
+	s.repo.On("GetProduct", mock.Anything, testProductID).
+		Return(&model.Product{ID: testProductID, ProductAttributes: attrs}, nil).Once()
+	s.repo.On("GetProductImages", mock.Anything, testProductID).Return(images, nil).Once()
+	s.repo.On("GetSupplierProductListByProduct", mock.Anything, testProductID).Return([]model.ProductSupplier{}, nil).Once()
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_Admin() {
+	s.expectAttributes()
+	updated := model.ProductAttributes{Brand: "New", Country: "Kazakhstan"}
+	s.repo.On("UpdateProductAttributes", mock.Anything, testProductID, &updated).Return(nil).Once()
+	s.expectProduct(updated, nil)
+
+	product, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 1, model.AdminRole,
+		&model.ProductAttributesUpdate{Brand: strPtr("New")})
+
+	s.helper.RequireNoError(err)
+	s.helper.AssertEqual("New", product.Product.Brand)
+	s.helper.AssertEqual("Kazakhstan", product.Product.Country, "fields not in the update are kept")
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_CreatingSupplier() {
+	s.expectAttributes()
+	s.repo.On("IsProductCreator", mock.Anything, testProductID, int64(10)).Return(true, nil).Once()
+	s.repo.On("UpdateProductAttributes", mock.Anything, testProductID, mock.Anything).Return(nil).Once()
+	s.expectProduct(model.ProductAttributes{Brand: "New"}, nil)
+
+	_, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 10, model.SupplierRole,
+		&model.ProductAttributesUpdate{Brand: strPtr("New")})
+
+	s.helper.AssertNoError(err)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_OtherSupplier() {
+	// offering the product does not make a supplier its editor
+	s.expectAttributes()
+	s.repo.On("IsProductCreator", mock.Anything, testProductID, int64(11)).Return(false, nil).Once()
+
+	_, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 11, model.SupplierRole,
+		&model.ProductAttributesUpdate{Brand: strPtr("New")})
+
+	s.ErrorIs(err, model.ErrForbidden)
+	s.repo.AssertNotCalled(s.T(), "UpdateProductAttributes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_Customer() {
+	s.expectAttributes()
+
+	_, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 10, model.CustomerRole,
+		&model.ProductAttributesUpdate{Brand: strPtr("New")})
+
+	s.ErrorIs(err, model.ErrForbidden)
+	s.repo.AssertNotCalled(s.T(), "UpdateProductAttributes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_Images() {
+	images := []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg"}
+	s.expectAttributes()
+	s.repo.On("ReplaceProductImages", mock.Anything, testProductID, images).Return(nil).Once()
+	s.expectProduct(model.ProductAttributes{Brand: "Old"}, images)
+
+	product, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 1, model.AdminRole,
+		&model.ProductAttributesUpdate{Images: &images})
+
+	s.helper.RequireNoError(err)
+	s.helper.AssertEqual(images, product.Product.Images)
+	s.repo.AssertNotCalled(s.T(), "UpdateProductAttributes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_NotFound() {
+	s.repo.On("LockProductAttributes", mock.Anything, int64(2)).Return(nil, model.ErrNoRows).Once()
+
+	_, err := s.service.UpdateProductAttributes(context.Background(), 2, 1, model.AdminRole,
+		&model.ProductAttributesUpdate{Brand: strPtr("New")})
+
+	s.ErrorIs(err, model.ErrNoRows)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateProductAttributes_Invalid() {
+	_, err := s.service.UpdateProductAttributes(context.Background(), testProductID, 1, model.AdminRole,
+		&model.ProductAttributesUpdate{})
+
+	s.ErrorIs(err, model.ErrInvalidAttributes)
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// importBatchSize bounds the rows matched locally per tick.
+	importBatchSize = 500
+	// lookupBatchSize bounds the NCT lookups per tick; each one drives a
+	// browser and takes seconds.
+	lookupBatchSize = 10
+)
+
+// CreateImport saves an uploaded import. Rows are validated by the file
+// parser; those that passed are processed in the background.
+func (s *ProductService) CreateImport(ctx context.Context, supplierID int64, fileName string, rows []model.ImportRow) (*model.Import, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", model.ErrInvalidImportFile)
+	}
+	if len(rows) > model.MaxImportRows {
+		return nil, fmt.Errorf("%w: more than %d rows", model.ErrInvalidImportFile, model.MaxImportRows)
+	}
+
+	var imp *model.Import
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		id, errTx := s.productRepository.CreateImport(ctx, &model.Import{
+			SupplierID: supplierID,
+			FileName:   fileName,
+		}, rows)
+		if errTx != nil {
+			return errTx
+		}
+		imp, errTx = s.productRepository.GetImport(ctx, id)
+		return errTx
+	})
+	if err != nil {
+		s.LogError(ctx, "Failed to create import", err, zap.Int64("supplier_id", supplierID))
+		return nil, err
+	}
+
+	s.LogInfo(ctx, "Import created",
+		zap.Int64("import_id", imp.ID),
+		zap.Int64("supplier_id", supplierID),
+		zap.Int("rows", imp.Progress.Total),
+		zap.Int("failed", imp.Progress.Failed),
+	)
+	return imp, nil
+}
+
+// Import returns the supplier's import with its progress.
+func (s *ProductService) Import(ctx context.Context, supplierID, id int64) (*model.Import, error) {
+	imp, err := s.productRepository.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if imp.SupplierID != supplierID {
+		return nil, model.ErrNoRows
+	}
+	return imp, nil
+}
+
+// ImportErrors returns the failed rows of the supplier's import.
+func (s *ProductService) ImportErrors(ctx context.Context, supplierID, id int64) ([]model.ImportRow, error) {
+	if _, err := s.Import(ctx, supplierID, id); err != nil {
+		return nil, err
+	}
+	return s.productRepository.FailedImportRows(ctx, id)
+}
+
+// StartImports processes uploaded imports every interval until ctx is
+// cancelled.
+func (s *ProductService) StartImports(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunImports(ctx); err != nil {
+			s.LogError(ctx, "Failed to run imports", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunImports matches pending rows against known products, looks queued
+// GTINs up in NCT and marks imports without open rows finished. Rows are
+// locked with SKIP LOCKED, so several instances can run side by side.
+func (s *ProductService) RunImports(ctx context.Context) error {
+	for i := 0; i < importBatchSize; i++ {
+		processed, err := s.importNextRow(ctx)
+		if err != nil {
+			return err
+		}
+		if !processed {
+			break
+		}
+	}
+
+	gtins, err := s.productRepository.ImportGTINs(ctx, model.ImportRowLookup, lookupBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, gtin := range gtins {
+		if err := s.lookupImportGTIN(ctx, gtin); err != nil {
+			s.LogError(ctx, "Failed to import looked up GTIN", err, zap.Int64("gtin", gtin))
+		}
+	}
+
+	return s.productRepository.FinishImports(ctx)
+}
+
+// importNextRow imports the next pending row if its GTIN is known and
+// queues it for NCT lookup otherwise. It reports whether a row was found.
+// A row that cannot be imported is failed, so it does not hold up the
+// rows after it.
+func (s *ProductService) importNextRow(ctx context.Context) (bool, error) {
+	var row *model.ImportRow
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var err error
+		row, err = s.productRepository.LockNextImportRow(ctx, model.ImportRowPending)
+		if err != nil {
+			return err
+		}
+		return s.importRow(ctx, row)
+	})
+	switch {
+	case row == nil && errors.Is(err, model.ErrNoRows):
+		return false, nil
+	case row == nil || err == nil:
+		return err == nil, err
+	}
+
+	s.LogError(ctx, "Failed to import row", err,
+		zap.Int64("import_id", row.ImportID),
+		zap.Int("row_no", row.RowNo),
+	)
+	return true, s.failImportRow(ctx, row)
+}
+
+func (s *ProductService) importRow(ctx context.Context, row *model.ImportRow) error {
+	if err := s.checkImportCategory(ctx, row); err != nil {
+		return err
+	}
+	if row.Status == model.ImportRowFailed {
+		return s.productRepository.UpdateImportRow(ctx, row)
+	}
+
+	product, err := s.getProductByGTIN(ctx, row.GTIN)
+	switch {
+	case errors.Is(err, model.ErrNoRows):
+		row.Status = model.ImportRowLookup
+	case errors.Is(err, model.ErrInvalidGTIN):
+		row.Fail("invalid GTIN")
+	case err != nil:
+		return err
+	default:
+		if err := s.importOffer(ctx, row, product.ID); err != nil {
+			return err
+		}
+	}
+	return s.productRepository.UpdateImportRow(ctx, row)
+}
+
+// failImportRow fails a pending row whose import was rolled back. A row
+// taken by another worker meanwhile is left to it.
+func (s *ProductService) failImportRow(ctx context.Context, row *model.ImportRow) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		locked, err := s.productRepository.LockImportRow(ctx, row.ImportID, row.RowNo, model.ImportRowPending)
+		if errors.Is(err, model.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		locked.Fail("import failed")
+		return s.productRepository.UpdateImportRow(ctx, locked)
+	})
+}
+
+// lookupImportGTIN fetches a GTIN queued by imports from NCT, creates the
+// product and imports every row waiting for it. The lookup runs outside
+// the transaction.
+func (s *ProductService) lookupImportGTIN(ctx context.Context, gtin int64) error {
+	found, lookupErr := s.nctClient.Lookup(ctx, model.LookupGTIN(gtin))
+	match := matchGTIN(found, gtin)
+
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		rows, err := s.productRepository.LockImportRowsByGTIN(ctx, gtin, model.ImportRowLookup)
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		var productID int64
+		product, err := s.getProductByGTIN(ctx, gtin)
+		switch {
+		case err == nil:
+			// created meanwhile, e.g. by AddProduct
+			productID = product.ID
+		case errors.Is(err, model.ErrInvalidGTIN):
+			return s.failImportRows(ctx, rows, "invalid GTIN")
+		case !errors.Is(err, model.ErrNoRows):
+			return err
+		case lookupErr != nil:
+			s.LogWarn(ctx, "NCT lookup failed", zap.Int64("gtin", gtin), zap.Error(lookupErr))
+			return s.retryImportLookup(ctx, rows, "NCT lookup failed")
+		case match == nil:
+			return s.failImportRows(ctx, rows, "product not found in NCT")
+		default:
+			productID, err = s.createImportedProduct(ctx, match, &rows[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		for i := range rows {
+			if err := s.importOffer(ctx, &rows[i], productID); err != nil {
+				return err
+			}
+			if err := s.productRepository.UpdateImportRow(ctx, &rows[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createImportedProduct stores a product found in NCT, categorized by the
+// row's category if given. The row's supplier is recorded as its creator.
+func (s *ProductService) createImportedProduct(ctx context.Context, product *model.Product, row *model.ImportRow) (int64, error) {
+	product.CreatedAt = time.Now()
+	product.UpdatedAt = product.CreatedAt
+	product.CreatedBy = &row.SupplierID
+
+	if err := s.handleCategories(ctx, product, &model.AddProductSupplier{CategoryID: row.CategoryID}); err != nil {
+		return 0, err
+	}
+	return s.productRepository.CreateProduct(ctx, product)
+}
+
+// checkImportCategory fails the row if it names a category that does not exist.
+func (s *ProductService) checkImportCategory(ctx context.Context, row *model.ImportRow) error {
+	if row.CategoryID == nil {
+		return nil
+	}
+	_, err := s.productRepository.GetCategory(ctx, *row.CategoryID)
+	if errors.Is(err, model.ErrNoRows) {
+		row.Fail("unknown category")
+		return nil
+	}
+	return err
+}
+
+func (s *ProductService) failImportRows(ctx context.Context, rows []model.ImportRow, reason string) error {
+	for i := range rows {
+		rows[i].Fail(reason)
+		if err := s.productRepository.UpdateImportRow(ctx, &rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryImportLookup schedules the rows' next lookup after a failed one and
+// fails the rows that ran out of attempts.
+func (s *ProductService) retryImportLookup(ctx context.Context, rows []model.ImportRow, reason string) error {
+	now := time.Now()
+	for i := range rows {
+		rows[i].RetryLookup(now, reason)
+		if err := s.productRepository.UpdateImportRow(ctx, &rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importOffer creates or updates the supplier's offer described by the row.
+func (s *ProductService) importOffer(ctx context.Context, row *model.ImportRow, productID int64) error {
+	if err := s.saveOffer(ctx, productID, row.SupplierID, row.Update()); err != nil {
+		return err
+	}
+	row.Status = model.ImportRowImported
+	row.ProductID = &productID
+	return nil
+}
+
+// saveOffer applies the update to the supplier's offer, creating it with
+// the default minimum quantity when missing, and records the price in the
+// price history when it is new or changed. Fields the update leaves unset
+// keep their values, as in UpdateOffer.
+func (s *ProductService) saveOffer(ctx context.Context, productID, supplierID int64, update *model.OfferUpdate) error {
+	offer, err := s.productRepository.LockProductSupplier(ctx, productID, supplierID)
+	created := errors.Is(err, model.ErrNoRows)
+	switch {
+	case created:
+		offer = &model.Offer{
+			ProductID:  productID,
+			SupplierID: supplierID,
+			SellAmount: model.DefaultSellAmount,
+		}
+	case err != nil:
+		return err
+	}
+
+	priceChanged := update.Apply(offer)
+	if created {
+		err = s.productRepository.CreateOffer(ctx, offer)
+	} else {
+		err = s.productRepository.UpdateProductSupplier(ctx, offer)
+	}
+	if err != nil || !(created || priceChanged) {
+		return err
+	}
+
+	return s.productRepository.CreatePriceChange(ctx, &model.PriceChange{
+		ProductID:  offer.ProductID,
+		SupplierID: offer.SupplierID,
+		Price:      offer.Price,
+		Date:       time.Now(),
+		Reason:     update.Reason,
+	})
+}
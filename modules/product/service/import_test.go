@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// rowWith matches the import row with the number and status.
+func rowWith(rowNo int, status string) interface{} {
+	return mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.RowNo == rowNo && row.Status == status
+	})
+}
+
+func (s *ProductServiceTestSuite) expectNextRow(row model.ImportRow) {
+	s.repo.On("LockNextImportRow", mock.Anything, model.ImportRowPending).Return(&row, nil).Once()
+}
+
+func (s *ProductServiceTestSuite) importNextRow() {
+	processed, err := s.service.importNextRow(context.Background())
+	s.helper.RequireNoError(err)
+	s.True(processed)
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_UpdatesOffer() {
+	s.expectNextRow(model.ImportRow{RowNo: 2, SupplierID: 10, GTIN: 4870001234560, Price: 450,
+		SellAmount: intPtr(2), Stock: intPtr(7), Status: model.ImportRowPending})
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234560)).Return(&model.Product{ID: 1}, nil).Once()
+	s.repo.On("LockProductSupplier", mock.Anything, int64(1), int64(10)).
+		Return(&model.Offer{ProductID: 1, SupplierID: 10, Price: 500, SellAmount: 1, Available: true}, nil).Once()
+	s.repo.On("UpdateProductSupplier", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		return o.Price == 450 && o.SellAmount == 2 && o.Stock != nil && *o.Stock == 7
+	})).Return(nil).Once()
+	s.repo.On("CreatePriceChange", mock.Anything, mock.MatchedBy(func(c *model.PriceChange) bool {
+		return c.Price == 450 && c.Reason == model.ImportPriceChangeReason
+	})).Return(nil).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowImported && *row.ProductID == 1
+	})).Return(nil).Once()
+
+	s.importNextRow()
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_CreatesOffer() {
+	s.expectNextRow(model.ImportRow{RowNo: 3, SupplierID: 10, GTIN: 4870001234577, Price: 300, Status: model.ImportRowPending})
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234577)).Return(&model.Product{ID: 2}, nil).Once()
+	s.repo.On("LockProductSupplier", mock.Anything, int64(2), int64(10)).Return(nil, model.ErrNoRows).Once()
+	// new offers get the default minimum quantity
+	s.repo.On("CreateOffer", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		return o.Price == 300 && o.SellAmount == model.DefaultSellAmount && o.Stock == nil && o.Available
+	})).Return(nil).Once()
+	s.repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, rowWith(3, model.ImportRowImported)).Return(nil).Once()
+
+	s.importNextRow()
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_KeepsFieldsMissingFromFile() {
+	s.expectNextRow(model.ImportRow{RowNo: 2, SupplierID: 10, GTIN: 4870001234560, Price: 500, Status: model.ImportRowPending})
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870001234560)).Return(&model.Product{ID: 1}, nil).Once()
+	s.repo.On("LockProductSupplier", mock.Anything, int64(1), int64(10)).
+		Return(&model.Offer{ProductID: 1, SupplierID: 10, Price: 500, SellAmount: 3, Stock: intPtr(9)}, nil).Once()
+	s.repo.On("UpdateProductSupplier", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		// imported offers are listed
+		return o.SellAmount == 3 && o.Stock != nil && *o.Stock == 9 && o.Available
+	})).Return(nil).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, rowWith(2, model.ImportRowImported)).Return(nil).Once()
+
+	s.importNextRow()
+	// the price did not change
+	s.repo.AssertNotCalled(s.T(), "CreatePriceChange", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_UnknownGTINIsQueued() {
+	s.expectNextRow(model.ImportRow{RowNo: 4, SupplierID: 10, GTIN: 4870009999997, Price: 100, Status: model.ImportRowPending})
+	s.repo.On("GetProductByGTIN", mock.Anything, int64(4870009999997)).Return(nil, model.ErrNoRows).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, rowWith(4, model.ImportRowLookup)).Return(nil).Once()
+
+	s.importNextRow()
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_UnknownCategory() {
+	s.expectNextRow(model.ImportRow{RowNo: 5, SupplierID: 10, GTIN: 4870001234560, Price: 100,
+		CategoryID: intPtr(9), Status: model.ImportRowPending})
+	s.repo.On("GetCategory", mock.Anything, 9).Return(nil, model.ErrNoRows).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowFailed && row.Error == "unknown category"
+	})).Return(nil).Once()
+
+	s.importNextRow()
+	s.repo.AssertNotCalled(s.T(), "GetProductByGTIN", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_InvalidGTIN() {
+	// a product flagged with an invalid GTIN is not matched
+	s.expectNextRow(model.ImportRow{RowNo: 2, SupplierID: 10, GTIN: 4870001234567, Price: 500, Status: model.ImportRowPending})
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowFailed && row.Error == "invalid GTIN"
+	})).Return(nil).Once()
+
+	s.importNextRow()
+	s.repo.AssertNotCalled(s.T(), "GetProductByGTIN", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_NoPendingRows() {
+	s.repo.On("LockNextImportRow", mock.Anything, model.ImportRowPending).Return(nil, model.ErrNoRows).Once()
+
+	processed, err := s.service.importNextRow(context.Background())
+
+	s.helper.AssertNoError(err)
+	s.False(processed)
+}
+
+func (s *ProductServiceTestSuite) TestLookupImportGTIN() {
+	gtin := int64(4870001234560)
+	s.nctClient.On("Lookup", outsideTx, "4870001234560").
+		Return([]model.Product{{Name: "Молоко пастеризованное 3,2% 1 л", GTIN: gtin}}, nil).Once()
+	s.repo.On("LockImportRowsByGTIN", mock.Anything, gtin, model.ImportRowLookup).Return([]model.ImportRow{
+		{RowNo: 2, SupplierID: 10, GTIN: gtin, Price: 450, CategoryID: intPtr(1), Status: model.ImportRowLookup},
+		{RowNo: 3, SupplierID: 11, GTIN: gtin, Price: 470, Status: model.ImportRowLookup},
+	}, nil).Once()
+	s.repo.On("GetProductByGTIN", mock.Anything, gtin).Return(nil, model.ErrNoRows).Once()
+	// categorized and created by the first row
+	s.repo.On("CreateProduct", mock.Anything, mock.MatchedBy(func(p *model.Product) bool {
+		return p.GTIN == gtin && p.CategoryID != nil && *p.CategoryID == 1 && p.CreatedBy != nil && *p.CreatedBy == 10
+	})).Return(int64(101), nil).Once()
+	for supplierID, price := range map[int64]int{10: 450, 11: 470} {
+		supplierID, price := supplierID, price
+		s.repo.On("LockProductSupplier", mock.Anything, int64(101), supplierID).Return(nil, model.ErrNoRows).Once()
+		s.repo.On("CreateOffer", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+			return o.SupplierID == supplierID && o.Price == price
+		})).Return(nil).Once()
+	}
+	s.repo.On("CreatePriceChange", mock.Anything, mock.Anything).Return(nil).Twice()
+	s.repo.On("UpdateImportRow", mock.Anything, rowWith(2, model.ImportRowImported)).Return(nil).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, rowWith(3, model.ImportRowImported)).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.lookupImportGTIN(context.Background(), gtin))
+}
+
+func (s *ProductServiceTestSuite) TestLookupImportGTIN_NotFoundInNCT() {
+	gtin := int64(4870009999997)
+	s.nctClient.On("Lookup", mock.Anything, "4870009999997").Return([]model.Product{}, nil).Once()
+	s.repo.On("LockImportRowsByGTIN", mock.Anything, gtin, model.ImportRowLookup).Return([]model.ImportRow{
+		{RowNo: 4, SupplierID: 10, GTIN: gtin, Price: 100, Status: model.ImportRowLookup},
+	}, nil).Once()
+	s.repo.On("GetProductByGTIN", mock.Anything, gtin).Return(nil, model.ErrNoRows).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowFailed && row.Error == "product not found in NCT"
+	})).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.lookupImportGTIN(context.Background(), gtin))
+	s.repo.AssertNotCalled(s.T(), "CreateProduct", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestImportNextRow_ErrorFailsRow() {
+	row := model.ImportRow{ImportID: 1, RowNo: 6, SupplierID: 10, GTIN: 4870001234560, Price: 100, Status: model.ImportRowPending}
+	s.expectNextRow(row)
+	s.repo.On("GetProductByGTIN", mock.Anything, row.GTIN).Return(nil, errors.New("conn busy")).Once()
+	s.repo.On("LockImportRow", mock.Anything, int64(1), 6, model.ImportRowPending).Return(&row, nil).Once()
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowFailed && row.Error == "import failed"
+	})).Return(nil).Once()
+
+	// the queue goes on with the next row
+	s.importNextRow()
+}
+
+func (s *ProductServiceTestSuite) expectLookupFailure(attempts int) {
+	gtin := int64(4870009999997)
+	s.nctClient.On("Lookup", mock.Anything, "4870009999997").Return(nil, errors.New("timeout")).Once()
+	s.repo.On("LockImportRowsByGTIN", mock.Anything, gtin, model.ImportRowLookup).Return([]model.ImportRow{
+		{RowNo: 4, SupplierID: 10, GTIN: gtin, Price: 100, Status: model.ImportRowLookup, LookupAttempts: attempts},
+	}, nil).Once()
+	s.repo.On("GetProductByGTIN", mock.Anything, gtin).Return(nil, model.ErrNoRows).Once()
+}
+
+func (s *ProductServiceTestSuite) TestLookupImportGTIN_FailedLookupIsRetried() {
+	s.expectLookupFailure(1)
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowLookup && row.LookupAttempts == 2 &&
+			time.Until(row.NextLookupAt) > model.LookupRetryDelay
+	})).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.lookupImportGTIN(context.Background(), 4870009999997))
+}
+
+func (s *ProductServiceTestSuite) TestLookupImportGTIN_LastFailedLookupFailsRows() {
+	s.expectLookupFailure(model.MaxLookupAttempts - 1)
+	s.repo.On("UpdateImportRow", mock.Anything, mock.MatchedBy(func(row *model.ImportRow) bool {
+		return row.Status == model.ImportRowFailed && row.Error == "NCT lookup failed"
+	})).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.lookupImportGTIN(context.Background(), 4870009999997))
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// marketAggregateDays is how far back the daily aggregates are built,
+	// the longest period the analytics serve.
+	marketAggregateDays = 365
+	// marketRefreshDays is how far back the aggregates are refreshed every
+	// interval, so that orders cancelled some days later are caught.
+	marketRefreshDays = 30
+	// marketTopSize is the number of top gainers and losers reported.
+	marketTopSize = 5
+)
+
+// StartMarketAggregates rebuilds the daily market aggregates of the whole
+// analytics period once a day and refreshes the recent days every interval
+// until ctx is cancelled. Instances take turns: a tick on which another
+// instance is refreshing is skipped.
+func (s *ProductService) StartMarketAggregates(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var rebuilt time.Time // day of the last full rebuild
+	for {
+		today := time.Now().UTC().Truncate(day)
+		days := marketRefreshDays
+		if !rebuilt.Equal(today) {
+			days = marketAggregateDays
+		}
+
+		from := today.AddDate(0, 0, -days)
+		refreshed, err := s.refreshMarketAggregates(ctx, from)
+		if err != nil {
+			s.LogError(ctx, "Failed to refresh market aggregates", err, zap.Time("from", from))
+		} else if refreshed && days == marketAggregateDays {
+			rebuilt = today
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshMarketAggregates refreshes the aggregates since from unless
+// another instance holds the lock on them.
+func (s *ProductService) refreshMarketAggregates(ctx context.Context, from time.Time) (bool, error) {
+	var refreshed bool
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		locked, errTx := s.productRepository.TryLockPriceAggregates(ctx)
+		if errTx != nil || !locked {
+			return errTx
+		}
+		refreshed = true
+		return s.productRepository.RefreshPriceAggregates(ctx, from)
+	})
+	return refreshed && err == nil, err
+}
+
+// MarketAnalytics computes the market index over the last days days from
+// the daily aggregates. Category, when set, is a category ID or name and
+// limits the analytics to it.
+func (s *ProductService) MarketAnalytics(ctx context.Context, days int, category string) (*model.MarketAnalytics, error) {
+	var categoryID *int
+	if category = strings.TrimSpace(category); category != "" {
+		c, err := s.findCategory(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		categoryID, category = &c.ID, c.Name
+	}
+
+	today := time.Now().UTC().Truncate(day)
+	from := today.AddDate(0, 0, -days)
+
+	prices, err := s.productRepository.GetDailyPrices(ctx, from, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics := buildMarketAnalytics(from, today, prices)
+	analytics.Days = days
+	analytics.Category = category
+	return analytics, nil
+}
+
+func (s *ProductService) findCategory(ctx context.Context, category string) (*model.Category, error) {
+	if id, err := strconv.Atoi(category); err == nil {
+		return s.productRepository.GetCategory(ctx, id)
+	}
+	return s.productRepository.GetCategoryByName(ctx, category)
+}
+
+// productSeries is a product's average price on each day of the period,
+// 0 before the first known price and on days it had no available offer.
+type productSeries struct {
+	id         int64
+	name       string
+	categoryID int
+	prices     []float64
+	known      []bool // the day has an aggregate
+	volume     int
+}
+
+// first and last return the first and last known prices.
+func (p *productSeries) first() float64 {
+	for _, price := range p.prices {
+		if price > 0 {
+			return price
+		}
+	}
+	return 0
+}
+
+func (p *productSeries) last() float64 {
+	return p.prices[len(p.prices)-1]
+}
+
+// buildMarketAnalytics computes the analytics of the days from from to
+// today from the daily aggregates, which are ordered by day.
+func buildMarketAnalytics(from, today time.Time, prices []model.DailyPrice) *model.MarketAnalytics {
+	n := int(today.Sub(from)/day) + 1
+
+	var products []*productSeries
+	byID := make(map[int64]*productSeries)
+	categories := make(map[int]string)
+	volumes := make([]int, n)
+	for _, row := range prices {
+		i := int(row.Day.Sub(from) / day)
+		if i < 0 || i >= n {
+			continue
+		}
+		p, ok := byID[row.ProductID]
+		if !ok {
+			p = &productSeries{
+				id:         row.ProductID,
+				name:       row.ProductName,
+				categoryID: row.CategoryID,
+				prices:     make([]float64, n),
+				known:      make([]bool, n),
+			}
+			byID[row.ProductID] = p
+			products = append(products, p)
+		}
+		if row.CategoryID != 0 {
+			categories[row.CategoryID] = row.CategoryName
+		}
+		p.prices[i] = row.AvgPrice
+		p.known[i] = true
+		p.volume += row.Volume
+		volumes[i] += row.Volume
+	}
+	// A day the aggregates do not reach yet keeps the previous price. A day
+	// without an available offer has an aggregate with no price and is not
+	// filled.
+	for _, p := range products {
+		for i := 1; i < n; i++ {
+			if !p.known[i] {
+				p.prices[i] = p.prices[i-1]
+			}
+		}
+	}
+
+	index := marketIndex(products, n)
+	analytics := &model.MarketAnalytics{
+		TotalProducts: len(products),
+		PriceChange:   round2(index[n-1] - 100),
+		Volatility:    volatility(index),
+		TopGainers:    []model.ProductTrend{},
+		TopLosers:     []model.ProductTrend{},
+		Trend:         make([]model.MarketIndexPoint, 0, n),
+		Categories:    []model.CategoryMarket{},
+	}
+	for i := 0; i < n; i++ {
+		analytics.Trend = append(analytics.Trend, model.MarketIndexPoint{
+			Date:   from.AddDate(0, 0, i),
+			Index:  round2(index[i]),
+			Volume: volumes[i],
+		})
+	}
+
+	for _, p := range products {
+		base := p.first()
+		if base == 0 || p.last() == 0 {
+			// no longer offered
+			continue
+		}
+		trend := model.ProductTrend{
+			ProductID:    p.id,
+			ProductName:  p.name,
+			CurrentPrice: int(math.Round(p.last())),
+			PriceChange:  round2((p.last() - base) / base * 100),
+			Volume:       p.volume,
+		}
+		switch {
+		case trend.PriceChange > 0:
+			analytics.TopGainers = append(analytics.TopGainers, trend)
+		case trend.PriceChange < 0:
+			analytics.TopLosers = append(analytics.TopLosers, trend)
+		}
+	}
+	analytics.TopGainers = topTrends(analytics.TopGainers, func(a, b float64) bool { return a > b })
+	analytics.TopLosers = topTrends(analytics.TopLosers, func(a, b float64) bool { return a < b })
+
+	for id, name := range categories {
+		var members []*productSeries
+		var top *productSeries
+		for _, p := range products {
+			if p.categoryID != id {
+				continue
+			}
+			members = append(members, p)
+			if top == nil || p.volume > top.volume {
+				top = p
+			}
+		}
+		index := marketIndex(members, n)
+		analytics.Categories = append(analytics.Categories, model.CategoryMarket{
+			CategoryID:      id,
+			CategoryName:    name,
+			ProductCount:    len(members),
+			PriceChange:     round2(index[n-1] - 100),
+			Volatility:      volatility(index),
+			TopProduct:      top.name,
+			TopProductPrice: int(math.Round(top.last())),
+		})
+	}
+	sort.Slice(analytics.Categories, func(i, j int) bool {
+		return analytics.Categories[i].CategoryName < analytics.Categories[j].CategoryName
+	})
+
+	return analytics
+}
+
+// marketIndex is a chained index of the products' prices, 100 on the first
+// day. Each day's change is the change of the products priced the day
+// before, weighted by the quantity ordered during the period, or equally
+// when none of them was ordered. A product counts towards a day's change
+// only when it was priced on both days, so products join the index the day
+// after their first known price and leave it while they are not offered.
+func marketIndex(products []*productSeries, n int) []float64 {
+	index := make([]float64, n)
+	index[0] = 100
+	for i := 1; i < n; i++ {
+		index[i] = index[i-1]
+
+		var prev, cur, prevEqual, curEqual float64
+		for _, p := range products {
+			if p.prices[i-1] == 0 || p.prices[i] == 0 {
+				continue
+			}
+			w := float64(p.volume)
+			prev += w * p.prices[i-1]
+			cur += w * p.prices[i]
+			prevEqual += p.prices[i-1]
+			curEqual += p.prices[i]
+		}
+		if prev == 0 {
+			prev, cur = prevEqual, curEqual
+		}
+		if prev > 0 {
+			index[i] = index[i-1] * cur / prev
+		}
+	}
+	return index
+}
+
+// volatility is the standard deviation of the index's daily changes, %.
+func volatility(index []float64) float64 {
+	if len(index) < 3 {
+		return 0
+	}
+	changes := make([]float64, 0, len(index)-1)
+	mean := 0.0
+	for i := 1; i < len(index); i++ {
+		change := (index[i]/index[i-1] - 1) * 100
+		changes = append(changes, change)
+		mean += change
+	}
+	mean /= float64(len(changes))
+
+	variance := 0.0
+	for _, change := range changes {
+		variance += (change - mean) * (change - mean)
+	}
+	return round2(math.Sqrt(variance / float64(len(changes)-1)))
+}
+
+// topTrends orders the trends by price change, then by volume, and keeps
+// the first marketTopSize.
+func topTrends(trends []model.ProductTrend, before func(a, b float64) bool) []model.ProductTrend {
+	sort.Slice(trends, func(i, j int) bool {
+		a, b := trends[i], trends[j]
+		if a.PriceChange != b.PriceChange {
+			return before(a.PriceChange, b.PriceChange)
+		}
+		if a.Volume != b.Volume {
+			return a.Volume > b.Volume
+		}
+		return a.ProductID < b.ProductID
+	})
+	if len(trends) > marketTopSize {
+		trends = trends[:marketTopSize]
+	}
+	return trends
+}
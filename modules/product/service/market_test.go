@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMarketAnalytics(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	milk := func(d int, price float64, volume int) model.DailyPrice {
+		return model.DailyPrice{Day: date(d), ProductID: 1, ProductName: "Milk", CategoryID: 1, CategoryName: "Dairy", AvgPrice: price, Volume: volume}
+	}
+	cheese := func(d int, price float64) model.DailyPrice {
+		return model.DailyPrice{Day: date(d), ProductID: 2, ProductName: "Cheese", CategoryID: 1, CategoryName: "Dairy", AvgPrice: price}
+	}
+	bread := func(d int, price float64, volume int) model.DailyPrice {
+		return model.DailyPrice{Day: date(d), ProductID: 3, ProductName: "Bread", CategoryID: 2, CategoryName: "Bakery", AvgPrice: price, Volume: volume}
+	}
+
+	analytics := buildMarketAnalytics(date(1), date(3), []model.DailyPrice{
+		milk(1, 100, 10), cheese(1, 200),
+		milk(2, 110, 0), bread(2, 50, 5), // cheese is missing and keeps its price
+		milk(3, 121, 0), cheese(3, 180), bread(3, 55, 0),
+	})
+
+	assert.Equal(t, 3, analytics.TotalProducts)
+	assert.Equal(t, []model.MarketIndexPoint{
+		{Date: date(1), Index: 100, Volume: 10},
+		{Date: date(2), Index: 110, Volume: 5},
+		{Date: date(3), Index: 121, Volume: 0},
+	}, analytics.Trend, "cheese was never ordered and has no weight; bread joins on the third day")
+	assert.Equal(t, 21.0, analytics.PriceChange)
+	assert.Equal(t, 0.0, analytics.Volatility)
+
+	require.Len(t, analytics.TopGainers, 2)
+	assert.Equal(t, model.ProductTrend{ProductID: 1, ProductName: "Milk", CurrentPrice: 121, PriceChange: 21, Volume: 10}, analytics.TopGainers[0])
+	assert.Equal(t, int64(3), analytics.TopGainers[1].ProductID)
+	assert.Equal(t, 10.0, analytics.TopGainers[1].PriceChange)
+	require.Len(t, analytics.TopLosers, 1)
+	assert.Equal(t, model.ProductTrend{ProductID: 2, ProductName: "Cheese", CurrentPrice: 180, PriceChange: -10}, analytics.TopLosers[0])
+
+	assert.Equal(t, []model.CategoryMarket{
+		{CategoryID: 2, CategoryName: "Bakery", ProductCount: 1, PriceChange: 10, Volatility: 7.07, TopProduct: "Bread", TopProductPrice: 55},
+		{CategoryID: 1, CategoryName: "Dairy", ProductCount: 2, PriceChange: 21, Volatility: 0, TopProduct: "Milk", TopProductPrice: 121},
+	}, analytics.Categories)
+}
+
+func TestBuildMarketAnalytics_NoData(t *testing.T) {
+	today := time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	analytics := buildMarketAnalytics(today.AddDate(0, 0, -29), today, nil)
+
+	assert.Zero(t, analytics.TotalProducts)
+	assert.Zero(t, analytics.PriceChange)
+	require.Len(t, analytics.Trend, 30)
+	assert.Equal(t, 100.0, analytics.Trend[29].Index)
+	assert.Empty(t, analytics.TopGainers)
+	assert.Empty(t, analytics.Categories)
+}
+
+func TestBuildMarketAnalytics_WithdrawnProduct(t *testing.T) {
+	date := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	milk := func(d int, price float64) model.DailyPrice {
+		return model.DailyPrice{Day: date(d), ProductID: 1, ProductName: "Milk", AvgPrice: price}
+	}
+	cheese := func(d int, price float64) model.DailyPrice {
+		return model.DailyPrice{Day: date(d), ProductID: 2, ProductName: "Cheese", AvgPrice: price}
+	}
+
+	analytics := buildMarketAnalytics(date(1), date(4), []model.DailyPrice{
+		milk(1, 100), cheese(1, 200),
+		milk(2, 110), cheese(2, 0), // cheese was withdrawn
+		milk(3, 121), // cheese is missing and stays withdrawn
+		milk(4, 121),
+	})
+
+	assert.Equal(t, []model.MarketIndexPoint{
+		{Date: date(1), Index: 100},
+		{Date: date(2), Index: 110},
+		{Date: date(3), Index: 121},
+		{Date: date(4), Index: 121},
+	}, analytics.Trend, "cheese leaves the index instead of keeping its last price")
+	require.Len(t, analytics.TopGainers, 1)
+	assert.Equal(t, int64(1), analytics.TopGainers[0].ProductID)
+	assert.Empty(t, analytics.TopLosers, "a product no longer offered has no current price")
+}
+
+func TestMarketIndex_EqualWeightsWithoutOrders(t *testing.T) {
+	products := []*productSeries{
+		{prices: []float64{100, 150}},
+		{prices: []float64{300, 300}},
+	}
+
+	assert.Equal(t, []float64{100, 112.5}, marketIndex(products, 2))
+}
+
+func (s *ProductServiceTestSuite) TestMarketAnalytics_CategoryByID() {
+	categoryID := 7
+	s.repo.On("GetCategory", mock.Anything, 7).Return(&model.Category{ID: 7, Name: "Молочные продукты"}, nil).Once()
+	s.repo.On("GetDailyPrices", mock.Anything, mock.Anything, &categoryID).Return(nil, nil).Once()
+
+	analytics, err := s.service.MarketAnalytics(context.Background(), 30, " 7 ")
+
+	s.helper.RequireNoError(err)
+	s.helper.AssertEqual("Молочные продукты", analytics.Category)
+}
+
+func (s *ProductServiceTestSuite) TestMarketAnalytics_CategoryByName() {
+	categoryID := 7
+	s.repo.On("GetCategoryByName", mock.Anything, "молочные продукты").
+		Return(&model.Category{ID: 7, Name: "Молочные продукты"}, nil).Once()
+	s.repo.On("GetDailyPrices", mock.Anything, mock.Anything, &categoryID).Return(nil, nil).Once()
+
+	analytics, err := s.service.MarketAnalytics(context.Background(), 30, "молочные продукты")
+
+	s.helper.RequireNoError(err)
+	s.helper.AssertEqual("Молочные продукты", analytics.Category)
+}
+
+func (s *ProductServiceTestSuite) TestMarketAnalytics_AllCategories() {
+	s.repo.On("GetDailyPrices", mock.Anything, mock.Anything, (*int)(nil)).Return(nil, nil).Once()
+
+	analytics, err := s.service.MarketAnalytics(context.Background(), 30, "")
+
+	s.helper.RequireNoError(err)
+	s.Empty(analytics.Category)
+}
+
+func (s *ProductServiceTestSuite) TestMarketAnalytics_UnknownCategory() {
+	s.repo.On("GetCategoryByName", mock.Anything, "Мясо").Return(nil, model.ErrNoRows).Once()
+
+	_, err := s.service.MarketAnalytics(context.Background(), 30, "Мясо")
+
+	s.ErrorIs(err, model.ErrNoRows)
+	s.repo.AssertNotCalled(s.T(), "GetDailyPrices", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestRefreshMarketAggregates() {
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	s.repo.On("TryLockPriceAggregates", mock.Anything).Return(true, nil).Once()
+	s.repo.On("RefreshPriceAggregates", mock.Anything, from).Return(nil).Once()
+
+	refreshed, err := s.service.refreshMarketAggregates(context.Background(), from)
+
+	s.helper.AssertNoError(err)
+	s.True(refreshed)
+}
+
+func (s *ProductServiceTestSuite) TestRefreshMarketAggregates_OtherInstanceRefreshing() {
+	s.repo.On("TryLockPriceAggregates", mock.Anything).Return(false, nil).Once()
+
+	refreshed, err := s.service.refreshMarketAggregates(context.Background(), time.Now())
+
+	s.helper.AssertNoError(err)
+	s.False(refreshed)
+	s.repo.AssertNotCalled(s.T(), "RefreshPriceAggregates", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// UpdateOffer changes the supplier's own offer of the product. A price
+// change is recorded in the price history; the product's lowest price is
+// kept up to date by the database.
+func (s *ProductService) UpdateOffer(ctx context.Context, productID, supplierID int64, update *model.OfferUpdate) (*model.Offer, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	var offer *model.Offer
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var errTx error
+		offer, errTx = s.productRepository.LockProductSupplier(ctx, productID, supplierID)
+		if errTx != nil {
+			return errTx
+		}
+
+		priceChanged := update.Apply(offer)
+		if errTx = s.productRepository.UpdateProductSupplier(ctx, offer); errTx != nil {
+			return errTx
+		}
+		if !priceChanged {
+			return nil
+		}
+
+		reason := update.Reason
+		if reason == "" {
+			reason = model.DefaultPriceChangeReason
+		}
+		return s.productRepository.CreatePriceChange(ctx, &model.PriceChange{
+			ProductID:  productID,
+			SupplierID: supplierID,
+			Price:      offer.Price,
+			Date:       time.Now(),
+			Reason:     reason,
+		})
+	})
+	if err != nil {
+		s.LogError(ctx, "Failed to update offer", err,
+			zap.Int64("product_id", productID),
+			zap.Int64("supplier_id", supplierID),
+		)
+		return nil, err
+	}
+
+	s.LogInfo(ctx, "Offer updated",
+		zap.Int64("product_id", productID),
+		zap.Int64("supplier_id", supplierID),
+		zap.Int("price", offer.Price),
+		zap.Bool("available", offer.Available),
+	)
+	return offer, nil
+}
+
+// DeactivateOffer withdraws the supplier's offer of the product. The offer
+// is kept and can be made available again with UpdateOffer.
+func (s *ProductService) DeactivateOffer(ctx context.Context, productID, supplierID int64) (*model.Offer, error) {
+	available := false
+	return s.UpdateOffer(ctx, productID, supplierID, &model.OfferUpdate{Available: &available})
+}
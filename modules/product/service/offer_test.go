@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *ProductServiceTestSuite) expectOffer(supplierID int64) {
+	s.repo.On("LockProductSupplier", mock.Anything, testProductID, supplierID).
+		Return(&model.Offer{ProductID: testProductID, SupplierID: supplierID, Price: 500, SellAmount: 1, Available: true}, nil).Once()
+}
+
+func (s *ProductServiceTestSuite) TestUpdateOffer_PriceChangeIsRecorded() {
+	s.expectOffer(10)
+	s.repo.On("UpdateProductSupplier", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		return o.Price == 450
+	})).Return(nil).Once()
+	s.repo.On("CreatePriceChange", mock.Anything, mock.MatchedBy(func(c *model.PriceChange) bool {
+		return c.Price == 450 && c.Reason == "promo"
+	})).Return(nil).Once()
+
+	offer, err := s.service.UpdateOffer(context.Background(), testProductID, 10, &model.OfferUpdate{
+		Price:  intPtr(450),
+		Reason: "promo",
+	})
+
+	s.helper.RequireNoError(err)
+	s.helper.AssertEqual(450, offer.Price)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateOffer_SamePriceIsNotRecorded() {
+	s.expectOffer(10)
+	s.repo.On("UpdateProductSupplier", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		return o.Price == 500 && o.SellAmount == 6
+	})).Return(nil).Once()
+
+	_, err := s.service.UpdateOffer(context.Background(), testProductID, 10, &model.OfferUpdate{
+		Price:      intPtr(500),
+		SellAmount: intPtr(6),
+	})
+
+	s.helper.AssertNoError(err)
+	s.repo.AssertNotCalled(s.T(), "CreatePriceChange", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateOffer_OtherSuppliersOfferIsNotFound() {
+	s.repo.On("LockProductSupplier", mock.Anything, testProductID, int64(11)).Return(nil, model.ErrNoRows).Once()
+
+	_, err := s.service.UpdateOffer(context.Background(), testProductID, 11, &model.OfferUpdate{Price: intPtr(1)})
+
+	s.ErrorIs(err, model.ErrNoRows)
+	s.repo.AssertNotCalled(s.T(), "UpdateProductSupplier", mock.Anything, mock.Anything)
+}
+
+func (s *ProductServiceTestSuite) TestUpdateOffer_Invalid() {
+	_, err := s.service.UpdateOffer(context.Background(), testProductID, 10, &model.OfferUpdate{})
+	s.ErrorIs(err, model.ErrInvalidOffer)
+
+	_, err = s.service.UpdateOffer(context.Background(), testProductID, 10, &model.OfferUpdate{Price: intPtr(0)})
+	s.ErrorIs(err, model.ErrInvalidOffer)
+}
+
+func (s *ProductServiceTestSuite) TestDeactivateOffer() {
+	s.expectOffer(10)
+	s.repo.On("UpdateProductSupplier", mock.Anything, mock.MatchedBy(func(o *model.Offer) bool {
+		return !o.Available && o.Price == 500
+	})).Return(nil).Once()
+
+	offer, err := s.service.DeactivateOffer(context.Background(), testProductID, 10)
+
+	s.helper.RequireNoError(err)
+	s.False(offer.Available)
+	s.repo.AssertNotCalled(s.T(), "CreatePriceChange", mock.Anything, mock.Anything)
+}
@@ -8,9 +8,14 @@ import (
 )
 
 func (s *ProductService) ProductList(ctx context.Context, query *model.ProductListQuery) (*model.ProductList, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
 	s.LogInfo(ctx, "Fetching product list",
 		zap.Int("offset", query.Offset),
 		zap.Int("limit", query.Limit),
+		zap.String("sort", query.Sort),
 	)
 
 	productList, err := s.productRepository.GetProductList(ctx, query)
@@ -19,15 +24,22 @@ func (s *ProductService) ProductList(ctx context.Context, query *model.ProductLi
 		return nil, err
 	}
 
-	total, err := s.productRepository.GetTotalProducts(ctx)
+	total, err := s.productRepository.GetTotalProducts(ctx, query)
 	if err != nil {
 		s.LogError(ctx, "Failed to get total products count", err)
 		return nil, err
 	}
 
+	facets, err := s.productRepository.GetProductFacets(ctx, query)
+	if err != nil {
+		s.LogError(ctx, "Failed to get product facets", err)
+		return nil, err
+	}
+
 	result := &model.ProductList{
 		Products: productList,
 		Total:    total,
+		Facets:   facets,
 	}
 
 	s.LogInfo(ctx, "Successfully fetched product list",
@@ -12,6 +12,11 @@ func (s *ProductService) Product(ctx context.Context, query *model.ProductQuery)
 		return nil, err
 	}
 
+	product.Images, err = s.productRepository.GetProductImages(ctx, query.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	productSupplierList, err := s.productRepository.GetSupplierProductListByProduct(ctx, query.ID)
 
 	if err != nil {
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/product/model"
+
+	"go.uber.org/zap"
+)
+
+func (s *ProductService) SearchProducts(ctx context.Context, query *model.ProductSearchQuery) (*model.ProductSearchList, error) {
+	if query.Normalized() == "" {
+		return nil, model.ErrEmptySearchQuery
+	}
+
+	s.LogInfo(ctx, "Searching products",
+		zap.String("query", query.Query),
+		zap.Int("offset", query.Offset),
+		zap.Int("limit", query.Limit),
+	)
+
+	products, err := s.productRepository.SearchProducts(ctx, query)
+	if err != nil {
+		s.LogError(ctx, "Failed to search products", err)
+		return nil, err
+	}
+
+	total, err := s.productRepository.GetTotalSearchProducts(ctx, query)
+	if err != nil {
+		s.LogError(ctx, "Failed to get total search products count", err)
+		return nil, err
+	}
+
+	return &model.ProductSearchList{
+		Products: products,
+		Total:    total,
+	}, nil
+}
@@ -2,43 +2,82 @@ package service
 
 import (
 	"context"
-	"diploma/modules/product/client/nct"
 	"diploma/modules/product/model"
 	"diploma/pkg/client/db"
 	"diploma/pkg/service"
+	"time"
 )
 
 type ProductService struct {
 	service.BaseService
 	productRepository IProductRepository
 	txManager         db.TxManager
-	nctParser         *nct.NCTParser
+	nctClient         INCTClient
 }
 
 func NewService(
 	productRepository IProductRepository,
 	txManager db.TxManager,
-	nctParser *nct.NCTParser,
+	nctClient INCTClient,
 ) *ProductService {
 	return &ProductService{
 		BaseService:       service.NewBaseService("product"),
 		productRepository: productRepository,
 		txManager:         txManager,
-		nctParser:         nctParser,
+		nctClient:         nctClient,
 	}
 }
 
+// INCTClient looks products up in the national catalog (NCT) by GTIN.
+// Lookups are slow and must not run inside a transaction.
+type INCTClient interface {
+	Lookup(ctx context.Context, gtin string) ([]model.Product, error)
+}
+
 type IProductRepository interface {
 	GetProduct(ctx context.Context, id int64) (*model.Product, error)
 	GetProductByGTIN(ctx context.Context, gtin int64) (*model.Product, error)
 	GetSupplierProductListByProduct(ctx context.Context, id int64) ([]model.ProductSupplier, error)
 	GetProductListByIDList(ctx context.Context, idList []int64) ([]*model.Product, error)
 	GetProductList(ctx context.Context, query *model.ProductListQuery) ([]model.Product, error)
-	GetTotalProducts(ctx context.Context) (int, error)
+	GetTotalProducts(ctx context.Context, query *model.ProductListQuery) (int, error)
+	GetProductFacets(ctx context.Context, query *model.ProductListQuery) (*model.ProductFacets, error)
+	SearchProducts(ctx context.Context, query *model.ProductSearchQuery) ([]model.ProductSearchResult, error)
+	GetTotalSearchProducts(ctx context.Context, query *model.ProductSearchQuery) (int, error)
 	GetProductPriceBySupplier(ctx context.Context, productID, supplierID int64) (int, error)
 	CreateProduct(ctx context.Context, product *model.Product) (int64, error)
 
 	CreateProductSupplier(ctx context.Context, supplierID, productID int64, price int) error
+	LockProductSupplier(ctx context.Context, productID, supplierID int64) (*model.Offer, error)
+	UpdateProductSupplier(ctx context.Context, offer *model.Offer) error
+	CreateOffer(ctx context.Context, offer *model.Offer) error
+	IsProductCreator(ctx context.Context, productID, supplierID int64) (bool, error)
+	CreatePriceChange(ctx context.Context, change *model.PriceChange) error
+	GetPriceHistory(ctx context.Context, productID int64, from time.Time) ([]model.PricePoint, error)
+	GetPricesAt(ctx context.Context, productID int64, at time.Time) ([]model.PricePoint, error)
+
+	// Market analytics
+	TryLockPriceAggregates(ctx context.Context) (bool, error)
+	RefreshPriceAggregates(ctx context.Context, from time.Time) error
+	GetDailyPrices(ctx context.Context, from time.Time, categoryID *int) ([]model.DailyPrice, error)
+	GetCategoryByName(ctx context.Context, name string) (*model.Category, error)
+
+	// Attributes and gallery
+	LockProductAttributes(ctx context.Context, productID int64) (*model.ProductAttributes, error)
+	UpdateProductAttributes(ctx context.Context, productID int64, attrs *model.ProductAttributes) error
+	GetProductImages(ctx context.Context, productID int64) ([]string, error)
+	ReplaceProductImages(ctx context.Context, productID int64, images []string) error
+
+	// Bulk imports
+	CreateImport(ctx context.Context, imp *model.Import, rows []model.ImportRow) (int64, error)
+	GetImport(ctx context.Context, id int64) (*model.Import, error)
+	LockNextImportRow(ctx context.Context, status string) (*model.ImportRow, error)
+	LockImportRow(ctx context.Context, importID int64, rowNo int, status string) (*model.ImportRow, error)
+	LockImportRowsByGTIN(ctx context.Context, gtin int64, status string) ([]model.ImportRow, error)
+	ImportGTINs(ctx context.Context, status string, limit uint64) ([]int64, error)
+	UpdateImportRow(ctx context.Context, row *model.ImportRow) error
+	FinishImports(ctx context.Context) error
+	FailedImportRows(ctx context.Context, importID int64) ([]model.ImportRow, error)
 	GetProductListBySupplier(ctx context.Context, supplierID int64, limit, offset int) ([]model.Product, error)
 	GetTotalProductsBySupplier(ctx context.Context, supplierID int64) (int, error)
 
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"diploma/internal/testutils"
+	"diploma/modules/product/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockProductRepository struct {
+	mock.Mock
+}
+
+func (m *mockProductRepository) CreateImport(ctx context.Context, imp *model.Import, rows []model.ImportRow) (int64, error) {
+	args := m.Called(ctx, imp, rows)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockProductRepository) CreateOffer(ctx context.Context, offer *model.Offer) error {
+	args := m.Called(ctx, offer)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) CreatePriceChange(ctx context.Context, change *model.PriceChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) CreateProduct(ctx context.Context, product *model.Product) (int64, error) {
+	args := m.Called(ctx, product)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockProductRepository) CreateProductSupplier(ctx context.Context, supplierID, productID int64, price int) error {
+	args := m.Called(ctx, supplierID, productID, price)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) FailedImportRows(ctx context.Context, importID int64) ([]model.ImportRow, error) {
+	args := m.Called(ctx, importID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ImportRow), args.Error(1)
+}
+
+func (m *mockProductRepository) FindOrCreateCategory(ctx context.Context, name string) (int, error) {
+	args := m.Called(ctx, name)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) FindOrCreateSubcategory(ctx context.Context, name string, categoryID int) (int, error) {
+	args := m.Called(ctx, name, categoryID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) FinishImports(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) GetCategories(ctx context.Context) ([]model.Category, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Category), args.Error(1)
+}
+
+func (m *mockProductRepository) GetCategory(ctx context.Context, id int) (*model.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func (m *mockProductRepository) GetCategoryByName(ctx context.Context, name string) (*model.Category, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Category), args.Error(1)
+}
+
+func (m *mockProductRepository) GetDailyPrices(ctx context.Context, from time.Time, categoryID *int) ([]model.DailyPrice, error) {
+	args := m.Called(ctx, from, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.DailyPrice), args.Error(1)
+}
+
+func (m *mockProductRepository) GetImport(ctx context.Context, id int64) (*model.Import, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Import), args.Error(1)
+}
+
+func (m *mockProductRepository) GetPriceHistory(ctx context.Context, productID int64, from time.Time) ([]model.PricePoint, error) {
+	args := m.Called(ctx, productID, from)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PricePoint), args.Error(1)
+}
+
+func (m *mockProductRepository) GetPricesAt(ctx context.Context, productID int64, at time.Time) ([]model.PricePoint, error) {
+	args := m.Called(ctx, productID, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.PricePoint), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProduct(ctx context.Context, id int64) (*model.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductByGTIN(ctx context.Context, gtin int64) (*model.Product, error) {
+	args := m.Called(ctx, gtin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Product), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductFacets(ctx context.Context, query *model.ProductListQuery) (*model.ProductFacets, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductFacets), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductImages(ctx context.Context, productID int64) ([]string, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductList(ctx context.Context, query *model.ProductListQuery) ([]model.Product, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductListByIDList(ctx context.Context, idList []int64) ([]*model.Product, error) {
+	args := m.Called(ctx, idList)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Product), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductListBySupplier(ctx context.Context, supplierID int64, limit, offset int) ([]model.Product, error) {
+	args := m.Called(ctx, supplierID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
+func (m *mockProductRepository) GetProductPriceBySupplier(ctx context.Context, productID, supplierID int64) (int, error) {
+	args := m.Called(ctx, productID, supplierID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) GetSubcategories(ctx context.Context, categoryID int) ([]model.Subcategory, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Subcategory), args.Error(1)
+}
+
+func (m *mockProductRepository) GetSupplierProductListByProduct(ctx context.Context, id int64) ([]model.ProductSupplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductSupplier), args.Error(1)
+}
+
+func (m *mockProductRepository) GetTotalProducts(ctx context.Context, query *model.ProductListQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) GetTotalProductsBySupplier(ctx context.Context, supplierID int64) (int, error) {
+	args := m.Called(ctx, supplierID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) GetTotalSearchProducts(ctx context.Context, query *model.ProductSearchQuery) (int, error) {
+	args := m.Called(ctx, query)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockProductRepository) ImportGTINs(ctx context.Context, status string, limit uint64) ([]int64, error) {
+	args := m.Called(ctx, status, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *mockProductRepository) IsProductCreator(ctx context.Context, productID, supplierID int64) (bool, error) {
+	args := m.Called(ctx, productID, supplierID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockProductRepository) LockImportRow(ctx context.Context, importID int64, rowNo int, status string) (*model.ImportRow, error) {
+	args := m.Called(ctx, importID, rowNo, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportRow), args.Error(1)
+}
+
+func (m *mockProductRepository) LockImportRowsByGTIN(ctx context.Context, gtin int64, status string) ([]model.ImportRow, error) {
+	args := m.Called(ctx, gtin, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ImportRow), args.Error(1)
+}
+
+func (m *mockProductRepository) LockNextImportRow(ctx context.Context, status string) (*model.ImportRow, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ImportRow), args.Error(1)
+}
+
+func (m *mockProductRepository) LockProductAttributes(ctx context.Context, productID int64) (*model.ProductAttributes, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ProductAttributes), args.Error(1)
+}
+
+func (m *mockProductRepository) LockProductSupplier(ctx context.Context, productID, supplierID int64) (*model.Offer, error) {
+	args := m.Called(ctx, productID, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Offer), args.Error(1)
+}
+
+func (m *mockProductRepository) RefreshPriceAggregates(ctx context.Context, from time.Time) error {
+	args := m.Called(ctx, from)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) ReplaceProductImages(ctx context.Context, productID int64, images []string) error {
+	args := m.Called(ctx, productID, images)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) SearchProducts(ctx context.Context, query *model.ProductSearchQuery) ([]model.ProductSearchResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.ProductSearchResult), args.Error(1)
+}
+
+func (m *mockProductRepository) TryLockPriceAggregates(ctx context.Context) (bool, error) {
+	args := m.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockProductRepository) UpdateImportRow(ctx context.Context, row *model.ImportRow) error {
+	args := m.Called(ctx, row)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) UpdateProductAttributes(ctx context.Context, productID int64, attrs *model.ProductAttributes) error {
+	args := m.Called(ctx, productID, attrs)
+	return args.Error(0)
+}
+
+func (m *mockProductRepository) UpdateProductSupplier(ctx context.Context, offer *model.Offer) error {
+	args := m.Called(ctx, offer)
+	return args.Error(0)
+}
+
+type mockNCTClient struct {
+	mock.Mock
+}
+
+func (m *mockNCTClient) Lookup(ctx context.Context, gtin string) ([]model.Product, error) {
+	args := m.Called(ctx, gtin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Product), args.Error(1)
+}
+
+type ProductServiceTestSuite struct {
+	suite.Suite
+	service   *ProductService
+	repo      *mockProductRepository
+	nctClient *mockNCTClient
+	txManager *testutils.MockTxManager
+	helper    *testutils.AssertTestHelper
+}
+
+func TestProductService(t *testing.T) {
+	suite.Run(t, new(ProductServiceTestSuite))
+}
+
+func (s *ProductServiceTestSuite) SetupTest() {
+	s.repo = new(mockProductRepository)
+	s.nctClient = new(mockNCTClient)
+	s.txManager = new(testutils.MockTxManager)
+	s.helper = testutils.NewAssertTestHelper(s.T())
+
+	s.txManager.On("ReadCommitted", mock.Anything, mock.Anything).Return(nil)
+
+	s.service = NewService(s.repo, s.txManager, s.nctClient)
+}
+
+func (s *ProductServiceTestSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.nctClient.AssertExpectations(s.T())
+}
+
+func strPtr(v string) *string { return &v }
+
+func intPtr(v int) *int { return &v }
@@ -0,0 +1,42 @@
+package order
+
+import (
+	"context"
+	orderModel "diploma/modules/order/model"
+	order "diploma/modules/order/service"
+	"diploma/modules/standing/model"
+)
+
+type OrderClient struct {
+	orderService *order.OrderService
+}
+
+func NewClient(orderService *order.OrderService) *OrderClient {
+	return &OrderClient{orderService: orderService}
+}
+
+func (c *OrderClient) CreateOrder(ctx context.Context, generated *model.GeneratedOrder) (int64, error) {
+	o := &orderModel.Order{
+		CustomerID:      generated.CustomerID,
+		SupplierID:      generated.SupplierID,
+		AddressID:       generated.AddressID,
+		DeliveryAddress: generated.DeliveryAddress,
+		ProductList:     make([]*orderModel.OrderProduct, 0, len(generated.Lines)),
+	}
+	for _, line := range generated.Lines {
+		o.ProductList = append(o.ProductList, &orderModel.OrderProduct{
+			ProductID: line.ProductID,
+			Quantity:  line.Quantity,
+			Price:     line.Price,
+		})
+	}
+
+	if err := c.orderService.CreateOrder(ctx, []*orderModel.Order{o}); err != nil {
+		return 0, err
+	}
+	return o.ID, nil
+}
+
+func (c *OrderClient) CancelOrder(ctx context.Context, customerID, orderID int64) error {
+	return c.orderService.CancelOrderByCustomer(ctx, customerID, orderID)
+}
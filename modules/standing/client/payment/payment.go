@@ -0,0 +1,158 @@
+package payment
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	currency            = "USD"
+	responseSuccess     = "success"
+	orderApproved       = "approved"
+	consentAmount       = "100"
+	signatureParam      = "signature"
+	signatureDebugParam = "response_signature_string"
+)
+
+// PaymentClient talks to the Fondy API: card verification with a saved
+// token (rectoken) and recurring charges with that token.
+type PaymentClient struct {
+	checkoutURL      string
+	recurringURL     string
+	merchantID       string
+	merchantPassword string
+	callbackURL      string
+}
+
+func NewPaymentClient(checkoutURL, recurringURL, merchantID, merchantPassword, callbackURL string) *PaymentClient {
+	return &PaymentClient{
+		checkoutURL:      checkoutURL,
+		recurringURL:     recurringURL,
+		merchantID:       merchantID,
+		merchantPassword: merchantPassword,
+		callbackURL:      callbackURL,
+	}
+}
+
+func (p *PaymentClient) Consent(paymentOrderID, description string) (string, error) {
+	if p.callbackURL == "" {
+		return "", errors.New("consent callback URL is not configured")
+	}
+
+	response, err := p.post(p.checkoutURL, map[string]string{
+		"order_id":            paymentOrderID,
+		"merchant_id":         p.merchantID,
+		"order_desc":          description,
+		"amount":              consentAmount,
+		"currency":            currency,
+		"verification":        "Y",
+		"required_rectoken":   "Y",
+		"server_callback_url": p.callbackURL,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	checkoutURL, ok := response["checkout_url"]
+	if !ok {
+		return "", fmt.Errorf("checkout_url not found in response")
+	}
+	return checkoutURL, nil
+}
+
+func (p *PaymentClient) Charge(paymentOrderID string, amount int, token, description string) error {
+	response, err := p.post(p.recurringURL, map[string]string{
+		"order_id":    paymentOrderID,
+		"merchant_id": p.merchantID,
+		"order_desc":  description,
+		"amount":      strconv.Itoa(amount) + "00",
+		"currency":    currency,
+		"rectoken":    token,
+	})
+	if err != nil {
+		return err
+	}
+
+	if response["order_status"] != orderApproved {
+		return fmt.Errorf("payment declined: %s %s", response["order_status"], response["response_description"])
+	}
+	return nil
+}
+
+// VerifyCallback checks the signature of a server callback.
+func (p *PaymentClient) VerifyCallback(params map[string]string) bool {
+	signature := params[signatureParam]
+	if signature == "" {
+		return false
+	}
+	expected := sign(p.merchantPassword, params)
+	return subtle.ConstantTimeCompare([]byte(signature), []byte(expected)) == 1
+}
+
+func (p *PaymentClient) post(url string, request map[string]string) (map[string]string, error) {
+	request[signatureParam] = sign(p.merchantPassword, request)
+
+	requestBody, err := json.Marshal(map[string]interface{}{"request": request})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding request: %w", err)
+	}
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(string(requestBody)))
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error response from server: %s", string(body))
+	}
+
+	var apiResponse struct {
+		Response map[string]interface{} `json:"response"`
+	}
+	if err = json.Unmarshal(body, &apiResponse); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	response := make(map[string]string, len(apiResponse.Response))
+	for k, v := range apiResponse.Response {
+		response[k] = fmt.Sprint(v)
+	}
+	if response["response_status"] != responseSuccess {
+		return nil, fmt.Errorf("payment request failed: %s", response["error_message"])
+	}
+	return response, nil
+}
+
+// sign builds the Fondy signature: sha1 of the merchant password and the
+// non-empty parameter values ordered by key, joined with "|".
+func sign(password string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k, v := range params {
+		if v == "" || k == signatureParam || k == signatureDebugParam {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := []string{password}
+	for _, k := range keys {
+		values = append(values, params[k])
+	}
+
+	hash := sha1.Sum([]byte(strings.Join(values, "|")))
+	return fmt.Sprintf("%x", hash)
+}
@@ -0,0 +1,28 @@
+package product
+
+import (
+	"context"
+	productModel "diploma/modules/product/model"
+	"diploma/modules/standing/model"
+	"errors"
+)
+
+type ProductClient struct {
+	productService IProductService
+}
+
+func NewClient(productService IProductService) *ProductClient {
+	return &ProductClient{productService: productService}
+}
+
+type IProductService interface {
+	ProductPriceBySupplier(ctx context.Context, productID, supplierID int64) (int, error)
+}
+
+func (c *ProductClient) Price(ctx context.Context, productID, supplierID int64) (int, error) {
+	price, err := c.productService.ProductPriceBySupplier(ctx, productID, supplierID)
+	if errors.Is(err, productModel.ErrNoRows) {
+		return 0, model.ErrProductUnavailable
+	}
+	return price, err
+}
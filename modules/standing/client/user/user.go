@@ -0,0 +1,31 @@
+package user
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+	userModel "diploma/modules/user/model"
+	"errors"
+)
+
+type UserClient struct {
+	userService IUserService
+}
+
+func NewClient(userService IUserService) *UserClient {
+	return &UserClient{userService: userService}
+}
+
+type IUserService interface {
+	AddressByID(ctx context.Context, userID, addressID int64) (userModel.Address, error)
+}
+
+func (c *UserClient) DeliveryAddress(ctx context.Context, userID, addressID int64) (string, error) {
+	address, err := c.userService.AddressByID(ctx, userID, addressID)
+	if errors.Is(err, userModel.ErrAddressNotFound) {
+		return "", model.ErrInvalidAddress
+	}
+	if err != nil {
+		return "", err
+	}
+	return address.FullText(), nil
+}
@@ -0,0 +1,98 @@
+package converter
+
+import (
+	modelApi "diploma/modules/standing/handler/model"
+	"diploma/modules/standing/model"
+	"fmt"
+	"time"
+)
+
+func ToServiceStandingOrderFromAPI(customerID int64, input modelApi.StandingOrderInput) (*model.StandingOrder, error) {
+	t, err := time.Parse("15:04", input.Schedule.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time %q, expected HH:MM", input.Schedule.Time)
+	}
+
+	weekdays := make([]time.Weekday, 0, len(input.Schedule.Weekdays))
+	for _, d := range input.Schedule.Weekdays {
+		weekdays = append(weekdays, time.Weekday(d))
+	}
+
+	items := make([]model.Item, 0, len(input.Items))
+	for _, item := range input.Items {
+		items = append(items, model.Item{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return &model.StandingOrder{
+		CustomerID: customerID,
+		SupplierID: input.SupplierID,
+		Items:      items,
+		Schedule: model.Schedule{
+			Kind:     input.Schedule.Kind,
+			Weekdays: weekdays,
+			Minute:   t.Hour()*60 + t.Minute(),
+		},
+		PaymentMethod: input.PaymentMethod,
+		AddressID:     input.AddressID,
+	}, nil
+}
+
+func ToAPIStandingOrderFromService(order *model.StandingOrder) modelApi.StandingOrder {
+	items := make([]modelApi.Item, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, modelApi.Item{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	var weekdays []int
+	for _, d := range order.Schedule.Weekdays {
+		weekdays = append(weekdays, int(d))
+	}
+
+	return modelApi.StandingOrder{
+		ID:         order.ID,
+		SupplierID: order.SupplierID,
+		Items:      items,
+		Schedule: modelApi.Schedule{
+			Kind:     order.Schedule.Kind,
+			Weekdays: weekdays,
+			Time:     fmt.Sprintf("%02d:%02d", order.Schedule.Minute/60, order.Schedule.Minute%60),
+		},
+		PaymentMethod:   order.PaymentMethod,
+		HasConsent:      order.PaymentToken != "",
+		AddressID:       order.AddressID,
+		DeliveryAddress: order.DeliveryAddress,
+		Status:          order.Status,
+		SkipNext:        order.SkipNext,
+		NextRunAt:       order.NextRunAt.Format(time.RFC3339),
+		CreatedAt:       order.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToAPIStandingOrdersFromService(orders []*model.StandingOrder) []modelApi.StandingOrder {
+	res := make([]modelApi.StandingOrder, 0, len(orders))
+	for _, order := range orders {
+		res = append(res, ToAPIStandingOrderFromService(order))
+	}
+	return res
+}
+
+func ToAPIRunsFromService(runs []model.Run) []modelApi.Run {
+	res := make([]modelApi.Run, 0, len(runs))
+	for _, run := range runs {
+		res = append(res, modelApi.Run{
+			ID:           run.ID,
+			ScheduledFor: run.ScheduledFor.Format(time.RFC3339),
+			Status:       run.Status,
+			OrderID:      run.OrderID,
+			Error:        run.Error,
+			CreatedAt:    run.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return res
+}
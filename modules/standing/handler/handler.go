@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+)
+
+type StandingHandler struct {
+	service IStandingService
+}
+
+func NewHandler(service IStandingService) *StandingHandler {
+	return &StandingHandler{service: service}
+}
+
+type IStandingService interface {
+	CreateStandingOrder(ctx context.Context, order *model.StandingOrder) (int64, error)
+	UpdateStandingOrder(ctx context.Context, order *model.StandingOrder) error
+	StandingOrder(ctx context.Context, customerID, id int64) (*model.StandingOrder, error)
+	StandingOrders(ctx context.Context, customerID int64) ([]*model.StandingOrder, error)
+	Pause(ctx context.Context, customerID, id int64) error
+	Resume(ctx context.Context, customerID, id int64) error
+	SkipNext(ctx context.Context, customerID, id int64, skip bool) error
+	History(ctx context.Context, customerID, id int64, limit, offset int) ([]model.Run, error)
+	Consent(ctx context.Context, customerID, id int64) (string, error)
+	CommitConsent(ctx context.Context, params map[string]string) error
+}
@@ -0,0 +1,74 @@
+package model
+
+import "errors"
+
+var (
+	ErrUnauthorized = errors.New("api: unauthorized")
+)
+
+type ErrorResponse struct {
+	Err string `json:"error"`
+}
+
+type Item struct {
+	ProductID int64 `json:"product_id" binding:"required,min=1"`
+	Quantity  int   `json:"quantity" binding:"required,min=1"`
+}
+
+type Schedule struct {
+	// Kind is one of daily, weekdays or weekly.
+	Kind string `json:"kind" binding:"required,oneof=daily weekdays weekly" example:"weekdays"`
+	// Weekdays are 0 for Sunday through 6 for Saturday; weekly schedules
+	// take exactly one day.
+	Weekdays []int  `json:"weekdays,omitempty" example:"1,3,5"`
+	Time     string `json:"time" binding:"required" example:"08:00"`
+}
+
+type StandingOrderInput struct {
+	SupplierID int64    `json:"supplier_id" binding:"required,min=1"`
+	Items      []Item   `json:"items" binding:"required,min=1,dive"`
+	Schedule   Schedule `json:"schedule" binding:"required"`
+	// PaymentMethod is invoice or saved_consent.
+	PaymentMethod string `json:"payment_method" binding:"required,oneof=invoice saved_consent"`
+	AddressID     int64  `json:"address_id" binding:"required,min=1"`
+}
+
+type CreateStandingOrderResponse struct {
+	ID int64 `json:"id"`
+}
+
+type StandingOrder struct {
+	ID              int64    `json:"id"`
+	SupplierID      int64    `json:"supplier_id"`
+	Items           []Item   `json:"items"`
+	Schedule        Schedule `json:"schedule"`
+	PaymentMethod   string   `json:"payment_method"`
+	HasConsent      bool     `json:"has_consent"`
+	AddressID       int64    `json:"address_id"`
+	DeliveryAddress string   `json:"delivery_address"`
+	Status          string   `json:"status"`
+	SkipNext        bool     `json:"skip_next"`
+	NextRunAt       string   `json:"next_run_at"`
+	CreatedAt       string   `json:"created_at"`
+}
+
+type GetStandingOrdersResponse struct {
+	StandingOrders []StandingOrder `json:"standing_orders"`
+}
+
+type Run struct {
+	ID           int64  `json:"id"`
+	ScheduledFor string `json:"scheduled_for"`
+	Status       string `json:"status"`
+	OrderID      int64  `json:"order_id,omitempty"`
+	Error        string `json:"error,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type GetHistoryResponse struct {
+	Runs []Run `json:"runs"`
+}
+
+type ConsentResponse struct {
+	CheckoutURL string `json:"checkout_url"`
+}
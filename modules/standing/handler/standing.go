@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"bytes"
+	"diploma/modules/auth/jwt"
+	"diploma/modules/standing/handler/converter"
+	modelApi "diploma/modules/standing/handler/model"
+	"diploma/modules/standing/model"
+	contextkeys "diploma/pkg/context-keys"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateStandingOrder godoc
+// @Summary Create standing order
+// @Description Creates a recurring order from supplier lines and a schedule. Orders are generated automatically at the scheduled time.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body modelApi.StandingOrderInput true "Standing order"
+// @Success 200 {object} modelApi.CreateStandingOrderResponse
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order [post]
+func (h *StandingHandler) CreateStandingOrder(c *gin.Context) {
+	claims, ok := customerClaims(c)
+	if !ok {
+		return
+	}
+
+	var input modelApi.StandingOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	order, err := converter.ToServiceStandingOrderFromAPI(claims.UserID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	id, err := h.service.CreateStandingOrder(c.Request.Context(), order)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.CreateStandingOrderResponse{ID: id})
+}
+
+// UpdateStandingOrder godoc
+// @Summary Update standing order
+// @Description Replaces lines, schedule, payment method and delivery address. The supplier cannot be changed.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Param input body modelApi.StandingOrderInput true "Standing order"
+// @Success 200 {object} map[string]string "standing order updated"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order/{id} [put]
+func (h *StandingHandler) UpdateStandingOrder(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	var input modelApi.StandingOrderInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	order, err := converter.ToServiceStandingOrderFromAPI(claims.UserID, input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+	order.ID = id
+
+	if err := h.service.UpdateStandingOrder(c.Request.Context(), order); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "standing order updated"})
+}
+
+// GetStandingOrders godoc
+// @Summary List standing orders
+// @Description Returns the standing orders of the authenticated customer.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} modelApi.GetStandingOrdersResponse
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order [get]
+func (h *StandingHandler) GetStandingOrders(c *gin.Context) {
+	claims, ok := customerClaims(c)
+	if !ok {
+		return
+	}
+
+	orders, err := h.service.StandingOrders(c.Request.Context(), claims.UserID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.GetStandingOrdersResponse{StandingOrders: converter.ToAPIStandingOrdersFromService(orders)})
+}
+
+// GetStandingOrder godoc
+// @Summary Get standing order
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} modelApi.StandingOrder
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order/{id} [get]
+func (h *StandingHandler) GetStandingOrder(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.StandingOrder(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, converter.ToAPIStandingOrderFromService(order))
+}
+
+// PauseStandingOrder godoc
+// @Summary Pause standing order
+// @Description No orders are generated until the standing order is resumed.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} map[string]string "standing order paused"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Router /api/standing-order/{id}/pause [post]
+func (h *StandingHandler) PauseStandingOrder(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Pause(c.Request.Context(), claims.UserID, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "standing order paused"})
+}
+
+// ResumeStandingOrder godoc
+// @Summary Resume standing order
+// @Description Runs missed while paused are not made up; the schedule continues from now.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} map[string]string "standing order resumed"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Router /api/standing-order/{id}/resume [post]
+func (h *StandingHandler) ResumeStandingOrder(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Resume(c.Request.Context(), claims.UserID, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "standing order resumed"})
+}
+
+// SkipNextRun godoc
+// @Summary Skip next run
+// @Description The next scheduled run is recorded as skipped and no order is generated.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} map[string]string "next run skipped"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Router /api/standing-order/{id}/skip [post]
+func (h *StandingHandler) SkipNextRun(c *gin.Context) {
+	h.setSkipNext(c, true, "next run skipped")
+}
+
+// CancelSkipNextRun godoc
+// @Summary Cancel skipping the next run
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} map[string]string "skip cancelled"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Router /api/standing-order/{id}/skip [delete]
+func (h *StandingHandler) CancelSkipNextRun(c *gin.Context) {
+	h.setSkipNext(c, false, "skip cancelled")
+}
+
+func (h *StandingHandler) setSkipNext(c *gin.Context, skip bool, message string) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SkipNext(c.Request.Context(), claims.UserID, id, skip); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// GetHistory godoc
+// @Summary Standing order history
+// @Description Returns the scheduled runs, newest first, with the generated order or the reason it was skipped or failed.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} modelApi.GetHistoryResponse
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order/{id}/history [get]
+func (h *StandingHandler) GetHistory(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	runs, err := h.service.History(c.Request.Context(), claims.UserID, id, limit, offset)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.GetHistoryResponse{Runs: converter.ToAPIRunsFromService(runs)})
+}
+
+// RequestConsent godoc
+// @Summary Request recurring payment consent
+// @Description Starts a card verification; after it succeeds, orders of a standing order with payment_method saved_consent are charged to this card.
+// @Tags standing-order
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Standing order ID"
+// @Success 200 {object} modelApi.ConsentResponse
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid standing order ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Standing order not found"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/standing-order/{id}/consent [post]
+func (h *StandingHandler) RequestConsent(c *gin.Context) {
+	claims, id, ok := customerClaimsAndID(c)
+	if !ok {
+		return
+	}
+
+	checkoutURL, err := h.service.Consent(c.Request.Context(), claims.UserID, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.ConsentResponse{CheckoutURL: checkoutURL})
+}
+
+func (h *StandingHandler) ConsentCallback(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid JSON"})
+		return
+	}
+
+	// numbers are kept as written so that the signature can be checked
+	var data map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "Invalid JSON"})
+		return
+	}
+
+	params := make(map[string]string, len(data))
+	for k, v := range data {
+		if v != nil {
+			params[k] = fmt.Sprint(v)
+		}
+	}
+
+	if err := h.service.CommitConsent(c.Request.Context(), params); err != nil {
+		if errors.Is(err, model.ErrInvalidCallback) {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "JSON received"})
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, model.ErrInvalidSchedule),
+		errors.Is(err, model.ErrInvalidStandingOrder),
+		errors.Is(err, model.ErrInvalidAddress),
+		errors.Is(err, model.ErrProductUnavailable):
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+	case errors.Is(err, model.ErrForbidden):
+		c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: err.Error()})
+	case errors.Is(err, model.ErrNoRows):
+		c.JSON(http.StatusNotFound, modelApi.ErrorResponse{Err: "standing order not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+	}
+}
+
+// customerClaims returns the claims of an authenticated customer, writing
+// the error response otherwise.
+func customerClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: modelApi.ErrUnauthorized.Error()})
+		return nil, false
+	}
+	if claims.Role != model.CustomerRole {
+		c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: "only customers can manage standing orders"})
+		return nil, false
+	}
+	return claims, true
+}
+
+func customerClaimsAndID(c *gin.Context) (*jwt.Claims, int64, bool) {
+	claims, ok := customerClaims(c)
+	if !ok {
+		return nil, 0, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid standing order ID"})
+		return nil, 0, false
+	}
+	return claims, id, true
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+const (
+	ScheduleDaily    = "daily"
+	ScheduleWeekdays = "weekdays"
+	ScheduleWeekly   = "weekly"
+)
+
+// Schedule says when a standing order runs: every day, on selected days of
+// the week, or once a week, always at Minute minutes after local midnight.
+type Schedule struct {
+	Kind string
+	// Weekdays is used by ScheduleWeekdays and ScheduleWeekly; weekly
+	// schedules have exactly one day.
+	Weekdays []time.Weekday
+	Minute   int
+}
+
+func (s Schedule) Validate() error {
+	if s.Minute < 0 || s.Minute >= 24*60 {
+		return ErrInvalidSchedule
+	}
+	for _, d := range s.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return ErrInvalidSchedule
+		}
+	}
+
+	switch s.Kind {
+	case ScheduleDaily:
+		return nil
+	case ScheduleWeekdays:
+		if len(s.Weekdays) == 0 {
+			return ErrInvalidSchedule
+		}
+		return nil
+	case ScheduleWeekly:
+		if len(s.Weekdays) != 1 {
+			return ErrInvalidSchedule
+		}
+		return nil
+	default:
+		return ErrInvalidSchedule
+	}
+}
+
+// Next returns the first run strictly after t.
+func (s Schedule) Next(t time.Time) time.Time {
+	t = t.In(time.Local)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	for i := 0; i <= 7; i++ {
+		d := day.AddDate(0, 0, i)
+		run := d.Add(time.Duration(s.Minute) * time.Minute)
+		if run.After(t) && s.runsOn(d.Weekday()) {
+			return run
+		}
+	}
+	// unreachable for a valid schedule
+	return time.Time{}
+}
+
+func (s Schedule) runsOn(d time.Weekday) bool {
+	if s.Kind == ScheduleDaily {
+		return true
+	}
+	for _, w := range s.Weekdays {
+		if w == d {
+			return true
+		}
+	}
+	return false
+}
+
+// WeekdayMask packs the weekdays into a bit mask, bit 0 being Sunday.
+func (s Schedule) WeekdayMask() int {
+	mask := 0
+	for _, d := range s.Weekdays {
+		mask |= 1 << uint(d)
+	}
+	return mask
+}
+
+// WeekdaysFromMask is the inverse of Schedule.WeekdayMask.
+func WeekdaysFromMask(mask int) []time.Weekday {
+	var days []time.Weekday
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if mask&(1<<uint(d)) != 0 {
+			days = append(days, d)
+		}
+	}
+	return days
+}
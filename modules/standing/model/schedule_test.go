@@ -0,0 +1,67 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleNext(t *testing.T) {
+	// Wednesday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.July, day, hour, minute, 0, 0, time.Local)
+	}
+	now := at(16, 10, 0)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		want     time.Time
+	}{
+		{
+			name:     "daily later today",
+			schedule: Schedule{Kind: ScheduleDaily, Minute: 18 * 60},
+			want:     at(16, 18, 0),
+		},
+		{
+			name:     "daily already passed today",
+			schedule: Schedule{Kind: ScheduleDaily, Minute: 8 * 60},
+			want:     at(17, 8, 0),
+		},
+		{
+			name:     "daily exactly now runs tomorrow",
+			schedule: Schedule{Kind: ScheduleDaily, Minute: 10 * 60},
+			want:     at(17, 10, 0),
+		},
+		{
+			name:     "weekdays picks the next selected day",
+			schedule: Schedule{Kind: ScheduleWeekdays, Weekdays: []time.Weekday{time.Monday, time.Friday}, Minute: 9 * 60},
+			want:     at(18, 9, 0),
+		},
+		{
+			name:     "weekly on the same weekday already passed",
+			schedule: Schedule{Kind: ScheduleWeekly, Weekdays: []time.Weekday{time.Wednesday}, Minute: 9 * 60},
+			want:     at(23, 9, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schedule.Next(now))
+		})
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	assert.NoError(t, Schedule{Kind: ScheduleDaily, Minute: 0}.Validate())
+	assert.ErrorIs(t, Schedule{Kind: ScheduleDaily, Minute: 24 * 60}.Validate(), ErrInvalidSchedule)
+	assert.ErrorIs(t, Schedule{Kind: ScheduleWeekdays}.Validate(), ErrInvalidSchedule)
+	assert.ErrorIs(t, Schedule{Kind: ScheduleWeekly, Weekdays: []time.Weekday{time.Monday, time.Tuesday}}.Validate(), ErrInvalidSchedule)
+	assert.ErrorIs(t, Schedule{Kind: "monthly"}.Validate(), ErrInvalidSchedule)
+}
+
+func TestWeekdayMask(t *testing.T) {
+	s := Schedule{Weekdays: []time.Weekday{time.Sunday, time.Wednesday, time.Saturday}}
+	assert.Equal(t, s.Weekdays, WeekdaysFromMask(s.WeekdayMask()))
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrNoRows = errors.New("models: no rows")
+
+	ErrInvalidSchedule = errors.New("models: invalid schedule")
+
+	ErrInvalidStandingOrder = errors.New("models: invalid standing order")
+
+	ErrInvalidAddress = errors.New("models: address does not belong to the user")
+
+	ErrConsentRequired = errors.New("models: payment consent is required")
+
+	ErrForbidden = errors.New("models: standing order belongs to another customer")
+
+	ErrProductUnavailable = errors.New("models: product is not offered by the supplier")
+
+	ErrInvalidCallback = errors.New("models: invalid payment callback")
+)
+
+const (
+	CustomerRole = iota
+	SupplierRole
+	AdminRole
+)
+
+const (
+	StatusActive = "active"
+	StatusPaused = "paused"
+)
+
+const (
+	// PaymentInvoice creates the order unpaid; the customer pays by the
+	// order invoice.
+	PaymentInvoice = "invoice"
+	// PaymentSavedConsent charges the card saved when the customer gave
+	// consent to recurring payments.
+	PaymentSavedConsent = "saved_consent"
+)
+
+const (
+	RunCreated = "created"
+	RunSkipped = "skipped"
+	RunFailed  = "failed"
+)
+
+// StandingOrder is a template of supplier lines that is turned into a real
+// order on every scheduled run.
+type StandingOrder struct {
+	ID         int64
+	CustomerID int64
+	SupplierID int64
+	Items      []Item
+	Schedule   Schedule
+
+	PaymentMethod string
+	// PaymentToken is the card token for PaymentSavedConsent.
+	PaymentToken string
+	ConsentAt    time.Time
+
+	AddressID       int64
+	DeliveryAddress string
+
+	Status    string
+	SkipNext  bool
+	NextRunAt time.Time
+	CreatedAt time.Time
+}
+
+type Item struct {
+	ProductID int64
+	Quantity  int
+}
+
+func (o *StandingOrder) Validate() error {
+	if len(o.Items) == 0 {
+		return ErrInvalidStandingOrder
+	}
+	seen := make(map[int64]bool, len(o.Items))
+	for _, item := range o.Items {
+		if item.ProductID <= 0 || item.Quantity <= 0 || seen[item.ProductID] {
+			return ErrInvalidStandingOrder
+		}
+		seen[item.ProductID] = true
+	}
+	if o.PaymentMethod != PaymentInvoice && o.PaymentMethod != PaymentSavedConsent {
+		return ErrInvalidStandingOrder
+	}
+	return o.Schedule.Validate()
+}
+
+// CanCharge reports whether the order can be paid on the run.
+func (o *StandingOrder) CanCharge() bool {
+	return o.PaymentMethod == PaymentInvoice || o.PaymentToken != ""
+}
+
+// Run is one scheduled generation of a standing order.
+type Run struct {
+	ID              int64
+	StandingOrderID int64
+	ScheduledFor    time.Time
+	Status          string
+	OrderID         int64
+	Error           string
+	CreatedAt       time.Time
+}
+
+// Line is an order line priced at the time of the run.
+type Line struct {
+	ProductID int64
+	Quantity  int
+	Price     int
+}
+
+// GeneratedOrder is the order produced by a run.
+type GeneratedOrder struct {
+	CustomerID      int64
+	SupplierID      int64
+	AddressID       int64
+	DeliveryAddress string
+	Lines           []Line
+}
+
+func (g *GeneratedOrder) Amount() int {
+	total := 0
+	for _, line := range g.Lines {
+		total += line.Price * line.Quantity
+	}
+	return total
+}
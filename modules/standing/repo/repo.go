@@ -0,0 +1,48 @@
+package repository
+
+import "diploma/pkg/client/db"
+
+type standingRepo struct {
+	db db.Client
+}
+
+const (
+	// ======== standing orders ========
+	standingOrderTbl     = "standing_orders"
+	soIDCol              = "id"
+	soCustomerIDCol      = "customer_id"
+	soSupplierIDCol      = "supplier_id"
+	soScheduleKindCol    = "schedule_kind"
+	soWeekdaysCol        = "weekdays"
+	soRunMinuteCol       = "run_minute"
+	soPaymentMethodCol   = "payment_method"
+	soPaymentTokenCol    = "payment_token"
+	soConsentAtCol       = "consent_at"
+	soAddressIDCol       = "address_id"
+	soDeliveryAddressCol = "delivery_address"
+	soStatusCol          = "status"
+	soSkipNextCol        = "skip_next"
+	soNextRunAtCol       = "next_run_at"
+	soCreatedAtCol       = "created_at"
+	soUpdatedAtCol       = "updated_at"
+
+	// ======== standing order items ========
+	standingItemTbl    = "standing_order_items"
+	siStandingOrderCol = "standing_order_id"
+	siProductIDCol     = "product_id"
+	siQuantityCol      = "quantity"
+
+	// ======== standing order runs ========
+	standingRunTbl       = "standing_order_runs"
+	srIDCol              = "id"
+	srStandingOrderIDCol = "standing_order_id"
+	srScheduledForCol    = "scheduled_for"
+	srStatusCol          = "status"
+	srOrderIDCol         = "order_id"
+	srErrorCol           = "error"
+	srCreatedAtCol       = "created_at"
+)
+
+func NewRepository(db db.Client) *standingRepo {
+	return &standingRepo{db: db}
+}
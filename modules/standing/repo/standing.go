@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"diploma/modules/standing/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+var standingOrderColumns = []string{
+	soIDCol, soCustomerIDCol, soSupplierIDCol, soScheduleKindCol, soWeekdaysCol, soRunMinuteCol,
+	soPaymentMethodCol, soPaymentTokenCol, soConsentAtCol, soAddressIDCol, soDeliveryAddressCol,
+	soStatusCol, soSkipNextCol, soNextRunAtCol, soCreatedAtCol,
+}
+
+func (r *standingRepo) CreateStandingOrder(ctx context.Context, order *model.StandingOrder) (int64, error) {
+	builder := sq.
+		Insert(standingOrderTbl).
+		Columns(
+			soCustomerIDCol, soSupplierIDCol, soScheduleKindCol, soWeekdaysCol, soRunMinuteCol,
+			soPaymentMethodCol, soAddressIDCol, soDeliveryAddressCol, soStatusCol, soNextRunAtCol,
+		).
+		Values(
+			order.CustomerID, order.SupplierID, order.Schedule.Kind, order.Schedule.WeekdayMask(), order.Schedule.Minute,
+			order.PaymentMethod, nullID(order.AddressID), order.DeliveryAddress, order.Status, order.NextRunAt,
+		).
+		Suffix("RETURNING " + soIDCol).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	q := db.Query{
+		Name:     "standing_repository.CreateStandingOrder",
+		QueryRaw: query,
+	}
+
+	var id int64
+	if err := r.db.DB().QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// UpdateStandingOrder replaces the schedule, payment method and delivery
+// address. Switching away from saved consent does not drop the stored
+// card token, so the customer can switch back without a new consent.
+func (r *standingRepo) UpdateStandingOrder(ctx context.Context, order *model.StandingOrder) error {
+	builder := sq.
+		Update(standingOrderTbl).
+		Set(soScheduleKindCol, order.Schedule.Kind).
+		Set(soWeekdaysCol, order.Schedule.WeekdayMask()).
+		Set(soRunMinuteCol, order.Schedule.Minute).
+		Set(soPaymentMethodCol, order.PaymentMethod).
+		Set(soAddressIDCol, nullID(order.AddressID)).
+		Set(soDeliveryAddressCol, order.DeliveryAddress).
+		Set(soNextRunAtCol, order.NextRunAt).
+		Set(soUpdatedAtCol, sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{soIDCol: order.ID}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.UpdateStandingOrder", builder)
+}
+
+// SetStatus pauses or resumes the standing order. nextRunAt is only
+// meaningful when resuming.
+func (r *standingRepo) SetStatus(ctx context.Context, id int64, status string, nextRunAt time.Time) error {
+	builder := sq.
+		Update(standingOrderTbl).
+		Set(soStatusCol, status).
+		Set(soNextRunAtCol, nextRunAt).
+		Set(soUpdatedAtCol, sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{soIDCol: id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.SetStatus", builder)
+}
+
+func (r *standingRepo) SetSkipNext(ctx context.Context, id int64, skip bool) error {
+	builder := sq.
+		Update(standingOrderTbl).
+		Set(soSkipNextCol, skip).
+		Set(soUpdatedAtCol, sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{soIDCol: id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.SetSkipNext", builder)
+}
+
+func (r *standingRepo) SetPaymentToken(ctx context.Context, id int64, token string, consentAt time.Time) error {
+	builder := sq.
+		Update(standingOrderTbl).
+		Set(soPaymentTokenCol, token).
+		Set(soConsentAtCol, consentAt).
+		Set(soUpdatedAtCol, sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{soIDCol: id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.SetPaymentToken", builder)
+}
+
+// Advance moves the standing order to its next run and clears the
+// skip-next flag, which only ever applies to a single run.
+func (r *standingRepo) Advance(ctx context.Context, id int64, nextRunAt time.Time) error {
+	builder := sq.
+		Update(standingOrderTbl).
+		Set(soNextRunAtCol, nextRunAt).
+		Set(soSkipNextCol, false).
+		Where(sq.Eq{soIDCol: id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.Advance", builder)
+}
+
+func (r *standingRepo) StandingOrderByID(ctx context.Context, id int64) (*model.StandingOrder, error) {
+	builder := sq.
+		Select(standingOrderColumns...).
+		From(standingOrderTbl).
+		Where(sq.Eq{soIDCol: id}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.queryOne(ctx, "standing_repository.StandingOrderByID", builder)
+}
+
+func (r *standingRepo) StandingOrdersByCustomer(ctx context.Context, customerID int64) ([]*model.StandingOrder, error) {
+	builder := sq.
+		Select(standingOrderColumns...).
+		From(standingOrderTbl).
+		Where(sq.Eq{soCustomerIDCol: customerID}).
+		OrderBy(soIDCol + " DESC").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "standing_repository.StandingOrdersByCustomer",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*model.StandingOrder
+	for rows.Next() {
+		order, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+// DueIDs returns active standing orders whose next run is at or before now.
+func (r *standingRepo) DueIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error) {
+	builder := sq.
+		Select(soIDCol).
+		From(standingOrderTbl).
+		Where(sq.Eq{soStatusCol: model.StatusActive}).
+		Where(sq.LtOrEq{soNextRunAtCol: now}).
+		OrderBy(soNextRunAtCol).
+		Limit(limit).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "standing_repository.DueIDs",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// LockDue locks the standing order for the duration of the transaction if
+// it is still due. Rows already locked by another instance are skipped, so
+// model.ErrNoRows means there is nothing to do.
+func (r *standingRepo) LockDue(ctx context.Context, id int64, now time.Time) (*model.StandingOrder, error) {
+	builder := sq.
+		Select(standingOrderColumns...).
+		From(standingOrderTbl).
+		Where(sq.Eq{soIDCol: id, soStatusCol: model.StatusActive}).
+		Where(sq.LtOrEq{soNextRunAtCol: now}).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar)
+
+	return r.queryOne(ctx, "standing_repository.LockDue", builder)
+}
+
+func (r *standingRepo) ReplaceItems(ctx context.Context, standingOrderID int64, items []model.Item) error {
+	deleteBuilder := sq.
+		Delete(standingItemTbl).
+		Where(sq.Eq{siStandingOrderCol: standingOrderID}).
+		PlaceholderFormat(sq.Dollar)
+
+	if err := r.exec(ctx, "standing_repository.DeleteItems", deleteBuilder); err != nil && !errors.Is(err, model.ErrNoRows) {
+		return err
+	}
+
+	insertBuilder := sq.
+		Insert(standingItemTbl).
+		Columns(siStandingOrderCol, siProductIDCol, siQuantityCol).
+		PlaceholderFormat(sq.Dollar)
+	for _, item := range items {
+		insertBuilder = insertBuilder.Values(standingOrderID, item.ProductID, item.Quantity)
+	}
+
+	return r.exec(ctx, "standing_repository.CreateItems", insertBuilder)
+}
+
+func (r *standingRepo) Items(ctx context.Context, standingOrderID int64) ([]model.Item, error) {
+	builder := sq.
+		Select(siProductIDCol, siQuantityCol).
+		From(standingItemTbl).
+		Where(sq.Eq{siStandingOrderCol: standingOrderID}).
+		OrderBy(siProductIDCol).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "standing_repository.Items",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.Item
+	for rows.Next() {
+		var item model.Item
+		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *standingRepo) CreateRun(ctx context.Context, run *model.Run) error {
+	builder := sq.
+		Insert(standingRunTbl).
+		Columns(srStandingOrderIDCol, srScheduledForCol, srStatusCol, srOrderIDCol, srErrorCol).
+		Values(run.StandingOrderID, run.ScheduledFor, run.Status, nullID(run.OrderID), run.Error).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.CreateRun", builder)
+}
+
+// FailRun marks the run that created the order as failed.
+func (r *standingRepo) FailRun(ctx context.Context, standingOrderID, orderID int64, reason string) error {
+	builder := sq.
+		Update(standingRunTbl).
+		Set(srStatusCol, model.RunFailed).
+		Set(srErrorCol, reason).
+		Where(sq.Eq{
+			srStandingOrderIDCol: standingOrderID,
+			srOrderIDCol:         orderID,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	return r.exec(ctx, "standing_repository.FailRun", builder)
+}
+
+func (r *standingRepo) Runs(ctx context.Context, standingOrderID int64, limit, offset uint64) ([]model.Run, error) {
+	builder := sq.
+		Select(srIDCol, srStandingOrderIDCol, srScheduledForCol, srStatusCol, srOrderIDCol, srErrorCol, srCreatedAtCol).
+		From(standingRunTbl).
+		Where(sq.Eq{srStandingOrderIDCol: standingOrderID}).
+		OrderBy(srScheduledForCol+" DESC", srIDCol+" DESC").
+		Limit(limit).
+		Offset(offset).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "standing_repository.Runs",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []model.Run
+	for rows.Next() {
+		var (
+			run     model.Run
+			orderID sql.NullInt64
+		)
+		err := rows.Scan(&run.ID, &run.StandingOrderID, &run.ScheduledFor, &run.Status, &orderID, &run.Error, &run.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		run.OrderID = orderID.Int64
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *standingRepo) queryOne(ctx context.Context, name string, builder sq.SelectBuilder) (*model.StandingOrder, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     name,
+		QueryRaw: query,
+	}
+
+	order, err := scanStandingOrder(r.db.DB().QueryRowContext(ctx, q, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, model.ErrNoRows
+	}
+	return order, err
+}
+
+func (r *standingRepo) exec(ctx context.Context, name string, builder sq.Sqlizer) error {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	q := db.Query{
+		Name:     name,
+		QueryRaw: query,
+	}
+
+	tag, err := r.db.DB().ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNoRows
+	}
+	return nil
+}
+
+func scanStandingOrder(row pgx.Row) (*model.StandingOrder, error) {
+	var (
+		order     model.StandingOrder
+		weekdays  int
+		consentAt sql.NullTime
+		addressID sql.NullInt64
+	)
+	err := row.Scan(
+		&order.ID,
+		&order.CustomerID,
+		&order.SupplierID,
+		&order.Schedule.Kind,
+		&weekdays,
+		&order.Schedule.Minute,
+		&order.PaymentMethod,
+		&order.PaymentToken,
+		&consentAt,
+		&addressID,
+		&order.DeliveryAddress,
+		&order.Status,
+		&order.SkipNext,
+		&order.NextRunAt,
+		&order.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	order.Schedule.Weekdays = model.WeekdaysFromMask(weekdays)
+	order.ConsentAt = consentAt.Time
+	order.AddressID = addressID.Int64
+	return &order, nil
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
@@ -0,0 +1,30 @@
+package standing
+
+import (
+	"diploma/modules/standing/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, h *handler.StandingHandler) {
+	standingRoutes := router.Group("/standing-order")
+	{
+		standingRoutes.POST("", h.CreateStandingOrder)
+		standingRoutes.GET("", h.GetStandingOrders)
+		standingRoutes.GET("/:id", h.GetStandingOrder)
+		standingRoutes.PUT("/:id", h.UpdateStandingOrder)
+		standingRoutes.POST("/:id/pause", h.PauseStandingOrder)
+		standingRoutes.POST("/:id/resume", h.ResumeStandingOrder)
+		standingRoutes.POST("/:id/skip", h.SkipNextRun)
+		standingRoutes.DELETE("/:id/skip", h.CancelSkipNextRun)
+		standingRoutes.GET("/:id/history", h.GetHistory)
+		standingRoutes.POST("/:id/consent", h.RequestConsent)
+	}
+}
+
+func RegisterRoutesCallback(router *gin.RouterGroup, h *handler.StandingHandler) {
+	callbackRoutes := router.Group("/callback")
+	{
+		callbackRoutes.POST("/standing-order", h.ConsentCallback)
+	}
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	consentOrderPrefix    = "standing-consent"
+	paymentStatusApproved = "approved"
+)
+
+// Consent starts the card verification that lets the standing order be
+// charged without the customer. The card token arrives later in
+// CommitConsent.
+func (s *StandingService) Consent(ctx context.Context, customerID, id int64) (string, error) {
+	if _, err := s.owned(ctx, customerID, id); err != nil {
+		return "", err
+	}
+
+	paymentOrderID := fmt.Sprintf("%s-%d-%d", consentOrderPrefix, id, time.Now().Unix())
+	checkoutURL, err := s.paymentClient.Consent(paymentOrderID, "Recurring payment consent")
+	if err != nil {
+		s.LogError(ctx, "Failed to request payment consent", err, zap.Int64("standing_order_id", id))
+		return "", err
+	}
+	return checkoutURL, nil
+}
+
+// CommitConsent handles the payment provider callback of a consent
+// verification and stores the card token.
+func (s *StandingService) CommitConsent(ctx context.Context, params map[string]string) error {
+	if !s.paymentClient.VerifyCallback(params) {
+		return model.ErrInvalidCallback
+	}
+
+	id, err := consentStandingOrderID(params["order_id"])
+	if err != nil {
+		return err
+	}
+	if params["order_status"] != paymentStatusApproved || params["rectoken"] == "" {
+		s.LogWarn(ctx, "Payment consent was not approved",
+			zap.Int64("standing_order_id", id),
+			zap.String("order_status", params["order_status"]),
+		)
+		return nil
+	}
+
+	if err := s.repo.SetPaymentToken(ctx, id, params["rectoken"], time.Now()); err != nil {
+		return err
+	}
+
+	s.LogInfo(ctx, "Payment consent saved", zap.Int64("standing_order_id", id))
+	return nil
+}
+
+func consentStandingOrderID(paymentOrderID string) (int64, error) {
+	parts := strings.Split(strings.TrimPrefix(paymentOrderID, consentOrderPrefix+"-"), "-")
+	if !strings.HasPrefix(paymentOrderID, consentOrderPrefix+"-") || len(parts) != 2 {
+		return 0, model.ErrInvalidCallback
+	}
+	id, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, model.ErrInvalidCallback
+	}
+	return id, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// dueBatchSize bounds the number of standing orders handled per tick.
+const dueBatchSize = 100
+
+// Start runs due standing orders every interval until ctx is cancelled.
+func (s *StandingService) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx, time.Now()); err != nil {
+			s.LogError(ctx, "Failed to run standing orders", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue generates orders for every standing order due at now. Each
+// standing order is handled in its own transaction holding a row lock, so
+// several application instances can run the scheduler side by side.
+func (s *StandingService) RunDue(ctx context.Context, now time.Time) error {
+	ids, err := s.repo.DueIDs(ctx, now, dueBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := s.runOne(ctx, id, now); err != nil {
+			s.LogError(ctx, "Failed to run standing order", err, zap.Int64("standing_order_id", id))
+		}
+	}
+	return nil
+}
+
+// runOne generates the order in a transaction and charges the saved card
+// only after it is committed, so a rolled back order is never charged.
+func (s *StandingService) runOne(ctx context.Context, id int64, now time.Time) error {
+	var (
+		order     *model.StandingOrder
+		generated *model.GeneratedOrder
+		run       *model.Run
+		runErr    error
+		taken     bool
+	)
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		var errTx error
+		order, errTx = s.repo.LockDue(ctx, id, now)
+		if errTx != nil {
+			// taken by another instance or no longer due
+			taken = errors.Is(errTx, model.ErrNoRows)
+			return errTx
+		}
+
+		run = &model.Run{
+			StandingOrderID: id,
+			ScheduledFor:    order.NextRunAt,
+		}
+		switch {
+		case order.SkipNext:
+			run.Status = model.RunSkipped
+		case !order.CanCharge():
+			run.Status = model.RunFailed
+			run.Error = model.ErrConsentRequired.Error()
+		default:
+			run.OrderID, generated, errTx = s.generate(ctx, order)
+			if errTx != nil {
+				// the order is rolled back; the failure is recorded in a
+				// separate transaction below
+				runErr = errTx
+				return errTx
+			}
+			run.Status = model.RunCreated
+		}
+
+		if errTx = s.repo.CreateRun(ctx, run); errTx != nil {
+			return errTx
+		}
+		return s.repo.Advance(ctx, id, order.Schedule.Next(now))
+	})
+	if taken {
+		return nil
+	}
+	if runErr == nil {
+		if err != nil || run.Status != model.RunCreated {
+			return err
+		}
+		return s.charge(ctx, order, run.OrderID, generated)
+	}
+
+	s.LogWarn(ctx, "Standing order run failed",
+		zap.Int64("standing_order_id", id),
+		zap.Error(runErr),
+	)
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, errTx := s.repo.LockDue(ctx, id, now)
+		if errTx != nil {
+			return errTx
+		}
+		errTx = s.repo.CreateRun(ctx, &model.Run{
+			StandingOrderID: id,
+			ScheduledFor:    order.NextRunAt,
+			Status:          model.RunFailed,
+			Error:           runErr.Error(),
+		})
+		if errTx != nil {
+			return errTx
+		}
+		return s.repo.Advance(ctx, id, order.Schedule.Next(now))
+	})
+}
+
+// generate prices the lines at the supplier's current prices and creates
+// the order.
+func (s *StandingService) generate(ctx context.Context, order *model.StandingOrder) (int64, *model.GeneratedOrder, error) {
+	items, err := s.repo.Items(ctx, order.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	generated := &model.GeneratedOrder{
+		CustomerID:      order.CustomerID,
+		SupplierID:      order.SupplierID,
+		AddressID:       order.AddressID,
+		DeliveryAddress: order.DeliveryAddress,
+		Lines:           make([]model.Line, 0, len(items)),
+	}
+	for _, item := range items {
+		price, err := s.productClient.Price(ctx, item.ProductID, order.SupplierID)
+		if err != nil {
+			return 0, nil, fmt.Errorf("product %d: %w", item.ProductID, err)
+		}
+		generated.Lines = append(generated.Lines, model.Line{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Price:     price,
+		})
+	}
+	if len(generated.Lines) == 0 {
+		return 0, nil, model.ErrInvalidStandingOrder
+	}
+
+	orderID, err := s.orderClient.CreateOrder(ctx, generated)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	s.LogInfo(ctx, "Standing order generated an order",
+		zap.Int64("standing_order_id", order.ID),
+		zap.Int64("order_id", orderID),
+		zap.Int("amount", generated.Amount()),
+	)
+	return orderID, generated, nil
+}
+
+// charge charges the saved card for a committed order. The payment order
+// ID is derived from the standing order and the order, so the provider
+// rejects a repeated charge. A failed charge marks the run failed and
+// cancels the order, so the supplier is not left with an unpaid order.
+func (s *StandingService) charge(ctx context.Context, order *model.StandingOrder, orderID int64, generated *model.GeneratedOrder) error {
+	if order.PaymentMethod != model.PaymentSavedConsent {
+		return nil
+	}
+
+	paymentOrderID := fmt.Sprintf("standing-%d-order-%d", order.ID, orderID)
+	description := fmt.Sprintf("Standing order %d", order.ID)
+	err := s.paymentClient.Charge(paymentOrderID, generated.Amount(), order.PaymentToken, description)
+	if err == nil {
+		return nil
+	}
+
+	s.LogWarn(ctx, "Standing order charge failed",
+		zap.Int64("standing_order_id", order.ID),
+		zap.Int64("order_id", orderID),
+		zap.Error(err),
+	)
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		if errTx := s.repo.FailRun(ctx, order.ID, orderID, err.Error()); errTx != nil {
+			return errTx
+		}
+		return s.orderClient.CancelOrder(ctx, order.CustomerID, orderID)
+	})
+}
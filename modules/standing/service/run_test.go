@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"diploma/modules/standing/model"
+
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testStandingOrderID = int64(1)
+	testCustomerID      = int64(20)
+	testSupplierID      = int64(10)
+	testOrderID         = int64(55)
+)
+
+var testNow = time.Date(2025, time.September, 1, 9, 0, 0, 0, time.Local)
+
+func newTestStandingOrder() *model.StandingOrder {
+	return &model.StandingOrder{
+		ID:            testStandingOrderID,
+		CustomerID:    testCustomerID,
+		SupplierID:    testSupplierID,
+		Schedule:      model.Schedule{Kind: model.ScheduleDaily, Minute: 8 * 60},
+		PaymentMethod: model.PaymentSavedConsent,
+		PaymentToken:  "card-token",
+		Status:        model.StatusActive,
+		NextRunAt:     testNow.Add(-time.Hour),
+	}
+}
+
+// expectGenerated expects the standing order to be locked and its order
+// generated: 2 x 100 of product 5.
+func (s *StandingServiceTestSuite) expectGenerated() {
+	s.repo.On("LockDue", mock.Anything, testStandingOrderID, testNow).Return(newTestStandingOrder(), nil).Once()
+	s.repo.On("Items", mock.Anything, testStandingOrderID).Return([]model.Item{{ProductID: 5, Quantity: 2}}, nil).Once()
+	s.productClient.On("Price", mock.Anything, int64(5), testSupplierID).Return(100, nil).Once()
+	s.orderClient.On("CreateOrder", mock.Anything, mock.Anything).Return(testOrderID, nil).Once()
+	s.repo.On("CreateRun", mock.Anything, mock.MatchedBy(func(run *model.Run) bool {
+		return run.Status == model.RunCreated && run.OrderID == testOrderID
+	})).Return(nil).Once()
+	s.repo.On("Advance", mock.Anything, testStandingOrderID, mock.Anything).Return(nil).Once()
+}
+
+func (s *StandingServiceTestSuite) TestRunOne_ChargesGeneratedOrder() {
+	s.expectGenerated()
+	s.paymentClient.On("Charge", "standing-1-order-55", 200, "card-token", mock.Anything).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.runOne(context.Background(), testStandingOrderID, testNow))
+	s.orderClient.AssertNotCalled(s.T(), "CancelOrder", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *StandingServiceTestSuite) TestRunOne_FailedChargeCancelsOrder() {
+	s.expectGenerated()
+	s.paymentClient.On("Charge", "standing-1-order-55", 200, "card-token", mock.Anything).
+		Return(errors.New("card declined")).Once()
+	s.repo.On("FailRun", mock.Anything, testStandingOrderID, testOrderID, "card declined").Return(nil).Once()
+	s.orderClient.On("CancelOrder", mock.Anything, testCustomerID, testOrderID).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.runOne(context.Background(), testStandingOrderID, testNow))
+}
+
+func (s *StandingServiceTestSuite) TestRunOne_TakenByAnotherInstance() {
+	s.repo.On("LockDue", mock.Anything, testStandingOrderID, testNow).Return(nil, model.ErrNoRows).Once()
+
+	s.helper.AssertNoError(s.service.runOne(context.Background(), testStandingOrderID, testNow))
+	s.repo.AssertNotCalled(s.T(), "CreateRun", mock.Anything, mock.Anything)
+}
+
+func (s *StandingServiceTestSuite) TestRunOne_MissingPriceIsRecorded() {
+	s.repo.On("LockDue", mock.Anything, testStandingOrderID, testNow).Return(newTestStandingOrder(), nil).Twice()
+	s.repo.On("Items", mock.Anything, testStandingOrderID).Return([]model.Item{{ProductID: 5, Quantity: 2}}, nil).Once()
+	// a withdrawn offer is not a claim lost to another instance
+	s.productClient.On("Price", mock.Anything, int64(5), testSupplierID).
+		Return(0, fmt.Errorf("offer: %w", model.ErrNoRows)).Once()
+	s.repo.On("CreateRun", mock.Anything, mock.MatchedBy(func(run *model.Run) bool {
+		return run.Status == model.RunFailed && run.Error != ""
+	})).Return(nil).Once()
+	s.repo.On("Advance", mock.Anything, testStandingOrderID, mock.Anything).Return(nil).Once()
+
+	s.helper.AssertNoError(s.service.runOne(context.Background(), testStandingOrderID, testNow))
+	s.orderClient.AssertNotCalled(s.T(), "CreateOrder", mock.Anything, mock.Anything)
+}
+
+func (s *StandingServiceTestSuite) TestCharge_InvoiceIsNotCharged() {
+	order := newTestStandingOrder()
+	order.PaymentMethod = model.PaymentInvoice
+
+	err := s.service.charge(context.Background(), order, testOrderID, &model.GeneratedOrder{})
+
+	s.helper.AssertNoError(err)
+	s.paymentClient.AssertNotCalled(s.T(), "Charge", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+	"diploma/pkg/client/db"
+	"diploma/pkg/service"
+	"time"
+)
+
+type StandingService struct {
+	service.BaseService
+	repo          IStandingRepository
+	productClient IProductClient
+	orderClient   IOrderClient
+	paymentClient IPaymentClient
+	userClient    IUserClient
+	txManager     db.TxManager
+}
+
+func NewService(
+	repo IStandingRepository,
+	productClient IProductClient,
+	orderClient IOrderClient,
+	paymentClient IPaymentClient,
+	userClient IUserClient,
+	txManager db.TxManager,
+) *StandingService {
+	return &StandingService{
+		BaseService:   service.NewBaseService("standing"),
+		repo:          repo,
+		productClient: productClient,
+		orderClient:   orderClient,
+		paymentClient: paymentClient,
+		userClient:    userClient,
+		txManager:     txManager,
+	}
+}
+
+type IStandingRepository interface {
+	CreateStandingOrder(ctx context.Context, order *model.StandingOrder) (int64, error)
+	UpdateStandingOrder(ctx context.Context, order *model.StandingOrder) error
+	SetStatus(ctx context.Context, id int64, status string, nextRunAt time.Time) error
+	SetSkipNext(ctx context.Context, id int64, skip bool) error
+	SetPaymentToken(ctx context.Context, id int64, token string, consentAt time.Time) error
+	Advance(ctx context.Context, id int64, nextRunAt time.Time) error
+	StandingOrderByID(ctx context.Context, id int64) (*model.StandingOrder, error)
+	StandingOrdersByCustomer(ctx context.Context, customerID int64) ([]*model.StandingOrder, error)
+	DueIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error)
+	LockDue(ctx context.Context, id int64, now time.Time) (*model.StandingOrder, error)
+	ReplaceItems(ctx context.Context, standingOrderID int64, items []model.Item) error
+	Items(ctx context.Context, standingOrderID int64) ([]model.Item, error)
+	CreateRun(ctx context.Context, run *model.Run) error
+	FailRun(ctx context.Context, standingOrderID, orderID int64, reason string) error
+	Runs(ctx context.Context, standingOrderID int64, limit, offset uint64) ([]model.Run, error)
+}
+
+type IProductClient interface {
+	Price(ctx context.Context, productID, supplierID int64) (int, error)
+}
+
+type IOrderClient interface {
+	CreateOrder(ctx context.Context, order *model.GeneratedOrder) (int64, error)
+	// CancelOrder cancels a pending order of the customer.
+	CancelOrder(ctx context.Context, customerID, orderID int64) error
+}
+
+type IPaymentClient interface {
+	// Consent starts a card verification that saves the card for recurring
+	// charges and returns the URL the customer is sent to.
+	Consent(paymentOrderID, description string) (string, error)
+	Charge(paymentOrderID string, amount int, token, description string) error
+	VerifyCallback(params map[string]string) bool
+}
+
+type IUserClient interface {
+	DeliveryAddress(ctx context.Context, userID, addressID int64) (string, error)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"diploma/internal/testutils"
+	"diploma/modules/standing/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type mockStandingRepository struct {
+	mock.Mock
+}
+
+func (m *mockStandingRepository) Advance(ctx context.Context, id int64, nextRunAt time.Time) error {
+	args := m.Called(ctx, id, nextRunAt)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) CreateRun(ctx context.Context, run *model.Run) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) CreateStandingOrder(ctx context.Context, order *model.StandingOrder) (int64, error) {
+	args := m.Called(ctx, order)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockStandingRepository) DueIDs(ctx context.Context, now time.Time, limit uint64) ([]int64, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *mockStandingRepository) FailRun(ctx context.Context, standingOrderID, orderID int64, reason string) error {
+	args := m.Called(ctx, standingOrderID, orderID, reason)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) Items(ctx context.Context, standingOrderID int64) ([]model.Item, error) {
+	args := m.Called(ctx, standingOrderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Item), args.Error(1)
+}
+
+func (m *mockStandingRepository) LockDue(ctx context.Context, id int64, now time.Time) (*model.StandingOrder, error) {
+	args := m.Called(ctx, id, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StandingOrder), args.Error(1)
+}
+
+func (m *mockStandingRepository) ReplaceItems(ctx context.Context, standingOrderID int64, items []model.Item) error {
+	args := m.Called(ctx, standingOrderID, items)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) Runs(ctx context.Context, standingOrderID int64, limit, offset uint64) ([]model.Run, error) {
+	args := m.Called(ctx, standingOrderID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]model.Run), args.Error(1)
+}
+
+func (m *mockStandingRepository) SetPaymentToken(ctx context.Context, id int64, token string, consentAt time.Time) error {
+	args := m.Called(ctx, id, token, consentAt)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) SetSkipNext(ctx context.Context, id int64, skip bool) error {
+	args := m.Called(ctx, id, skip)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) SetStatus(ctx context.Context, id int64, status string, nextRunAt time.Time) error {
+	args := m.Called(ctx, id, status, nextRunAt)
+	return args.Error(0)
+}
+
+func (m *mockStandingRepository) StandingOrderByID(ctx context.Context, id int64) (*model.StandingOrder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.StandingOrder), args.Error(1)
+}
+
+func (m *mockStandingRepository) StandingOrdersByCustomer(ctx context.Context, customerID int64) ([]*model.StandingOrder, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.StandingOrder), args.Error(1)
+}
+
+func (m *mockStandingRepository) UpdateStandingOrder(ctx context.Context, order *model.StandingOrder) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+type mockProductClient struct {
+	mock.Mock
+}
+
+func (m *mockProductClient) Price(ctx context.Context, productID, supplierID int64) (int, error) {
+	args := m.Called(ctx, productID, supplierID)
+	return args.Int(0), args.Error(1)
+}
+
+type mockOrderClient struct {
+	mock.Mock
+}
+
+func (m *mockOrderClient) CancelOrder(ctx context.Context, customerID, orderID int64) error {
+	args := m.Called(ctx, customerID, orderID)
+	return args.Error(0)
+}
+
+func (m *mockOrderClient) CreateOrder(ctx context.Context, order *model.GeneratedOrder) (int64, error) {
+	args := m.Called(ctx, order)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockPaymentClient struct {
+	mock.Mock
+}
+
+func (m *mockPaymentClient) Charge(paymentOrderID string, amount int, token, description string) error {
+	args := m.Called(paymentOrderID, amount, token, description)
+	return args.Error(0)
+}
+
+func (m *mockPaymentClient) Consent(paymentOrderID, description string) (string, error) {
+	args := m.Called(paymentOrderID, description)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockPaymentClient) VerifyCallback(params map[string]string) bool {
+	args := m.Called(params)
+	return args.Bool(0)
+}
+
+type StandingServiceTestSuite struct {
+	suite.Suite
+	service       *StandingService
+	repo          *mockStandingRepository
+	productClient *mockProductClient
+	orderClient   *mockOrderClient
+	paymentClient *mockPaymentClient
+	txManager     *testutils.MockTxManager
+	helper        *testutils.AssertTestHelper
+}
+
+func TestStandingService(t *testing.T) {
+	suite.Run(t, new(StandingServiceTestSuite))
+}
+
+func (s *StandingServiceTestSuite) SetupTest() {
+	s.repo = new(mockStandingRepository)
+	s.productClient = new(mockProductClient)
+	s.orderClient = new(mockOrderClient)
+	s.paymentClient = new(mockPaymentClient)
+	s.txManager = new(testutils.MockTxManager)
+	s.helper = testutils.NewAssertTestHelper(s.T())
+
+	s.txManager.On("ReadCommitted", mock.Anything, mock.Anything).Return(nil)
+
+	s.service = NewService(s.repo, s.productClient, s.orderClient, s.paymentClient, nil, s.txManager)
+}
+
+func (s *StandingServiceTestSuite) TearDownTest() {
+	s.repo.AssertExpectations(s.T())
+	s.productClient.AssertExpectations(s.T())
+	s.orderClient.AssertExpectations(s.T())
+	s.paymentClient.AssertExpectations(s.T())
+}
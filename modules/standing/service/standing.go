@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/standing/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+func (s *StandingService) CreateStandingOrder(ctx context.Context, order *model.StandingOrder) (int64, error) {
+	if err := order.Validate(); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		if err := s.prepare(ctx, order); err != nil {
+			return err
+		}
+		order.Status = model.StatusActive
+		order.NextRunAt = order.Schedule.Next(time.Now())
+
+		var errTx error
+		id, errTx = s.repo.CreateStandingOrder(ctx, order)
+		if errTx != nil {
+			return errTx
+		}
+		return s.repo.ReplaceItems(ctx, id, order.Items)
+	})
+	if err != nil {
+		s.LogError(ctx, "Failed to create standing order", err,
+			zap.Int64("customer_id", order.CustomerID),
+			zap.Int64("supplier_id", order.SupplierID),
+		)
+		return 0, err
+	}
+
+	s.LogInfo(ctx, "Standing order created",
+		zap.Int64("standing_order_id", id),
+		zap.Time("next_run_at", order.NextRunAt),
+	)
+	return id, nil
+}
+
+// UpdateStandingOrder replaces lines, schedule, payment method and address
+// of the customer's standing order. The supplier cannot be changed.
+func (s *StandingService) UpdateStandingOrder(ctx context.Context, order *model.StandingOrder) error {
+	if err := order.Validate(); err != nil {
+		return err
+	}
+
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		current, err := s.owned(ctx, order.CustomerID, order.ID)
+		if err != nil {
+			return err
+		}
+		order.SupplierID = current.SupplierID
+
+		if err := s.prepare(ctx, order); err != nil {
+			return err
+		}
+		order.NextRunAt = order.Schedule.Next(time.Now())
+
+		if err := s.repo.UpdateStandingOrder(ctx, order); err != nil {
+			return err
+		}
+		return s.repo.ReplaceItems(ctx, order.ID, order.Items)
+	})
+}
+
+func (s *StandingService) StandingOrder(ctx context.Context, customerID, id int64) (*model.StandingOrder, error) {
+	order, err := s.owned(ctx, customerID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Items, err = s.repo.Items(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *StandingService) StandingOrders(ctx context.Context, customerID int64) ([]*model.StandingOrder, error) {
+	orders, err := s.repo.StandingOrdersByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, order := range orders {
+		order.Items, err = s.repo.Items(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return orders, nil
+}
+
+func (s *StandingService) Pause(ctx context.Context, customerID, id int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.owned(ctx, customerID, id)
+		if err != nil {
+			return err
+		}
+		return s.repo.SetStatus(ctx, id, model.StatusPaused, order.NextRunAt)
+	})
+}
+
+// Resume reactivates a paused standing order. Runs missed while paused are
+// not made up; the schedule continues from now.
+func (s *StandingService) Resume(ctx context.Context, customerID, id int64) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		order, err := s.owned(ctx, customerID, id)
+		if err != nil {
+			return err
+		}
+		return s.repo.SetStatus(ctx, id, model.StatusActive, order.Schedule.Next(time.Now()))
+	})
+}
+
+// SkipNext marks (or unmarks) the next run to be skipped.
+func (s *StandingService) SkipNext(ctx context.Context, customerID, id int64, skip bool) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		if _, err := s.owned(ctx, customerID, id); err != nil {
+			return err
+		}
+		return s.repo.SetSkipNext(ctx, id, skip)
+	})
+}
+
+// History returns the runs of the standing order, newest first.
+func (s *StandingService) History(ctx context.Context, customerID, id int64, limit, offset int) ([]model.Run, error) {
+	if _, err := s.owned(ctx, customerID, id); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	if limit > maxHistoryLimit {
+		limit = maxHistoryLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.Runs(ctx, id, uint64(limit), uint64(offset))
+}
+
+// owned loads the standing order and checks that it belongs to the customer.
+func (s *StandingService) owned(ctx context.Context, customerID, id int64) (*model.StandingOrder, error) {
+	order, err := s.repo.StandingOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID != customerID {
+		return nil, model.ErrForbidden
+	}
+	return order, nil
+}
+
+// prepare checks that every line is offered by the supplier and snapshots
+// the delivery address.
+func (s *StandingService) prepare(ctx context.Context, order *model.StandingOrder) error {
+	for _, item := range order.Items {
+		if _, err := s.productClient.Price(ctx, item.ProductID, order.SupplierID); err != nil {
+			return err
+		}
+	}
+
+	address, err := s.userClient.DeliveryAddress(ctx, order.CustomerID, order.AddressID)
+	if err != nil {
+		return err
+	}
+	order.DeliveryAddress = address
+	return nil
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	modelApi "diploma/modules/supplier/handler/model"
+	"diploma/modules/supplier/model"
+	"fmt"
+	"time"
+)
+
+func ToServiceSlotFromAPI(supplierID int64, req modelApi.CreateSlotRequest) (*model.DeliverySlot, error) {
+	start, err := ParseClock(req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ParseClock(req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.DeliverySlot{
+		SupplierID:  supplierID,
+		Weekday:     time.Weekday(*req.Weekday),
+		StartMinute: start,
+		EndMinute:   end,
+		CutoffHours: req.CutoffHours,
+		Capacity:    req.Capacity,
+	}, nil
+}
+
+// ParseClock converts "HH:MM" to minutes from midnight.
+func ParseClock(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func ToAPISlotsFromService(slots []model.DeliverySlot) []modelApi.Slot {
+	res := make([]modelApi.Slot, 0, len(slots))
+	for _, slot := range slots {
+		res = append(res, modelApi.Slot{
+			ID:          slot.ID,
+			Weekday:     int(slot.Weekday),
+			StartTime:   FormatClock(slot.StartMinute),
+			EndTime:     FormatClock(slot.EndMinute),
+			CutoffHours: slot.CutoffHours,
+			Capacity:    slot.Capacity,
+			IsActive:    slot.IsActive,
+		})
+	}
+	return res
+}
+
+func ToAPIOccurrencesFromService(occurrences []model.SlotOccurrence) []modelApi.SlotOccurrence {
+	res := make([]modelApi.SlotOccurrence, 0, len(occurrences))
+	for _, o := range occurrences {
+		res = append(res, modelApi.SlotOccurrence{
+			SlotID:    o.SlotID,
+			Date:      model.DateKey(o.Date),
+			Start:     o.Start.Format(time.RFC3339),
+			End:       o.End.Format(time.RFC3339),
+			Remaining: o.Remaining,
+		})
+	}
+	return res
+}
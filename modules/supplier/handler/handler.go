@@ -1,5 +1,10 @@
 package handler
 
+import (
+	"context"
+	"diploma/modules/supplier/model"
+)
+
 type SupplierHandler struct {
 	service ISupplierService
 }
@@ -9,4 +14,8 @@ func NewHandler(service ISupplierService) *SupplierHandler {
 }
 
 type ISupplierService interface {
+	CreateSlot(ctx context.Context, slot *model.DeliverySlot) (int64, error)
+	DeactivateSlot(ctx context.Context, supplierID, slotID int64) error
+	Slots(ctx context.Context, supplierID int64) ([]model.DeliverySlot, error)
+	AvailableSlots(ctx context.Context, supplierID int64) ([]model.SlotOccurrence, bool, error)
 }
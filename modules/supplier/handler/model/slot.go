@@ -0,0 +1,50 @@
+package model
+
+import "errors"
+
+var (
+	ErrUnauthorized = errors.New("api: unauthorized")
+)
+
+type ErrorResponse struct {
+	Err string `json:"error"`
+}
+
+type CreateSlotRequest struct {
+	// Weekday is 0 for Sunday through 6 for Saturday.
+	Weekday     *int   `json:"weekday" binding:"required,min=0,max=6"`
+	StartTime   string `json:"start_time" binding:"required" example:"09:00"`
+	EndTime     string `json:"end_time" binding:"required" example:"13:00"`
+	CutoffHours int    `json:"cutoff_hours" binding:"min=0"`
+	Capacity    int    `json:"capacity" binding:"required,min=1"`
+}
+
+type CreateSlotResponse struct {
+	ID int64 `json:"id"`
+}
+
+type Slot struct {
+	ID          int64  `json:"id"`
+	Weekday     int    `json:"weekday"`
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	CutoffHours int    `json:"cutoff_hours"`
+	Capacity    int    `json:"capacity"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type GetSlotsResponse struct {
+	Slots []Slot `json:"slots"`
+}
+
+type SlotOccurrence struct {
+	SlotID    int64  `json:"slot_id"`
+	Date      string `json:"date"`
+	Start     string `json:"start"`
+	End       string `json:"end"`
+	Remaining int    `json:"remaining"`
+}
+
+type GetAvailableSlotsResponse struct {
+	Slots []SlotOccurrence `json:"slots"`
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"diploma/modules/auth/jwt"
+	"diploma/modules/supplier/handler/converter"
+	modelApi "diploma/modules/supplier/handler/model"
+	"diploma/modules/supplier/model"
+	contextkeys "diploma/pkg/context-keys"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateSlot godoc
+// @Summary Create delivery slot
+// @Description Adds a weekly delivery window for the authenticated supplier.
+// @Tags supplier
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param input body modelApi.CreateSlotRequest true "Slot definition"
+// @Success 200 {object} modelApi.CreateSlotResponse
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid slot"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/supplier/slots [post]
+func (h *SupplierHandler) CreateSlot(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+
+	var req modelApi.CreateSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	slot, err := converter.ToServiceSlotFromAPI(claims.UserID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	id, err := h.service.CreateSlot(c.Request.Context(), slot)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidSlot) {
+			c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.CreateSlotResponse{ID: id})
+}
+
+// GetSlots godoc
+// @Summary List own delivery slots
+// @Description Returns all delivery slots of the authenticated supplier, including inactive ones.
+// @Tags supplier
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} modelApi.GetSlotsResponse
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/supplier/slots [get]
+func (h *SupplierHandler) GetSlots(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+
+	slots, err := h.service.Slots(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.GetSlotsResponse{Slots: converter.ToAPISlotsFromService(slots)})
+}
+
+// DeleteSlot godoc
+// @Summary Deactivate delivery slot
+// @Description Stops offering the slot. Existing bookings are kept.
+// @Tags supplier
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Slot ID"
+// @Success 200 {object} map[string]string "slot deactivated"
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid slot ID"
+// @Failure 401 {object} modelApi.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelApi.ErrorResponse "Forbidden"
+// @Failure 404 {object} modelApi.ErrorResponse "Slot not found"
+// @Router /api/supplier/slots/{id} [delete]
+func (h *SupplierHandler) DeleteSlot(c *gin.Context) {
+	claims, ok := supplierClaims(c)
+	if !ok {
+		return
+	}
+
+	slotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid slot ID"})
+		return
+	}
+
+	err = h.service.DeactivateSlot(c.Request.Context(), claims.UserID, slotID)
+	if err != nil {
+		if errors.Is(err, model.ErrNoRows) {
+			c.JSON(http.StatusNotFound, modelApi.ErrorResponse{Err: "slot not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "slot deactivated"})
+}
+
+// GetAvailableSlots godoc
+// @Summary Available delivery slots
+// @Description Returns the supplier's delivery slots that can still be booked in the coming days.
+// @Tags supplier
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Success 200 {object} modelApi.GetAvailableSlotsResponse
+// @Failure 400 {object} modelApi.ErrorResponse "Invalid supplier ID"
+// @Failure 500 {object} modelApi.ErrorResponse "Internal server error"
+// @Router /api/supplier/{id}/slots [get]
+func (h *SupplierHandler) GetAvailableSlots(c *gin.Context) {
+	supplierID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, modelApi.ErrorResponse{Err: "invalid supplier ID"})
+		return
+	}
+
+	slots, _, err := h.service.AvailableSlots(c.Request.Context(), supplierID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, modelApi.ErrorResponse{Err: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, modelApi.GetAvailableSlotsResponse{Slots: converter.ToAPIOccurrencesFromService(slots)})
+}
+
+// supplierClaims returns the claims of an authenticated supplier, writing
+// the error response otherwise.
+func supplierClaims(c *gin.Context) (*jwt.Claims, bool) {
+	claims, ok := c.Request.Context().Value(contextkeys.UserKey).(*jwt.Claims)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, modelApi.ErrorResponse{Err: modelApi.ErrUnauthorized.Error()})
+		return nil, false
+	}
+	if claims.Role != model.SupplierRole {
+		c.JSON(http.StatusForbidden, modelApi.ErrorResponse{Err: "only suppliers can manage delivery slots"})
+		return nil, false
+	}
+	return claims, true
+}
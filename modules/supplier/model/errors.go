@@ -0,0 +1,13 @@
+package model
+
+import "errors"
+
+var (
+	ErrNoRows = errors.New("models: no rows")
+
+	ErrInvalidSlot = errors.New("models: invalid delivery slot")
+
+	ErrSlotUnavailable = errors.New("models: delivery slot is not available for this date")
+
+	ErrSlotFull = errors.New("models: delivery slot is fully booked")
+)
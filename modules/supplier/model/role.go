@@ -0,0 +1,7 @@
+package model
+
+const (
+	CustomerRole = iota
+	SupplierRole
+	AdminRole
+)
@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// DeliverySlot is a weekly delivery window of a supplier.
+type DeliverySlot struct {
+	ID         int64
+	SupplierID int64
+	Weekday    time.Weekday
+	// StartMinute and EndMinute are minutes from midnight.
+	StartMinute int
+	EndMinute   int
+	// CutoffHours is how long before the window starts orders stop being
+	// accepted into it.
+	CutoffHours int
+	Capacity    int
+	IsActive    bool
+}
+
+// Validate checks the slot definition.
+func (s *DeliverySlot) Validate() error {
+	if s.Weekday < time.Sunday || s.Weekday > time.Saturday {
+		return ErrInvalidSlot
+	}
+	if s.StartMinute < 0 || s.EndMinute > 24*60 || s.EndMinute <= s.StartMinute {
+		return ErrInvalidSlot
+	}
+	if s.CutoffHours < 0 || s.Capacity <= 0 {
+		return ErrInvalidSlot
+	}
+	return nil
+}
+
+// On returns the occurrence of the slot on the given day.
+func (s *DeliverySlot) On(day time.Time) SlotOccurrence {
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	return SlotOccurrence{
+		SlotID:     s.ID,
+		SupplierID: s.SupplierID,
+		Date:       date,
+		Start:      date.Add(time.Duration(s.StartMinute) * time.Minute),
+		End:        date.Add(time.Duration(s.EndMinute) * time.Minute),
+		Remaining:  s.Capacity,
+	}
+}
+
+// Cutoff is the last moment an order can be placed into the occurrence.
+func (s *DeliverySlot) Cutoff(o SlotOccurrence) time.Time {
+	return o.Start.Add(-time.Duration(s.CutoffHours) * time.Hour)
+}
+
+// SlotOccurrence is a delivery slot on a concrete date.
+type SlotOccurrence struct {
+	SlotID     int64
+	SupplierID int64
+	Date       time.Time
+	Start      time.Time
+	End        time.Time
+	Remaining  int
+}
+
+// DateKey formats a delivery date the way it is passed to and from the API
+// and the database.
+func DateKey(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// SlotHold is a place in a slot held for a checkout.
+type SlotHold struct {
+	SlotID   int64
+	Date     time.Time
+	Released bool // the hold expired or was released and its place given back
+}
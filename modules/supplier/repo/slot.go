@@ -0,0 +1,343 @@
+package repository
+
+import (
+	"context"
+	"diploma/modules/supplier/model"
+	"diploma/pkg/client/db"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// ======== delivery slots ========
+	deliverySlotTbl    = "delivery_slots"
+	dsIDCol            = "id"
+	dsSupplierIDCol    = "supplier_id"
+	dsWeekdayCol       = "weekday"
+	dsStartMinuteCol   = "start_minute"
+	dsEndMinuteCol     = "end_minute"
+	dsCutoffHoursCol   = "cutoff_hours"
+	dsCapacityCol      = "capacity"
+	dsIsActiveCol      = "is_active"
+	slotReservationTbl = "delivery_slot_reservations"
+	srSlotIDCol        = "slot_id"
+	srDateCol          = "delivery_date"
+	srReservedCol      = "reserved"
+	slotHoldTbl        = "delivery_slot_holds"
+	shCheckoutIDCol    = "checkout_id"
+	shSlotIDCol        = "slot_id"
+	shDateCol          = "delivery_date"
+	shExpiresAtCol     = "expires_at"
+	shConfirmedAtCol   = "confirmed_at"
+	shReleasedCol      = "released"
+)
+
+var slotColumns = []string{
+	dsIDCol, dsSupplierIDCol, dsWeekdayCol, dsStartMinuteCol, dsEndMinuteCol, dsCutoffHoursCol, dsCapacityCol, dsIsActiveCol,
+}
+
+func (r *supplierRepo) CreateSlot(ctx context.Context, slot *model.DeliverySlot) (int64, error) {
+	builder := sq.
+		Insert(deliverySlotTbl).
+		Columns(dsSupplierIDCol, dsWeekdayCol, dsStartMinuteCol, dsEndMinuteCol, dsCutoffHoursCol, dsCapacityCol).
+		Values(slot.SupplierID, int(slot.Weekday), slot.StartMinute, slot.EndMinute, slot.CutoffHours, slot.Capacity).
+		Suffix("RETURNING " + dsIDCol).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.CreateSlot",
+		QueryRaw: query,
+	}
+
+	var id int64
+	if err := r.db.DB().QueryRowContext(ctx, q, args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// DeactivateSlot hides the slot from new checkouts. Existing reservations
+// and orders keep pointing at it.
+func (r *supplierRepo) DeactivateSlot(ctx context.Context, supplierID, slotID int64) error {
+	builder := sq.
+		Update(deliverySlotTbl).
+		Set(dsIsActiveCol, false).
+		Where(sq.Eq{dsIDCol: slotID, dsSupplierIDCol: supplierID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.DeactivateSlot",
+		QueryRaw: query,
+	}
+
+	tag, err := r.db.DB().ExecContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return model.ErrNoRows
+	}
+	return nil
+}
+
+func (r *supplierRepo) SlotsBySupplier(ctx context.Context, supplierID int64, onlyActive bool) ([]model.DeliverySlot, error) {
+	builder := sq.
+		Select(slotColumns...).
+		From(deliverySlotTbl).
+		Where(sq.Eq{dsSupplierIDCol: supplierID}).
+		OrderBy(dsWeekdayCol, dsStartMinuteCol).
+		PlaceholderFormat(sq.Dollar)
+	if onlyActive {
+		builder = builder.Where(sq.Eq{dsIsActiveCol: true})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.SlotsBySupplier",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var slots []model.DeliverySlot
+	for rows.Next() {
+		slot, err := scanSlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		slots = append(slots, slot)
+	}
+	return slots, rows.Err()
+}
+
+func (r *supplierRepo) SlotByID(ctx context.Context, slotID int64) (model.DeliverySlot, error) {
+	builder := sq.
+		Select(slotColumns...).
+		From(deliverySlotTbl).
+		Where(sq.Eq{dsIDCol: slotID}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return model.DeliverySlot{}, err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.SlotByID",
+		QueryRaw: query,
+	}
+
+	slot, err := scanSlot(r.db.DB().QueryRowContext(ctx, q, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.DeliverySlot{}, model.ErrNoRows
+	}
+	return slot, err
+}
+
+func scanSlot(row pgx.Row) (model.DeliverySlot, error) {
+	var (
+		slot    model.DeliverySlot
+		weekday int
+	)
+	err := row.Scan(
+		&slot.ID,
+		&slot.SupplierID,
+		&weekday,
+		&slot.StartMinute,
+		&slot.EndMinute,
+		&slot.CutoffHours,
+		&slot.Capacity,
+		&slot.IsActive,
+	)
+	slot.Weekday = time.Weekday(weekday)
+	return slot, err
+}
+
+// ReservedCounts returns the number of reservations per slot and date
+// (formatted with model.DateKey) for the supplier in [from, to].
+func (r *supplierRepo) ReservedCounts(ctx context.Context, supplierID int64, from, to time.Time) (map[int64]map[string]int, error) {
+	builder := sq.
+		Select("sr."+srSlotIDCol, "to_char(sr."+srDateCol+", 'YYYY-MM-DD')", "sr."+srReservedCol).
+		From(slotReservationTbl + " sr").
+		Join(deliverySlotTbl + " ds ON ds." + dsIDCol + " = sr." + srSlotIDCol).
+		Where(sq.Eq{"ds." + dsSupplierIDCol: supplierID}).
+		Where(sq.Expr("sr."+srDateCol+" BETWEEN ?::date AND ?::date", model.DateKey(from), model.DateKey(to))).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.ReservedCounts",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int64]map[string]int)
+	for rows.Next() {
+		var (
+			slotID   int64
+			date     string
+			reserved int
+		)
+		if err := rows.Scan(&slotID, &date, &reserved); err != nil {
+			return nil, err
+		}
+		if counts[slotID] == nil {
+			counts[slotID] = make(map[string]int)
+		}
+		counts[slotID][date] = reserved
+	}
+	return counts, rows.Err()
+}
+
+// ReserveSlot books one place in the slot on the date and returns the
+// number of places left. The increment and the capacity check happen in a
+// single statement on a locked row, so concurrent checkouts cannot
+// overbook; model.ErrSlotFull is returned when no place is left.
+func (r *supplierRepo) ReserveSlot(ctx context.Context, slotID int64, date time.Time) (int, error) {
+	q := db.Query{
+		Name: "supplier_repository.ReserveSlot",
+		QueryRaw: `INSERT INTO ` + slotReservationTbl + ` AS sr (slot_id, delivery_date, reserved)
+			SELECT ds.id, $2::date, 1 FROM ` + deliverySlotTbl + ` ds WHERE ds.id = $1
+			ON CONFLICT (slot_id, delivery_date) DO UPDATE SET reserved = sr.reserved + 1
+			WHERE sr.reserved < (SELECT capacity FROM ` + deliverySlotTbl + ` WHERE id = $1)
+			RETURNING (SELECT capacity FROM ` + deliverySlotTbl + ` WHERE id = $1) - sr.reserved`,
+	}
+
+	var remaining int
+	err := r.db.DB().QueryRowContext(ctx, q, slotID, model.DateKey(date)).Scan(&remaining)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, model.ErrSlotFull
+	}
+	if err != nil {
+		return 0, err
+	}
+	return remaining, nil
+}
+
+// HoldSlot records that a place reserved with ReserveSlot belongs to the
+// checkout and is given back at expiresAt unless confirmed.
+func (r *supplierRepo) HoldSlot(ctx context.Context, checkoutID string, slotID int64, date time.Time, expiresAt time.Time) error {
+	builder := sq.
+		Insert(slotHoldTbl).
+		Columns(shCheckoutIDCol, shSlotIDCol, shDateCol, shExpiresAtCol).
+		Values(checkoutID, slotID, sq.Expr("?::date", model.DateKey(date)), expiresAt).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.HoldSlot",
+		QueryRaw: query,
+	}
+
+	_, err = r.db.DB().ExecContext(ctx, q, args...)
+	return err
+}
+
+// ConfirmSlotHolds makes the checkout's holds permanent and returns them.
+// Holds already released still report Released; their places are no
+// longer reserved.
+func (r *supplierRepo) ConfirmSlotHolds(ctx context.Context, checkoutID string) ([]model.SlotHold, error) {
+	builder := sq.
+		Update(slotHoldTbl).
+		Set(shConfirmedAtCol, sq.Expr("now()")).
+		Where(sq.Eq{shCheckoutIDCol: checkoutID}).
+		Where(sq.Eq{shConfirmedAtCol: nil}).
+		Suffix("RETURNING " + shSlotIDCol + ", " + shDateCol + ", " + shReleasedCol).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	q := db.Query{
+		Name:     "supplier_repository.ConfirmSlotHolds",
+		QueryRaw: query,
+	}
+
+	rows, err := r.db.DB().QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []model.SlotHold
+	for rows.Next() {
+		var hold model.SlotHold
+		if err := rows.Scan(&hold.SlotID, &hold.Date, &hold.Released); err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+// ReleaseSlotHolds gives back the places of the checkout's open holds.
+func (r *supplierRepo) ReleaseSlotHolds(ctx context.Context, checkoutID string) error {
+	_, err := r.releaseHolds(ctx, "supplier_repository.ReleaseSlotHolds", shCheckoutIDCol+" = $1", checkoutID)
+	return err
+}
+
+// ReleaseExpiredSlotHolds gives back the places of open holds that expired
+// before now and returns the number of reservations changed.
+func (r *supplierRepo) ReleaseExpiredSlotHolds(ctx context.Context, now time.Time) (int64, error) {
+	return r.releaseHolds(ctx, "supplier_repository.ReleaseExpiredSlotHolds", shExpiresAtCol+" < $1", now)
+}
+
+// releaseHolds marks the open holds matching cond released and decrements
+// their reservations in the same statement.
+func (r *supplierRepo) releaseHolds(ctx context.Context, name, cond string, arg interface{}) (int64, error) {
+	q := db.Query{
+		Name: name,
+		QueryRaw: `WITH released AS (
+				UPDATE ` + slotHoldTbl + ` SET released = TRUE
+				WHERE ` + cond + ` AND confirmed_at IS NULL AND NOT released
+				RETURNING slot_id, delivery_date
+			), counts AS (
+				SELECT slot_id, delivery_date, COUNT(*) AS n FROM released GROUP BY slot_id, delivery_date
+			)
+			UPDATE ` + slotReservationTbl + ` AS sr SET reserved = GREATEST(sr.reserved - c.n, 0)
+			FROM counts c
+			WHERE sr.slot_id = c.slot_id AND sr.delivery_date = c.delivery_date`,
+	}
+
+	tag, err := r.db.DB().ExecContext(ctx, q, arg)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -1,9 +1,17 @@
 package supplier
 
-// func RegisterRoutes(router *gin.RouterGroup, h *handler.AuthHandler) {
-// 	authRoutes := router.Group("/auth")
-// 	{
-// 		authRoutes.POST("/register", h.Register)
-// 		authRoutes.POST("/login", h.Login)
-// 	}
-// }
+import (
+	"diploma/modules/supplier/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, h *handler.SupplierHandler) {
+	supplierRoutes := router.Group("/supplier")
+	{
+		supplierRoutes.GET("/slots", h.GetSlots)
+		supplierRoutes.POST("/slots", h.CreateSlot)
+		supplierRoutes.DELETE("/slots/:id", h.DeleteSlot)
+		supplierRoutes.GET("/:id/slots", h.GetAvailableSlots)
+	}
+}
@@ -4,21 +4,29 @@ import (
 	"context"
 	"diploma/modules/supplier/model"
 	"diploma/pkg/client/db"
+	"diploma/pkg/service"
+	"time"
 )
 
 type SupplierService struct {
+	service.BaseService
 	supplierRepo ISupplierRepository
 	txManager    db.TxManager
+	// holdTTL is how long a place reserved at checkout waits for payment.
+	holdTTL time.Duration
 }
 
-func NewService(repo ISupplierRepository, tx db.TxManager) *SupplierService {
+func NewService(repo ISupplierRepository, tx db.TxManager, holdTTL time.Duration) *SupplierService {
 	return &SupplierService{
-			supplierRepo: repo,
+		BaseService:  service.NewBaseService("supplier"),
+		supplierRepo: repo,
 		txManager:    tx,
+		holdTTL:      holdTTL,
 	}
 
 }
 
 type ISupplierRepository interface {
 	SupplierListByIDList(ctx context.Context, id []int64) ([]model.Supplier, error)
+	ISlotRepository
 }
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"diploma/modules/supplier/model"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SlotHorizonDays is how many days ahead delivery slots are offered.
+const SlotHorizonDays = 14
+
+type ISlotRepository interface {
+	CreateSlot(ctx context.Context, slot *model.DeliverySlot) (int64, error)
+	DeactivateSlot(ctx context.Context, supplierID, slotID int64) error
+	SlotsBySupplier(ctx context.Context, supplierID int64, onlyActive bool) ([]model.DeliverySlot, error)
+	SlotByID(ctx context.Context, slotID int64) (model.DeliverySlot, error)
+	ReservedCounts(ctx context.Context, supplierID int64, from, to time.Time) (map[int64]map[string]int, error)
+	ReserveSlot(ctx context.Context, slotID int64, date time.Time) (int, error)
+	HoldSlot(ctx context.Context, checkoutID string, slotID int64, date time.Time, expiresAt time.Time) error
+	ConfirmSlotHolds(ctx context.Context, checkoutID string) ([]model.SlotHold, error)
+	ReleaseSlotHolds(ctx context.Context, checkoutID string) error
+	ReleaseExpiredSlotHolds(ctx context.Context, now time.Time) (int64, error)
+}
+
+func (s *SupplierService) CreateSlot(ctx context.Context, slot *model.DeliverySlot) (int64, error) {
+	if err := slot.Validate(); err != nil {
+		return 0, err
+	}
+	return s.supplierRepo.CreateSlot(ctx, slot)
+}
+
+func (s *SupplierService) DeactivateSlot(ctx context.Context, supplierID, slotID int64) error {
+	return s.supplierRepo.DeactivateSlot(ctx, supplierID, slotID)
+}
+
+func (s *SupplierService) Slots(ctx context.Context, supplierID int64) ([]model.DeliverySlot, error) {
+	return s.supplierRepo.SlotsBySupplier(ctx, supplierID, false)
+}
+
+// AvailableSlots lists the slot occurrences of the supplier that can still
+// be booked within the next SlotHorizonDays days. uses reports whether the
+// supplier delivers by slots at all; when it does not, orders get the
+// default delivery date.
+func (s *SupplierService) AvailableSlots(ctx context.Context, supplierID int64) (occurrences []model.SlotOccurrence, uses bool, err error) {
+	err = s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		slots, errTx := s.supplierRepo.SlotsBySupplier(ctx, supplierID, true)
+		if errTx != nil {
+			return errTx
+		}
+		if len(slots) == 0 {
+			return nil
+		}
+		uses = true
+
+		now := time.Now()
+		from := now
+		to := now.AddDate(0, 0, SlotHorizonDays)
+		reserved, errTx := s.supplierRepo.ReservedCounts(ctx, supplierID, from, to)
+		if errTx != nil {
+			return errTx
+		}
+
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			for i := range slots {
+				slot := &slots[i]
+				if slot.Weekday != day.Weekday() {
+					continue
+				}
+				o := slot.On(day)
+				if now.After(slot.Cutoff(o)) {
+					continue
+				}
+				o.Remaining -= reserved[slot.ID][model.DateKey(o.Date)]
+				if o.Remaining <= 0 {
+					continue
+				}
+				occurrences = append(occurrences, o)
+			}
+		}
+		return nil
+	})
+	return occurrences, uses, err
+}
+
+// ReserveSlot books one place in the supplier's slot on the date and holds
+// it for the checkout until ConfirmSlots or ReleaseSlots, or until the hold
+// expires. It should run in the same transaction that creates the
+// checkout, so the place is given back if that fails.
+func (s *SupplierService) ReserveSlot(ctx context.Context, supplierID, slotID int64, date time.Time, checkoutID string) (model.SlotOccurrence, error) {
+	var occurrence model.SlotOccurrence
+	err := s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		slot, errTx := s.supplierRepo.SlotByID(ctx, slotID)
+		if errTx != nil {
+			return errTx
+		}
+
+		if slot.SupplierID != supplierID || !slot.IsActive || slot.Weekday != date.Weekday() {
+			return model.ErrSlotUnavailable
+		}
+
+		occurrence = slot.On(date)
+		if time.Now().After(slot.Cutoff(occurrence)) ||
+			occurrence.Date.After(time.Now().AddDate(0, 0, SlotHorizonDays)) {
+			return model.ErrSlotUnavailable
+		}
+
+		occurrence.Remaining, errTx = s.supplierRepo.ReserveSlot(ctx, slotID, occurrence.Date)
+		if errTx != nil {
+			return errTx
+		}
+		return s.supplierRepo.HoldSlot(ctx, checkoutID, slotID, occurrence.Date, time.Now().Add(s.holdTTL))
+	})
+	if err != nil {
+		return model.SlotOccurrence{}, err
+	}
+	return occurrence, nil
+}
+
+// ConfirmSlots keeps the places held for a paid checkout. A place whose
+// hold already expired is reserved again; model.ErrSlotFull is returned
+// when it was taken meanwhile.
+func (s *SupplierService) ConfirmSlots(ctx context.Context, checkoutID string) error {
+	return s.txManager.ReadCommitted(ctx, func(ctx context.Context) error {
+		holds, errTx := s.supplierRepo.ConfirmSlotHolds(ctx, checkoutID)
+		if errTx != nil {
+			return errTx
+		}
+		for _, hold := range holds {
+			if !hold.Released {
+				continue
+			}
+			if _, errTx = s.supplierRepo.ReserveSlot(ctx, hold.SlotID, hold.Date); errTx != nil {
+				return errTx
+			}
+		}
+		return nil
+	})
+}
+
+// ReleaseSlots gives back the places held for a checkout.
+func (s *SupplierService) ReleaseSlots(ctx context.Context, checkoutID string) error {
+	return s.supplierRepo.ReleaseSlotHolds(ctx, checkoutID)
+}
+
+// StartHoldExpiry gives back the places of expired checkout holds every
+// interval until ctx is cancelled.
+func (s *SupplierService) StartHoldExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		released, err := s.supplierRepo.ReleaseExpiredSlotHolds(ctx, time.Now())
+		if err != nil {
+			s.LogError(ctx, "Failed to release expired slot holds", err)
+		} else if released > 0 {
+			s.LogInfo(ctx, "Released expired slot holds", zap.Int64("reservations", released))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 type IAddressService interface {
 	SetAddress(ctx context.Context, input model.Address) (int64, error)
 	Address(ctx context.Context, userID int64) ([]model.Address, error)
+	AddressByID(ctx context.Context, userID, addressID int64) (model.Address, error)
 }
 
 // Register godoc
@@ -15,6 +15,7 @@ func ToServiceAddressFromApi(userID int64, input modelApi.Address) model.Address
 
 func ToApiAddressFromService(input model.Address) modelApi.Address {
 	return modelApi.Address{
+		ID:          input.ID,
 		Street:      input.Street,
 		Description: input.Description,
 	}
@@ -15,6 +15,7 @@ type GetAddressResponse struct {
 }
 
 type Address struct {
+	ID          int64  `json:"id,omitempty"`
 	Street      string `json:"street"`
 	Description string `json:"description"`
 }
@@ -8,4 +8,6 @@ var (
 	ErrDuplicateNumber = errors.New("models: duplicate email")
 
 	ErrNoRows = errors.New("models: no rows")
+
+	ErrAddressNotFound = errors.New("models: address not found")
 )
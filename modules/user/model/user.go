@@ -20,3 +20,11 @@ type Address struct {
 	Description string
 	UserID      int64
 }
+
+// FullText is the address as printed on orders and documents.
+func (a Address) FullText() string {
+	if a.Description == "" {
+		return a.Street
+	}
+	return a.Street + ", " + a.Description
+}
@@ -2,13 +2,17 @@ package user
 
 import (
 	"context"
+	"errors"
 
 	"diploma/modules/user/model"
+
+	"github.com/jackc/pgx/v4"
 )
 
 type IAddressRepo interface {
 	CreateAddress(ctx context.Context, address model.Address) (int64, error)
 	AddressByUserId(ctx context.Context, userID int64) ([]model.Address, error)
+	GetById(ctx context.Context, id int64) (*model.Address, error)
 	// Update(ctx context.Context, address *model.Address) error
 	Delete(ctx context.Context, addressID int64) error
 }
@@ -52,3 +56,21 @@ func (s *userServ) Address(ctx context.Context, userID int64) ([]model.Address,
 
 	return addresList, nil
 }
+
+// AddressByID returns the address if it belongs to the user, and
+// model.ErrAddressNotFound otherwise.
+func (s *userServ) AddressByID(ctx context.Context, userID, addressID int64) (model.Address, error) {
+	addr, err := s.userRepository.GetById(ctx, addressID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Address{}, model.ErrAddressNotFound
+	}
+	if err != nil {
+		return model.Address{}, err
+	}
+
+	if addr.UserID != userID {
+		return model.Address{}, model.ErrAddressNotFound
+	}
+
+	return *addr, nil
+}
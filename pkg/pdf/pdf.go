@@ -0,0 +1,60 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	FontFamily = "DejaVu"
+
+	regularFontFile = "DejaVuSans.ttf"
+	boldFontFile    = "DejaVuSans-Bold.ttf"
+)
+
+// Document is an A4 portrait document with a UTF-8 font registered,
+// so it can print Cyrillic and Kazakh text.
+type Document struct {
+	*fpdf.Fpdf
+}
+
+// New creates an empty document using the fonts found in fontDir.
+func New(fontDir string) (*Document, error) {
+	f := fpdf.New("P", "mm", "A4", fontDir)
+	f.AddUTF8Font(FontFamily, "", regularFontFile)
+	f.AddUTF8Font(FontFamily, "B", boldFontFile)
+	if err := f.Error(); err != nil {
+		return nil, fmt.Errorf("load pdf fonts from %s: %w", fontDir, err)
+	}
+
+	f.SetMargins(15, 15, 15)
+	f.SetAutoPageBreak(true, 15)
+	f.SetFont(FontFamily, "", 10)
+
+	return &Document{Fpdf: f}, nil
+}
+
+// SetFixedDates pins the creation and modification dates so the same input
+// always produces byte-identical output.
+func (d *Document) SetFixedDates(t time.Time) {
+	d.SetCreationDate(t)
+	d.SetModificationDate(t)
+	d.SetCatalogSort(true)
+}
+
+// Bytes renders the document.
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Money formats an amount in tenge.
+func Money(amount int) string {
+	return fmt.Sprintf("%d ₸", amount)
+}